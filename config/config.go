@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +17,41 @@ type Config struct {
 	DBName     string
 	JWTSecret  string
 	Port       string
+
+	// How long a reserved ticket holds its seat before it is released
+	TicketHoldDuration time.Duration
+	// How often background jobs look for expired holds
+	HoldSweepInterval time.Duration
+	// How often events are moved to ongoing and finished
+	EventLifecycleInterval time.Duration
+	// How long a waitlisted user has to claim a freed seat
+	WaitlistClaimDuration time.Duration
+	// How long the response to a request with an Idempotency-Key is kept for retries
+	IdempotencyKeyTTL time.Duration
+
+	// Currency that prices and payments are charged in
+	Currency string
+	// Service fee charged on top of the price of every ticket
+	TicketServiceFee float64
+	// Payment provider tickets are charged through, only "mock" for now
+	PaymentGateway string
+	// Shared secret used to verify payment gateway webhooks
+	PaymentWebhookSecret string
+	// Secret used to sign the ticket credentials in QR codes, kept apart from JWTSecret
+	TicketSigningSecret string
+
+	// Where uploaded files are kept, "local" disk or an "s3" compatible bucket
+	StorageDriver string
+	// Directory the local driver keeps files in
+	StorageLocalDir string
+	// S3 compatible endpoint, e.g. https://s3.amazonaws.com or http://localhost:9000 for MinIO
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	// How long presigned download URLs stay valid
+	StorageURLExpiry time.Duration
 }
 
 var AppConfig Config
@@ -33,11 +70,60 @@ func LoadConfig() error {
 		DBName:     os.Getenv("DB_NAME"),
 		JWTSecret:  os.Getenv("JWT_SECRET"),
 		Port:       os.Getenv("PORT"),
+
+		TicketHoldDuration:     getDurationEnv("TICKET_HOLD_DURATION", 10*time.Minute),
+		HoldSweepInterval:      getDurationEnv("HOLD_SWEEP_INTERVAL", time.Minute),
+		EventLifecycleInterval: getDurationEnv("EVENT_LIFECYCLE_INTERVAL", time.Minute),
+		WaitlistClaimDuration:  getDurationEnv("WAITLIST_CLAIM_DURATION", 30*time.Minute),
+		IdempotencyKeyTTL:      getDurationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+
+		Currency:             getEnv("CURRENCY", "IDR"),
+		TicketServiceFee:     getFloatEnv("TICKET_SERVICE_FEE", 0),
+		PaymentGateway:       os.Getenv("PAYMENT_GATEWAY"),
+		PaymentWebhookSecret: os.Getenv("PAYMENT_WEBHOOK_SECRET"),
+		TicketSigningSecret:  os.Getenv("TICKET_SIGNING_SECRET"),
+
+		StorageDriver:    getEnv("STORAGE_DRIVER", "local"),
+		StorageLocalDir:  getEnv("STORAGE_LOCAL_DIR", "uploads"),
+		S3Endpoint:       os.Getenv("S3_ENDPOINT"),
+		S3Region:         getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:         os.Getenv("S3_BUCKET"),
+		S3AccessKey:      os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:      os.Getenv("S3_SECRET_KEY"),
+		StorageURLExpiry: getDurationEnv("STORAGE_URL_EXPIRY", 15*time.Minute),
 	}
 
 	return nil
 }
 
+// getEnv reads a string from the environment, falling back to the default when it is missing
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getDurationEnv reads a duration such as "10m" from the environment,
+// falling back to the default when it is missing or invalid
+func getDurationEnv(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// getFloatEnv reads a non-negative number from the environment, falling back
+// to the default when it is missing or invalid
+func getFloatEnv(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
 func GetDSN() string {
 	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 		AppConfig.DBUser, AppConfig.DBPassword, AppConfig.DBHost, AppConfig.DBPort, AppConfig.DBName)
@@ -25,17 +25,88 @@ func ConnectDatabase() {
 	fmt.Println("Connected to database")
 	DB = db
 
+	// Event names stopped being unique when occurrences of a series started
+	// sharing theirs. Older schemas carry the constraint under either name.
+	for _, index := range []string{"uni_events_name", "name"} {
+		if DB.Migrator().HasIndex(&entity.Event{}, index) {
+			if err := DB.Migrator().DropIndex(&entity.Event{}, index); err != nil {
+				log.Fatalf("Failed to drop the unique event name index: %v", err)
+			}
+		}
+	}
+
 	// Auto migrate the database
 	err = DB.AutoMigrate(
 		&entity.User{},
 		&entity.Event{},
 		&entity.Ticket{},
 		&entity.AuditLog{},
+		&entity.Order{},
+		&entity.OrderItem{},
+		&entity.TicketTier{},
+		&entity.Payment{},
+		&entity.Refund{},
+		&entity.CancellationPolicyRule{},
+		&entity.WaitlistEntry{},
+		&entity.TicketTransfer{},
+		&entity.PromoCode{},
+		&entity.IdempotencyKey{},
+		&entity.Venue{},
+		&entity.SeatMap{},
+		&entity.Seat{},
+		&entity.SeatReservation{},
+		&entity.EventSeries{},
+		&entity.Category{},
+		&entity.Tag{},
+		&entity.Media{},
 	)
 
 	if err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// Bring the denormalised sold counters in line with the ticket table
+	err = DB.Exec(
+		"UPDATE events SET sold_count = (SELECT COUNT(*) FROM tickets WHERE tickets.event_id = events.id AND tickets.status IN ?)",
+		entity.SeatTakingStatuses,
+	).Error
+
+	if err == nil {
+		err = DB.Exec(
+			"UPDATE ticket_tiers SET sold_count = (SELECT COUNT(*) FROM tickets WHERE tickets.tier_id = ticket_tiers.id AND tickets.status IN ?)",
+			entity.SeatTakingStatuses,
+		).Error
+	}
+
+	if err != nil {
+		log.Fatalf("Failed to sync event sold counts: %v", err)
+	}
+
+	// Tickets sold before prices were recorded on the ticket are valued at the
+	// current list price, once
+	err = DB.Exec(
+		"UPDATE tickets SET unit_price = CASE WHEN price_paid + discount > 0 THEN price_paid + discount " +
+			"ELSE COALESCE((SELECT price FROM ticket_tiers WHERE ticket_tiers.id = tickets.tier_id), " +
+			"(SELECT price FROM events WHERE events.id = tickets.event_id), 0) END WHERE currency = ''",
+	).Error
+
+	if err == nil {
+		err = DB.Exec("UPDATE tickets SET price_paid = unit_price - discount WHERE currency = '' AND price_paid = 0").Error
+	}
+
+	if err == nil {
+		err = DB.Exec("UPDATE tickets SET currency = ? WHERE currency = ''", AppConfig.Currency).Error
+	}
+
+	if err != nil {
+		log.Fatalf("Failed to record ticket prices: %v", err)
+	}
+
+	// Standalone events created before their names were indexed, once
+	err = DB.Exec("UPDATE events SET standalone_name = LOWER(name) WHERE series_id IS NULL AND standalone_name IS NULL").Error
+	if err != nil {
+		log.Fatalf("Failed to index standalone event names: %v", err)
+	}
+
 	fmt.Println("Database migration successful")
 } 
\ No newline at end of file
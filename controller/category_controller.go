@@ -0,0 +1,613 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+type CategoryController interface {
+	GetAllCategories(c *gin.Context)
+	GetCategoryByID(c *gin.Context)
+	CreateCategory(c *gin.Context)
+	UpdateCategory(c *gin.Context)
+	DeleteCategory(c *gin.Context)
+	GetAllTags(c *gin.Context)
+	CreateTag(c *gin.Context)
+	UpdateTag(c *gin.Context)
+	DeleteTag(c *gin.Context)
+	SetEventCategories(c *gin.Context)
+	SetEventTags(c *gin.Context)
+}
+
+type categoryController struct {
+	categoryService service.CategoryService
+	auditService    service.AuditService
+}
+
+func NewCategoryController(categoryService service.CategoryService, auditService service.AuditService) CategoryController {
+	return &categoryController{
+		categoryService: categoryService,
+		auditService:    auditService,
+	}
+}
+
+// GetAllCategories godoc
+// @Summary Get all categories
+// @Description Get every event category ordered by name, with the number of upcoming public events in each
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Success 200 {array} entity.Category
+// @Failure 500 {object} map[string]interface{}
+// @Router /categories [get]
+func (ctrl *categoryController) GetAllCategories(c *gin.Context) {
+	categories, err := ctrl.categoryService.GetAllCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": categories})
+}
+
+// GetCategoryByID godoc
+// @Summary Get category by ID
+// @Description Get the name, slug and description of a category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} entity.Category
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /categories/{id} [get]
+func (ctrl *categoryController) GetCategoryByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	category, err := ctrl.categoryService.GetCategoryByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// CreateCategory godoc
+// @Summary Create a category
+// @Description Create an event category. Its slug, used to filter events, is derived from the name.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category body entity.Category true "Category Data"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /categories [post]
+func (ctrl *categoryController) CreateCategory(c *gin.Context) {
+	var category entity.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err := ctrl.categoryService.CreateCategory(&category)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log category creation in the audit trail
+	newCategory, _ := json.Marshal(category)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionCreate,
+		"category",
+		category.ID,
+		nil,
+		string(newCategory),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Category created successfully", "category": category})
+}
+
+// UpdateCategory godoc
+// @Summary Update a category
+// @Description Rename a category or change its description. The slug follows the name.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param category body entity.Category true "Category Data"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /categories/{id} [put]
+func (ctrl *categoryController) UpdateCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	var category entity.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the old category for audit purposes
+	oldCategory, err := ctrl.categoryService.GetCategoryByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+	oldCategoryJSON, _ := json.Marshal(oldCategory)
+
+	err = ctrl.categoryService.UpdateCategory(uint(id), &category)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log category update in the audit trail
+	updatedCategory, _ := ctrl.categoryService.GetCategoryByID(uint(id))
+	updatedCategoryJSON, _ := json.Marshal(updatedCategory)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"category",
+		uint(id),
+		string(oldCategoryJSON),
+		string(updatedCategoryJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category updated successfully", "category": updatedCategory})
+}
+
+// DeleteCategory godoc
+// @Summary Delete a category
+// @Description Delete a category, taking it off the events that were in it
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /categories/{id} [delete]
+func (ctrl *categoryController) DeleteCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the category before deletion for audit purposes
+	oldCategory, err := ctrl.categoryService.GetCategoryByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+	oldCategoryJSON, _ := json.Marshal(oldCategory)
+
+	err = ctrl.categoryService.DeleteCategory(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log category deletion in the audit trail
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionDelete,
+		"category",
+		uint(id),
+		string(oldCategoryJSON),
+		"", // No new state after deletion
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
+
+// GetAllTags godoc
+// @Summary Get all tags
+// @Description Get every event tag ordered by name, with the number of upcoming public events carrying each
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Success 200 {array} entity.Tag
+// @Failure 500 {object} map[string]interface{}
+// @Router /tags [get]
+func (ctrl *categoryController) GetAllTags(c *gin.Context) {
+	tags, err := ctrl.categoryService.GetAllTags()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tags})
+}
+
+// CreateTag godoc
+// @Summary Create a tag
+// @Description Create an event tag. Its slug, used to filter events, is derived from the name.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param tag body entity.Tag true "Tag Data"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /tags [post]
+func (ctrl *categoryController) CreateTag(c *gin.Context) {
+	var tag entity.Tag
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err := ctrl.categoryService.CreateTag(&tag)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log tag creation in the audit trail
+	newTag, _ := json.Marshal(tag)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionCreate,
+		"tag",
+		tag.ID,
+		nil,
+		string(newTag),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Tag created successfully", "tag": tag})
+}
+
+// UpdateTag godoc
+// @Summary Update a tag
+// @Description Rename a tag. The slug follows the name.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param tag body entity.Tag true "Tag Data"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /tags/{id} [put]
+func (ctrl *categoryController) UpdateTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	var tag entity.Tag
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the old tag for audit purposes
+	oldTag, err := ctrl.categoryService.GetTagByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+	oldTagJSON, _ := json.Marshal(oldTag)
+
+	err = ctrl.categoryService.UpdateTag(uint(id), &tag)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log tag update in the audit trail
+	updatedTag, _ := ctrl.categoryService.GetTagByID(uint(id))
+	updatedTagJSON, _ := json.Marshal(updatedTag)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"tag",
+		uint(id),
+		string(oldTagJSON),
+		string(updatedTagJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag updated successfully", "tag": updatedTag})
+}
+
+// DeleteTag godoc
+// @Summary Delete a tag
+// @Description Delete a tag, taking it off the events that carried it
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /tags/{id} [delete]
+func (ctrl *categoryController) DeleteTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the tag before deletion for audit purposes
+	oldTag, err := ctrl.categoryService.GetTagByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+	oldTagJSON, _ := json.Marshal(oldTag)
+
+	err = ctrl.categoryService.DeleteTag(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log tag deletion in the audit trail
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionDelete,
+		"tag",
+		uint(id),
+		string(oldTagJSON),
+		"", // No new state after deletion
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+// SetEventCategories godoc
+// @Summary Set the categories of an event
+// @Description Put an event in exactly the given categories. An empty list takes it out of all of them.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param categories body map[string][]uint true "Category IDs, e.g. {\"category_ids\": [1, 3]}"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/categories [put]
+func (ctrl *categoryController) SetEventCategories(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var request struct {
+		CategoryIDs []uint `json:"category_ids"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	event, err := ctrl.categoryService.SetEventCategories(uint(eventID), request.CategoryIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the new categories in the audit trail
+	categoriesJSON, _ := json.Marshal(event.Categories)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"event_categories",
+		uint(eventID),
+		nil,
+		string(categoriesJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event categories updated successfully", "data": event.Categories})
+}
+
+// SetEventTags godoc
+// @Summary Set the tags of an event
+// @Description Give an event exactly the given tags. An empty list removes all of them.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param tags body map[string][]uint true "Tag IDs, e.g. {\"tag_ids\": [2, 5]}"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/tags [put]
+func (ctrl *categoryController) SetEventTags(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var request struct {
+		TagIDs []uint `json:"tag_ids"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	event, err := ctrl.categoryService.SetEventTags(uint(eventID), request.TagIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the new tags in the audit trail
+	tagsJSON, _ := json.Marshal(event.Tags)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"event_tags",
+		uint(eventID),
+		nil,
+		string(tagsJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event tags updated successfully", "data": event.Tags})
+}
@@ -2,11 +2,14 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/dto"
 	"github.com/taufikmulyawan/ticketing-system/entity"
 	"github.com/taufikmulyawan/ticketing-system/service"
 	"github.com/taufikmulyawan/ticketing-system/utils"
@@ -15,9 +18,14 @@ import (
 type EventController interface {
 	GetAllEvents(c *gin.Context)
 	GetEventByID(c *gin.Context)
+	SearchEvents(c *gin.Context)
 	CreateEvent(c *gin.Context)
 	UpdateEvent(c *gin.Context)
 	DeleteEvent(c *gin.Context)
+	CancelEvent(c *gin.Context)
+	PostponeEvent(c *gin.Context)
+	RunLifecycle(c *gin.Context)
+	PublishEvent(c *gin.Context)
 }
 
 type eventController struct {
@@ -34,18 +42,74 @@ func NewEventController(eventService service.EventService, auditService service.
 
 // GetAllEvents godoc
 // @Summary Get all events
-// @Description Get a list of all events with pagination
+// @Description Get a list of public events with pagination, optionally filtered and sorted. Admins also see drafts and events scheduled to be published later.
 // @Tags events
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number"
 // @Param limit query int false "Items per page"
+// @Param name query string false "Part of the event name"
+// @Param location query string false "Part of the event location"
+// @Param venue_id query int false "Events held at this venue"
+// @Param city query string false "Events held at a venue in this city"
+// @Param series_id query int false "Occurrences of this event series"
+// @Param category query string false "Events in this category, by slug or name"
+// @Param tag query string false "Events with this tag, by slug or name"
+// @Param start_date query string false "Events starting on or after this day (format: YYYY-MM-DD)"
+// @Param end_date query string false "Events starting on or before this day (format: YYYY-MM-DD)"
+// @Param status query string false "Event status (active, postponed, ongoing, finished, cancelled)"
+// @Param min_price query number false "Minimum ticket price"
+// @Param max_price query number false "Maximum ticket price"
+// @Param available query bool false "Only events with seats left"
+// @Param sort query string false "Sort by start_date (default), -start_date, price, -price or popularity"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Router /events [get]
 func (ctrl *eventController) GetAllEvents(c *gin.Context) {
 	page, limit := utils.GetPaginationParams(c)
 
-	events, count, err := ctrl.eventService.GetAllEvents(page, limit)
+	var filter dto.EventFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Drafts = isAdmin(c)
+
+	events, count, err := ctrl.eventService.GetAllEvents(page, limit, filter)
+	if errors.Is(err, service.ErrInvalidEventFilter) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.GeneratePaginationResponse(events, page, limit, count))
+}
+
+// SearchEvents godoc
+// @Summary Search events
+// @Description Search event names, locations and descriptions for free text such as "jazz jakarta". Results are ranked by relevance and tolerate small typos.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param q query string true "Search text"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /events/search [get]
+func (ctrl *eventController) SearchEvents(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	page, limit := utils.GetPaginationParams(c)
+
+	events, count, err := ctrl.eventService.SearchEvents(query, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -56,7 +120,7 @@ func (ctrl *eventController) GetAllEvents(c *gin.Context) {
 
 // GetEventByID godoc
 // @Summary Get event by ID
-// @Description Get details of a specific event by its ID
+// @Description Get details of a specific event by its ID. Only admins see events that are not published yet.
 // @Tags events
 // @Accept json
 // @Produce json
@@ -72,7 +136,7 @@ func (ctrl *eventController) GetEventByID(c *gin.Context) {
 	}
 
 	event, err := ctrl.eventService.GetEventByID(uint(id))
-	if err != nil {
+	if err != nil || (!isAdmin(c) && !service.IsEventPublished(event, time.Now())) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
 		return
 	}
@@ -98,6 +162,10 @@ func (ctrl *eventController) CreateEvent(c *gin.Context) {
 		return
 	}
 
+	// Occurrences are generated through their series
+	event.SeriesID = nil
+	event.SeriesDate = ""
+
 	// Parse date strings if they come in string format
 	if event.StartDate.IsZero() || event.EndDate.IsZero() {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date are required and must be valid dates"})
@@ -105,8 +173,8 @@ func (ctrl *eventController) CreateEvent(c *gin.Context) {
 	}
 
 	// Validate event data
-	if event.Name == "" || event.Location == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "name and location are required"})
+	if event.Name == "" || (event.Location == "" && event.VenueID == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and either location or venue_id are required"})
 		return
 	}
 
@@ -172,7 +240,7 @@ func (ctrl *eventController) CreateEvent(c *gin.Context) {
 
 // UpdateEvent godoc
 // @Summary Update an event
-// @Description Update an existing event with the provided details
+// @Description Update an existing event with the provided details. The status cannot be changed here, events are cancelled and postponed through their own endpoints and move to ongoing and finished on their own.
 // @Tags events
 // @Accept json
 // @Produce json
@@ -204,8 +272,8 @@ func (ctrl *eventController) UpdateEvent(c *gin.Context) {
 	}
 
 	// Validate event data
-	if event.Name == "" || event.Location == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "name and location are required"})
+	if event.Name == "" || (event.Location == "" && event.VenueID == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and either location or venue_id are required"})
 		return
 	}
 
@@ -331,4 +399,290 @@ func (ctrl *eventController) DeleteEvent(c *gin.Context) {
 	)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Event deleted successfully"})
-} 
\ No newline at end of file
+}
+
+// CancelEvent godoc
+// @Summary Cancel an event
+// @Description Call off an event that has not started. Held tickets are released and paid tickets are refunded in full.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param cancellation body map[string]string true "Reason, e.g. {\"reason\": \"Venue unavailable\"}"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{} "Cancelled, but some refunds were rejected by the payment gateway"
+// @Router /events/{id}/cancel [post]
+func (ctrl *eventController) CancelEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var request struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the old event for audit purposes
+	oldEvent, err := ctrl.eventService.GetEventByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	oldEventJSON, _ := json.Marshal(oldEvent)
+
+	// The event comes back with the error when it was cancelled but some of
+	// its refunds failed, the cancellation is audited either way
+	event, refunds, err := ctrl.eventService.CancelEvent(uint(id), request.Reason)
+	if event == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "data": refunds})
+		return
+	}
+
+	// Log the cancellation and the refunds it issued in the audit trail
+	eventJSON, _ := json.Marshal(event)
+	refundsJSON, _ := json.Marshal(refunds)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"event",
+		uint(id),
+		string(oldEventJSON),
+		string(eventJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionCreate,
+		"refund",
+		uint(id),
+		nil,
+		string(refundsJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	if errors.Is(err, service.ErrRefundFailed) {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "event": event, "data": refunds})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "event": event, "data": refunds})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event cancelled successfully", "event": event, "refunds": refunds})
+}
+
+// PostponeEvent godoc
+// @Summary Postpone an event
+// @Description Move an event that has not started to later dates. Tickets stay valid and holders may cancel them for a full refund while the event is postponed.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param postponement body map[string]string true "New dates and reason, e.g. {\"start_date\": \"2026-01-10T19:00:00Z\", \"end_date\": \"2026-01-10T23:00:00Z\", \"reason\": \"Artist ill\"}"
+// @Security BearerAuth
+// @Success 200 {object} entity.Event
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/postpone [post]
+func (ctrl *eventController) PostponeEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var request struct {
+		StartDate time.Time `json:"start_date" binding:"required"`
+		EndDate   time.Time `json:"end_date" binding:"required"`
+		Reason    string    `json:"reason" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the old event for audit purposes
+	oldEvent, err := ctrl.eventService.GetEventByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	oldEventJSON, _ := json.Marshal(oldEvent)
+
+	event, err := ctrl.eventService.PostponeEvent(uint(id), request.StartDate, request.EndDate, request.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the postponement in the audit trail
+	eventJSON, _ := json.Marshal(event)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"event",
+		uint(id),
+		string(oldEventJSON),
+		string(eventJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event postponed successfully", "data": event})
+}
+
+// RunLifecycle godoc
+// @Summary Run the event lifecycle now
+// @Description Move events that have started to ongoing and events that have ended to finished without waiting for the scheduler
+// @Tags events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /events/lifecycle/run [post]
+func (ctrl *eventController) RunLifecycle(c *gin.Context) {
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	transitions, err := ctrl.eventService.AdvanceLifecycle(uint(uID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "data": transitions})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event lifecycle updated", "data": transitions})
+}
+
+// PublishEvent godoc
+// @Summary Publish an event
+// @Description Make a draft public right away, or at publish_at when it is given. Events with tickets sold cannot be moved back out of public view.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param publication body map[string]string false "Optional publish time, e.g. {\"publish_at\": \"2026-01-01T09:00:00Z\"}"
+// @Security BearerAuth
+// @Success 200 {object} entity.Event
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/publish [post]
+func (ctrl *eventController) PublishEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var request struct {
+		PublishAt *time.Time `json:"publish_at"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the old event for audit purposes
+	oldEvent, err := ctrl.eventService.GetEventByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	oldEventJSON, _ := json.Marshal(oldEvent)
+
+	event, err := ctrl.eventService.PublishEvent(uint(id), request.PublishAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the publication in the audit trail
+	eventJSON, _ := json.Marshal(event)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"event",
+		uint(id),
+		string(oldEventJSON),
+		string(eventJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event published successfully", "data": event})
+}
+
+// isAdmin reports whether the request was made by a signed in admin
+func isAdmin(c *gin.Context) bool {
+	userRole, _ := c.Get("user_role")
+	return userRole == string(entity.RoleAdmin)
+}
@@ -0,0 +1,301 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+	"github.com/taufikmulyawan/ticketing-system/utils"
+)
+
+type EventSeriesController interface {
+	GetAllSeries(c *gin.Context)
+	GetSeriesByID(c *gin.Context)
+	GetSeriesOccurrences(c *gin.Context)
+	CreateSeries(c *gin.Context)
+	UpdateSeries(c *gin.Context)
+	DeleteSeries(c *gin.Context)
+}
+
+type eventSeriesController struct {
+	seriesService service.EventSeriesService
+	auditService  service.AuditService
+}
+
+func NewEventSeriesController(seriesService service.EventSeriesService, auditService service.AuditService) EventSeriesController {
+	return &eventSeriesController{
+		seriesService: seriesService,
+		auditService:  auditService,
+	}
+}
+
+// GetAllSeries godoc
+// @Summary Get all event series
+// @Description Get a list of recurring event series with pagination
+// @Tags event-series
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Router /event-series [get]
+func (ctrl *eventSeriesController) GetAllSeries(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+
+	series, count, err := ctrl.seriesService.GetAllSeries(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.GeneratePaginationResponse(series, page, limit, count))
+}
+
+// GetSeriesByID godoc
+// @Summary Get event series by ID
+// @Description Get the details and recurrence rule of an event series
+// @Tags event-series
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Success 200 {object} entity.EventSeries
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /event-series/{id} [get]
+func (ctrl *eventSeriesController) GetSeriesByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	series, err := ctrl.seriesService.GetSeriesByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event series not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// GetSeriesOccurrences godoc
+// @Summary Get the occurrences of an event series
+// @Description Get the events generated for a series with pagination, soonest first. Each occurrence has its own capacity and tickets.
+// @Tags event-series
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /event-series/{id}/events [get]
+func (ctrl *eventSeriesController) GetSeriesOccurrences(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	page, limit := utils.GetPaginationParams(c)
+
+	events, count, err := ctrl.seriesService.GetSeriesOccurrences(uint(id), page, limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event series not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.GeneratePaginationResponse(events, page, limit, count))
+}
+
+// CreateSeries godoc
+// @Summary Create an event series
+// @Description Create a recurring event and generate its occurrences. The series repeats every interval days, weeks or months from start_date until the day given in until or for count occurrences, skipping the exceptions (YYYY-MM-DD).
+// @Tags event-series
+// @Accept json
+// @Produce json
+// @Param series body entity.EventSeries true "Series Data"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /event-series [post]
+func (ctrl *eventSeriesController) CreateSeries(c *gin.Context) {
+	var series entity.EventSeries
+	if err := c.ShouldBindJSON(&series); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err := ctrl.seriesService.CreateSeries(&series)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log series creation in the audit trail
+	newSeries, _ := json.Marshal(series)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionCreate,
+		"event_series",
+		series.ID,
+		nil,
+		string(newSeries),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Event series created successfully", "series": series})
+}
+
+// UpdateSeries godoc
+// @Summary Update an event series
+// @Description Update the details, end and exceptions of a series and apply them to every upcoming occurrence. The recurrence itself cannot change. Occurrences dropped from the series are deleted, which is refused when they have tickets sold. Edit a single occurrence through its event instead.
+// @Tags event-series
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Param series body entity.EventSeries true "Series Data"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /event-series/{id} [put]
+func (ctrl *eventSeriesController) UpdateSeries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	var series entity.EventSeries
+	if err := c.ShouldBindJSON(&series); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the old series for audit purposes
+	oldSeries, err := ctrl.seriesService.GetSeriesByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event series not found"})
+		return
+	}
+	oldSeriesJSON, _ := json.Marshal(oldSeries)
+
+	err = ctrl.seriesService.UpdateSeries(uint(id), &series)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log series update in the audit trail
+	updatedSeriesJSON, _ := json.Marshal(series)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"event_series",
+		uint(id),
+		string(oldSeriesJSON),
+		string(updatedSeriesJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event series updated successfully", "series": series})
+}
+
+// DeleteSeries godoc
+// @Summary Delete an event series
+// @Description Delete a series together with its occurrences. Refused when tickets were sold for any occurrence.
+// @Tags event-series
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /event-series/{id} [delete]
+func (ctrl *eventSeriesController) DeleteSeries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the series before deletion for audit purposes
+	oldSeries, err := ctrl.seriesService.GetSeriesByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event series not found"})
+		return
+	}
+	oldSeriesJSON, _ := json.Marshal(oldSeries)
+
+	err = ctrl.seriesService.DeleteSeries(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log series deletion in the audit trail
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionDelete,
+		"event_series",
+		uint(id),
+		string(oldSeriesJSON),
+		"", // No new state after deletion
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Event series deleted successfully"})
+}
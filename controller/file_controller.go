@@ -26,7 +26,7 @@ func NewFileController(fileService service.FileService) FileController {
 
 // UploadFile godoc
 // @Summary Upload a file
-// @Description Upload a file to the server (event image, ticket file, profile picture)
+// @Description Upload a file to the server (event image, ticket file, profile picture) (Admin only)
 // @Tags files
 // @Accept multipart/form-data
 // @Produce json
@@ -67,32 +67,38 @@ func (ctrl *fileController) UploadFile(c *gin.Context) {
 
 // DownloadFile godoc
 // @Summary Download a file
-// @Description Download a file from the server
+// @Description Download a file from the server. Event images are public, other types require signing in.
 // @Tags files
 // @Produce octet-stream
 // @Param filename path string true "File name"
 // @Param type path string true "File type (events, tickets, profiles)"
+// @Security BearerAuth
 // @Success 200 {file} binary
-// @Failure 400,404 {object} map[string]string
+// @Failure 400,401,404 {object} map[string]string
 // @Router /files/{type}/{filename} [get]
 func (ctrl *fileController) DownloadFile(c *gin.Context) {
 	// Get filename and file type from URL
 	filename := c.Param("filename")
 	fileType := c.Param("type")
 	
-	// Get file path
-	filePath, err := ctrl.fileService.GetFilePath(filename, fileType)
+	// Only event images can be downloaded anonymously
+	if _, signedIn := c.Get("user_id"); !signedIn && !service.IsPublicFileType(fileType) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+	
+	// Open the file in storage
+	file, err := ctrl.fileService.OpenFile(filename, fileType)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	defer file.Close()
 	
-	// Set content disposition header to force download
-	c.Header("Content-Disposition", "attachment; filename="+filename)
-	c.Header("Content-Type", getContentType(filename))
-	
-	// Serve the file
-	c.File(filePath)
+	// Stream the file with a content disposition header to force download
+	c.DataFromReader(http.StatusOK, -1, getContentType(filename), file, map[string]string{
+		"Content-Disposition": "attachment; filename=" + filename,
+	})
 }
 
 // DeleteFile godoc
@@ -4,20 +4,44 @@ import "github.com/taufikmulyawan/ticketing-system/service"
 
 // Controllers holds all controller instances
 type Controllers struct {
-	UserController   UserController
-	EventController  EventController
-	TicketController TicketController
-	ReportController ReportController
-	AuditController  AuditController
+	UserController      UserController
+	EventController     EventController
+	TicketController    TicketController
+	ReportController    ReportController
+	AuditController     AuditController
+	OrderController     OrderController
+	TierController      TicketTierController
+	PaymentController   PaymentController
+	RefundController    RefundController
+	WaitlistController  WaitlistController
+	PromoCodeController PromoCodeController
+	VenueController     VenueController
+	SeatMapController   SeatMapController
+	SeriesController    EventSeriesController
+	CategoryController  CategoryController
+	FileController      FileController
+	MediaController     MediaController
 }
 
 // InitControllers initializes all controllers with their required services
 func InitControllers(services *service.Services) *Controllers {
 	return &Controllers{
-		UserController:   NewUserController(services.UserService, services.AuditService),
-		EventController:  NewEventController(services.EventService, services.AuditService),
-		TicketController: NewTicketController(services.TicketService, services.AuditService),
-		ReportController: NewReportController(services.ReportService),
-		AuditController:  NewAuditController(services.AuditService),
+		UserController:      NewUserController(services.UserService, services.AuditService),
+		EventController:     NewEventController(services.EventService, services.AuditService),
+		TicketController:    NewTicketController(services.TicketService, services.AuditService),
+		ReportController:    NewReportController(services.ReportService),
+		AuditController:     NewAuditController(services.AuditService),
+		OrderController:     NewOrderController(services.OrderService, services.AuditService),
+		TierController:      NewTicketTierController(services.TierService, services.AuditService),
+		PaymentController:   NewPaymentController(services.PaymentService),
+		RefundController:    NewRefundController(services.RefundService, services.AuditService),
+		WaitlistController:  NewWaitlistController(services.WaitlistService, services.AuditService),
+		PromoCodeController: NewPromoCodeController(services.PromoCodeService, services.AuditService),
+		VenueController:     NewVenueController(services.VenueService, services.AuditService),
+		SeatMapController:   NewSeatMapController(services.SeatMapService, services.AuditService),
+		SeriesController:    NewEventSeriesController(services.EventSeriesService, services.AuditService),
+		CategoryController:  NewCategoryController(services.CategoryService, services.AuditService),
+		FileController:      NewFileController(services.FileService),
+		MediaController:     NewMediaController(services.MediaService, services.AuditService),
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,317 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+type MediaController interface {
+	AddMedia(c *gin.Context)
+	UpdateMedia(c *gin.Context)
+	ReorderMedia(c *gin.Context)
+	DeleteMedia(c *gin.Context)
+}
+
+type mediaController struct {
+	mediaService service.MediaService
+	auditService service.AuditService
+}
+
+func NewMediaController(mediaService service.MediaService, auditService service.AuditService) MediaController {
+	return &mediaController{
+		mediaService: mediaService,
+		auditService: auditService,
+	}
+}
+
+// AddMedia godoc
+// @Summary Add an image to an event
+// @Description Upload an image to the gallery of an event. It is added at the end of the gallery, and the first image of an event becomes its cover.
+// @Tags media
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param file formData file true "Image (JPG, PNG or GIF)"
+// @Param caption formData string false "Caption"
+// @Param is_cover formData bool false "Make the image the event's cover"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /events/{id}/media [post]
+func (ctrl *mediaController) AddMedia(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file provided"})
+		return
+	}
+
+	isCover := false
+	if value := c.PostForm("is_cover"); value != "" {
+		isCover, err = strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "is_cover must be true or false"})
+			return
+		}
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	media, err := ctrl.mediaService.AddMedia(uint(eventID), file, c.PostForm("caption"), isCover)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the new image in the audit trail
+	newMedia, _ := json.Marshal(media)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionCreate,
+		"media",
+		media.ID,
+		nil,
+		string(newMedia),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Media added successfully", "media": media})
+}
+
+// UpdateMedia godoc
+// @Summary Update an event image
+// @Description Change the caption of an image and whether it is the cover. Making an image the cover takes the flag off the previous cover.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param media_id path int true "Media ID"
+// @Param media body entity.Media true "Caption and cover flag"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/media/{media_id} [put]
+func (ctrl *mediaController) UpdateMedia(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	mediaID, err := strconv.ParseUint(c.Param("media_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	var media entity.Media
+	if err := c.ShouldBindJSON(&media); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the old image for audit purposes
+	oldMedia, err := ctrl.mediaService.GetMediaByID(uint(eventID), uint(mediaID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+	oldMediaJSON, _ := json.Marshal(oldMedia)
+
+	updatedMedia, err := ctrl.mediaService.UpdateMedia(uint(eventID), uint(mediaID), &media)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the update in the audit trail
+	updatedMediaJSON, _ := json.Marshal(updatedMedia)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"media",
+		uint(mediaID),
+		string(oldMediaJSON),
+		string(updatedMediaJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Media updated successfully", "media": updatedMedia})
+}
+
+// ReorderMedia godoc
+// @Summary Reorder an event's gallery
+// @Description Put the images of an event in the given order. The list has to contain every image of the event exactly once.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param order body map[string][]uint true "Media IDs in display order, e.g. {\"media_ids\": [3, 1, 2]}"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /events/{id}/media [put]
+func (ctrl *mediaController) ReorderMedia(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var request struct {
+		MediaIDs []uint `json:"media_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	media, err := ctrl.mediaService.ReorderMedia(uint(eventID), request.MediaIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the new order in the audit trail
+	orderJSON, _ := json.Marshal(request.MediaIDs)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"event_media",
+		uint(eventID),
+		nil,
+		string(orderJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Media reordered successfully", "data": media})
+}
+
+// DeleteMedia godoc
+// @Summary Delete an event image
+// @Description Remove an image from an event's gallery and delete its file. When it was the cover, the next image becomes the cover.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param media_id path int true "Media ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/media/{media_id} [delete]
+func (ctrl *mediaController) DeleteMedia(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	mediaID, err := strconv.ParseUint(c.Param("media_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the image before deletion for audit purposes
+	oldMedia, err := ctrl.mediaService.GetMediaByID(uint(eventID), uint(mediaID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+	oldMediaJSON, _ := json.Marshal(oldMedia)
+
+	err = ctrl.mediaService.DeleteMedia(uint(eventID), uint(mediaID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the deletion in the audit trail
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionDelete,
+		"media",
+		uint(mediaID),
+		string(oldMediaJSON),
+		"", // No new state after deletion
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Media deleted successfully"})
+}
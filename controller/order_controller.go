@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+	"github.com/taufikmulyawan/ticketing-system/utils"
+)
+
+type OrderController interface {
+	CreateOrder(c *gin.Context)
+	GetOrderByID(c *gin.Context)
+	GetMyOrders(c *gin.Context)
+}
+
+type orderController struct {
+	orderService service.OrderService
+	auditService service.AuditService
+}
+
+func NewOrderController(orderService service.OrderService, auditService service.AuditService) OrderController {
+	return &orderController{
+		orderService: orderService,
+		auditService: auditService,
+	}
+}
+
+// CreateOrder godoc
+// @Summary Create an order
+// @Description Hold tickets for one or more events in a single all-or-nothing order and start one payment for the total. The order completes once the payment succeeds.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param order body entity.Order true "Order Data (items with event_id and quantity are required)"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400,409,422 {object} map[string]interface{}
+// @Router /orders [post]
+func (ctrl *orderController) CreateOrder(c *gin.Context) {
+	var order entity.Order
+	if err := c.ShouldBindJSON(&order); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set user ID from token
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Validate order data
+	for _, item := range order.Items {
+		if item.EventID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "event_id is required for every item"})
+			return
+		}
+	}
+
+	order.UserID = uint(id)
+
+	payment, err := ctrl.orderService.CreateOrder(&order)
+	if err != nil {
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the order in the audit trail
+	newOrder, _ := json.Marshal(order)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(id),
+		entity.ActionCreate,
+		"order",
+		order.ID,
+		nil,
+		string(newOrder),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, gin.H{"order": order, "payment": payment})
+}
+
+// GetOrderByID godoc
+// @Summary Get order by ID
+// @Description Get an order with its line items and tickets
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Security BearerAuth
+// @Success 200 {object} entity.Order
+// @Failure 403,404 {object} map[string]interface{}
+// @Router /orders/{id} [get]
+func (ctrl *orderController) GetOrderByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := ctrl.orderService.GetOrderByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		return
+	}
+
+	// Check if user is authorized to view this order
+	userID, _ := c.Get("user_id")
+	userRole, _ := c.Get("user_role")
+
+	if userRole != string(entity.RoleAdmin) && order.UserID != uint(userID.(float64)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// GetMyOrders godoc
+// @Summary Get current user's orders
+// @Description Get the orders placed by the authenticated user, newest first
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /my-orders [get]
+func (ctrl *orderController) GetMyOrders(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	orders, count, err := ctrl.orderService.GetUserOrders(page, limit, uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.GeneratePaginationResponse(orders, page, limit, count))
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+type PaymentController interface {
+	HandleWebhook(c *gin.Context)
+	GetPaymentByID(c *gin.Context)
+}
+
+type paymentController struct {
+	paymentService service.PaymentService
+}
+
+func NewPaymentController(paymentService service.PaymentService) PaymentController {
+	return &paymentController{
+		paymentService: paymentService,
+	}
+}
+
+// HandleWebhook godoc
+// @Summary Payment gateway webhook
+// @Description Receive a signed payment notification from the gateway. Held tickets become purchased when a payment succeeds.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "HMAC-SHA256 signature of the raw body"
+// @Param event body service.PaymentWebhookEvent true "Webhook Event"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /payments/webhook [post]
+func (ctrl *paymentController) HandleWebhook(c *gin.Context) {
+	// The signature covers the exact bytes sent by the gateway
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	err = ctrl.paymentService.HandleWebhook(payload, c.GetHeader("X-Payment-Signature"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook processed successfully"})
+}
+
+// GetPaymentByID godoc
+// @Summary Get payment by ID
+// @Description Get the status of a payment
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param id path int true "Payment ID"
+// @Security BearerAuth
+// @Success 200 {object} entity.Payment
+// @Failure 403,404 {object} map[string]interface{}
+// @Router /payments/{id} [get]
+func (ctrl *paymentController) GetPaymentByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment ID"})
+		return
+	}
+
+	payment, err := ctrl.paymentService.GetPaymentByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		return
+	}
+
+	// Check if user is authorized to view this payment
+	userID, _ := c.Get("user_id")
+	userRole, _ := c.Get("user_role")
+
+	if userRole != string(entity.RoleAdmin) && payment.UserID != uint(userID.(float64)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this payment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+	"github.com/taufikmulyawan/ticketing-system/utils"
+)
+
+type PromoCodeController interface {
+	GetAllPromoCodes(c *gin.Context)
+	GetPromoCodeByID(c *gin.Context)
+	CreatePromoCode(c *gin.Context)
+	UpdatePromoCode(c *gin.Context)
+	DeletePromoCode(c *gin.Context)
+}
+
+type promoCodeController struct {
+	promoCodeService service.PromoCodeService
+	auditService     service.AuditService
+}
+
+func NewPromoCodeController(promoCodeService service.PromoCodeService, auditService service.AuditService) PromoCodeController {
+	return &promoCodeController{
+		promoCodeService: promoCodeService,
+		auditService:     auditService,
+	}
+}
+
+// GetAllPromoCodes godoc
+// @Summary Get all promo codes
+// @Description Get a list of all promo codes with pagination
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /promo-codes [get]
+func (ctrl *promoCodeController) GetAllPromoCodes(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+
+	promoCodes, count, err := ctrl.promoCodeService.GetAllPromoCodes(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.GeneratePaginationResponse(promoCodes, page, limit, count))
+}
+
+// GetPromoCodeByID godoc
+// @Summary Get promo code by ID
+// @Description Get a promo code with how many times it is currently used
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Param id path int true "Promo Code ID"
+// @Security BearerAuth
+// @Success 200 {object} entity.PromoCode
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /promo-codes/{id} [get]
+func (ctrl *promoCodeController) GetPromoCodeByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promo code ID"})
+		return
+	}
+
+	promoCode, err := ctrl.promoCodeService.GetPromoCodeByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Promo code not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, promoCode)
+}
+
+// CreatePromoCode godoc
+// @Summary Create a promo code
+// @Description Create a percentage or fixed discount code with optional usage caps, validity window and event scope
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Param promo_code body entity.PromoCode true "Promo Code Data"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /promo-codes [post]
+func (ctrl *promoCodeController) CreatePromoCode(c *gin.Context) {
+	var promoCode entity.PromoCode
+	if err := c.ShouldBindJSON(&promoCode); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err := ctrl.promoCodeService.CreatePromoCode(&promoCode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log promo code creation in the audit trail
+	newPromoCode, _ := json.Marshal(promoCode)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionCreate,
+		"promo_code",
+		promoCode.ID,
+		nil,
+		string(newPromoCode),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Promo code created successfully", "promo_code": promoCode})
+}
+
+// UpdatePromoCode godoc
+// @Summary Update a promo code
+// @Description Update the discount, usage caps, validity window or event scope of a promo code
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Param id path int true "Promo Code ID"
+// @Param promo_code body entity.PromoCode true "Promo Code Data"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /promo-codes/{id} [put]
+func (ctrl *promoCodeController) UpdatePromoCode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promo code ID"})
+		return
+	}
+
+	var promoCode entity.PromoCode
+	if err := c.ShouldBindJSON(&promoCode); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the old promo code for audit purposes
+	oldPromoCode, err := ctrl.promoCodeService.GetPromoCodeByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Promo code not found"})
+		return
+	}
+	oldPromoCodeJSON, _ := json.Marshal(oldPromoCode)
+
+	err = ctrl.promoCodeService.UpdatePromoCode(uint(id), &promoCode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log promo code update in the audit trail
+	updatedPromoCode, _ := ctrl.promoCodeService.GetPromoCodeByID(uint(id))
+	updatedPromoCodeJSON, _ := json.Marshal(updatedPromoCode)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"promo_code",
+		uint(id),
+		string(oldPromoCodeJSON),
+		string(updatedPromoCodeJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Promo code updated successfully", "promo_code": updatedPromoCode})
+}
+
+// DeletePromoCode godoc
+// @Summary Delete a promo code
+// @Description Delete a promo code that has never been applied to a ticket
+// @Tags promo-codes
+// @Accept json
+// @Produce json
+// @Param id path int true "Promo Code ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /promo-codes/{id} [delete]
+func (ctrl *promoCodeController) DeletePromoCode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promo code ID"})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the promo code before deletion for audit purposes
+	oldPromoCode, err := ctrl.promoCodeService.GetPromoCodeByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Promo code not found"})
+		return
+	}
+	oldPromoCodeJSON, _ := json.Marshal(oldPromoCode)
+
+	err = ctrl.promoCodeService.DeletePromoCode(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log promo code deletion in the audit trail
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionDelete,
+		"promo_code",
+		uint(id),
+		string(oldPromoCodeJSON),
+		"", // No new state after deletion
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Promo code deleted successfully"})
+}
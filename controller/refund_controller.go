@@ -0,0 +1,233 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+type RefundController interface {
+	GetCancellationPolicy(c *gin.Context)
+	SetCancellationPolicy(c *gin.Context)
+	GetEventRefunds(c *gin.Context)
+	RefundCancelledEvent(c *gin.Context)
+}
+
+type refundController struct {
+	refundService service.RefundService
+	auditService  service.AuditService
+}
+
+func NewRefundController(refundService service.RefundService, auditService service.AuditService) RefundController {
+	return &refundController{
+		refundService: refundService,
+		auditService:  auditService,
+	}
+}
+
+// GetCancellationPolicy godoc
+// @Summary Get the cancellation policy of an event
+// @Description Get the refund percentage that applies depending on how long before the event a ticket is cancelled. An empty policy means a full refund.
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Success 200 {array} entity.CancellationPolicyRule
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/cancellation-policy [get]
+func (ctrl *refundController) GetCancellationPolicy(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	rules, err := ctrl.refundService.GetCancellationPolicy(uint(eventID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": rules})
+}
+
+// SetCancellationPolicy godoc
+// @Summary Set the cancellation policy of an event
+// @Description Replace the refund rules of an event. Each rule refunds refund_percentage of the ticket price when cancelling at least hours_before_start hours before the event.
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param rules body []entity.CancellationPolicyRule true "Policy Rules"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /events/{id}/cancellation-policy [put]
+func (ctrl *refundController) SetCancellationPolicy(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var rules []entity.CancellationPolicyRule
+	if err := c.ShouldBindJSON(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the old policy for audit purposes
+	oldRules, _ := ctrl.refundService.GetCancellationPolicy(uint(eventID))
+	oldRulesJSON, _ := json.Marshal(oldRules)
+
+	err = ctrl.refundService.SetCancellationPolicy(uint(eventID), rules)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the policy change in the audit trail
+	newRulesJSON, _ := json.Marshal(rules)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"cancellation_policy",
+		uint(eventID),
+		string(oldRulesJSON),
+		string(newRulesJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cancellation policy updated successfully", "data": rules})
+}
+
+// GetEventRefunds godoc
+// @Summary Get refunds of an event
+// @Description Get every refund issued for tickets of an event
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security BearerAuth
+// @Success 200 {array} entity.Refund
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/refunds [get]
+func (ctrl *refundController) GetEventRefunds(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	refunds, err := ctrl.refundService.GetEventRefunds(uint(eventID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": refunds})
+}
+
+// RefundCancelledEvent godoc
+// @Summary Refund all tickets of a cancelled event
+// @Description Cancel every remaining ticket of a cancelled event and refund paid tickets in full. Refunds the payment gateway rejected before are sent again, so it is safe to run again until none fail.
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{} "Some refunds were rejected by the payment gateway"
+// @Router /events/{id}/refunds [post]
+func (ctrl *refundController) RefundCancelledEvent(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Refunds the gateway rejected come back with the error, the ones that
+	// were sent are audited either way
+	refunds, err := ctrl.refundService.RefundCancelledEvent(uint(eventID))
+	if err != nil && !errors.Is(err, service.ErrRefundFailed) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var totalRefunded float64
+	failed := 0
+	for _, refund := range refunds {
+		if refund.Status == entity.RefundStatusFailed {
+			failed++
+			continue
+		}
+		totalRefunded += refund.Amount
+	}
+
+	// Log the bulk refund in the audit trail
+	refundsJSON, _ := json.Marshal(refunds)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionCreate,
+		"refund",
+		uint(eventID),
+		nil,
+		string(refundsJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	result := gin.H{
+		"message":        "Event tickets refunded",
+		"refunded_count": len(refunds) - failed,
+		"failed_count":   failed,
+		"total_refunded": totalRefunded,
+		"data":           refunds,
+	}
+	if err != nil {
+		result["error"] = err.Error()
+		c.JSON(http.StatusBadGateway, result)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -12,6 +12,7 @@ import (
 type ReportController interface {
 	GetSalesReport(c *gin.Context)
 	GetEventSalesReport(c *gin.Context)
+	GetCategorySalesReport(c *gin.Context)
 	ExportSalesReportPDF(c *gin.Context)
 	ExportEventSalesReportPDF(c *gin.Context)
 	ExportSalesReportCSV(c *gin.Context)
@@ -75,6 +76,26 @@ func (ctrl *reportController) GetEventSalesReport(c *gin.Context) {
 	c.JSON(http.StatusOK, eventSummary)
 }
 
+// GetCategorySalesReport godoc
+// @Summary Get sales report by category
+// @Description Get ticket sales and revenue totalled per event category. Events in several categories count towards each, and events without a category are grouped as Uncategorized.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} types.CategorySalesSummary
+// @Failure 500 {object} map[string]interface{}
+// @Router /reports/categories [get]
+func (ctrl *reportController) GetCategorySalesReport(c *gin.Context) {
+	summaries, err := ctrl.reportService.GetCategorySalesSummary()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": summaries})
+}
+
 // ExportSalesReportPDF godoc
 // @Summary Export overall sales report as PDF
 // @Description Export a PDF of ticket sales and revenue across all events
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/dto"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+	"github.com/taufikmulyawan/ticketing-system/utils"
+)
+
+type SeatMapController interface {
+	GetAllSeatMaps(c *gin.Context)
+	GetSeatMapByID(c *gin.Context)
+	CreateSeatMap(c *gin.Context)
+	DeleteSeatMap(c *gin.Context)
+	GetEventSeats(c *gin.Context)
+}
+
+type seatMapController struct {
+	seatMapService service.SeatMapService
+	auditService   service.AuditService
+}
+
+func NewSeatMapController(seatMapService service.SeatMapService, auditService service.AuditService) SeatMapController {
+	return &seatMapController{
+		seatMapService: seatMapService,
+		auditService:   auditService,
+	}
+}
+
+// GetAllSeatMaps godoc
+// @Summary Get all seat maps
+// @Description Get a list of seat maps without their seats, optionally only those of one venue
+// @Tags seat-maps
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param venue_id query int false "Venue ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /seat-maps [get]
+func (ctrl *seatMapController) GetAllSeatMaps(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+
+	var venueID *uint
+	if value := c.Query("venue_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+			return
+		}
+		venue := uint(id)
+		venueID = &venue
+	}
+
+	seatMaps, count, err := ctrl.seatMapService.GetAllSeatMaps(page, limit, venueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.GeneratePaginationResponse(seatMaps, page, limit, count))
+}
+
+// GetSeatMapByID godoc
+// @Summary Get seat map by ID
+// @Description Get a seat map with every seat's section, row, number, category and price
+// @Tags seat-maps
+// @Accept json
+// @Produce json
+// @Param id path int true "Seat Map ID"
+// @Success 200 {object} entity.SeatMap
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /seat-maps/{id} [get]
+func (ctrl *seatMapController) GetSeatMapByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seat map ID"})
+		return
+	}
+
+	seatMap, err := ctrl.seatMapService.GetSeatMapByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Seat map not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, seatMap)
+}
+
+// CreateSeatMap godoc
+// @Summary Create a seat map
+// @Description Lay out sections of rows of seats. Seats take the category and price of their section unless given their own. Rows list their seats or number them 1 to seat_count.
+// @Tags seat-maps
+// @Accept json
+// @Produce json
+// @Param seat_map body dto.SeatMapCreateRequest true "Seat Map Layout"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /seat-maps [post]
+func (ctrl *seatMapController) CreateSeatMap(c *gin.Context) {
+	var request dto.SeatMapCreateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	seatMap, err := ctrl.seatMapService.CreateSeatMap(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log seat map creation in the audit trail
+	newSeatMap, _ := json.Marshal(seatMap)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionCreate,
+		"seat_map",
+		seatMap.ID,
+		nil,
+		string(newSeatMap),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Seat map created successfully", "seat_map": seatMap})
+}
+
+// DeleteSeatMap godoc
+// @Summary Delete a seat map
+// @Description Delete a seat map that no event uses
+// @Tags seat-maps
+// @Accept json
+// @Produce json
+// @Param id path int true "Seat Map ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /seat-maps/{id} [delete]
+func (ctrl *seatMapController) DeleteSeatMap(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seat map ID"})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the seat map before deletion for audit purposes
+	oldSeatMap, err := ctrl.seatMapService.GetSeatMapByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Seat map not found"})
+		return
+	}
+	oldSeatMapJSON, _ := json.Marshal(oldSeatMap)
+
+	err = ctrl.seatMapService.DeleteSeatMap(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log seat map deletion in the audit trail
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionDelete,
+		"seat_map",
+		uint(id),
+		string(oldSeatMapJSON),
+		"", // No new state after deletion
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Seat map deleted successfully"})
+}
+
+// GetEventSeats godoc
+// @Summary Get seat availability of an event
+// @Description Get every seat of an event with reserved seating, its price and whether it can still be bought. Pass a seat's ID as seat_id when purchasing. Only admins see the seats of drafts and scheduled events.
+// @Tags seat-maps
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/seats [get]
+func (ctrl *seatMapController) GetEventSeats(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	seats, err := ctrl.seatMapService.GetEventSeats(uint(eventID), isAdmin(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	available := 0
+	for _, seat := range seats {
+		if *seat.Available {
+			available++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": seats, "available_count": available})
+}
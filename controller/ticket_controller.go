@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -16,6 +17,16 @@ type TicketController interface {
 	GetTicketByID(c *gin.Context)
 	PurchaseTicket(c *gin.Context)
 	CancelTicket(c *gin.Context)
+	HoldTicket(c *gin.Context)
+	ConfirmHold(c *gin.Context)
+	ReleaseHold(c *gin.Context)
+	GetTicketQRCode(c *gin.Context)
+	GetTicketPDF(c *gin.Context)
+	CheckIn(c *gin.Context)
+	StartTransfer(c *gin.Context)
+	AcceptTransfer(c *gin.Context)
+	CancelTransfer(c *gin.Context)
+	GetMyTransfers(c *gin.Context)
 }
 
 type ticketController struct {
@@ -96,34 +107,45 @@ func (ctrl *ticketController) GetTicketByID(c *gin.Context) {
 		return
 	}
 
-	ticket, err := ctrl.ticketService.GetTicketByID(uint(id))
+	ticket, ok := ctrl.findViewableTicket(c, uint(id))
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, ticket)
+}
+
+// findViewableTicket loads a ticket that the current user owns, or any ticket
+// for admins. It writes the error response and returns false otherwise.
+func (ctrl *ticketController) findViewableTicket(c *gin.Context, id uint) (*entity.Ticket, bool) {
+	ticket, err := ctrl.ticketService.GetTicketByID(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
-		return
+		return nil, false
 	}
 
 	// Check if user is authorized to view this ticket
 	userID, _ := c.Get("user_id")
 	userRole, _ := c.Get("user_role")
-	
+
 	if userRole != string(entity.RoleAdmin) && ticket.UserID != uint(userID.(float64)) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this ticket"})
-		return
+		return nil, false
 	}
 
-	c.JSON(http.StatusOK, ticket)
+	return ticket, true
 }
 
 // PurchaseTicket godoc
 // @Summary Purchase a ticket
-// @Description Purchase a ticket for an event
+// @Description Hold a ticket for an event and start its payment. The ticket becomes purchased once the payment gateway confirms the payment.
 // @Tags tickets
 // @Accept json
 // @Produce json
-// @Param ticket body entity.Ticket true "Ticket Data (eventID is required)"
+// @Param ticket body entity.Ticket true "Ticket Data (eventID is required, seat_id is required for events with reserved seating, promo_code is optional)"
 // @Security BearerAuth
 // @Success 201 {object} map[string]interface{}
-// @Failure 400 {object} map[string]interface{}
+// @Failure 400,409,422 {object} map[string]interface{}
 // @Router /tickets [post]
 func (ctrl *ticketController) PurchaseTicket(c *gin.Context) {
 	var ticket entity.Ticket
@@ -156,9 +178,9 @@ func (ctrl *ticketController) PurchaseTicket(c *gin.Context) {
 	// Store the pre-purchase ticket data for audit
 	oldTicket, _ := json.Marshal(nil) // No old ticket exists
 	
-	err := ctrl.ticketService.PurchaseTicket(&ticket)
+	payment, err := ctrl.ticketService.PurchaseTicket(&ticket)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	
@@ -178,12 +200,12 @@ func (ctrl *ticketController) PurchaseTicket(c *gin.Context) {
 		userAgent,
 	)
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Ticket purchased successfully", "ticket_id": ticket.ID})
+	c.JSON(http.StatusCreated, gin.H{"message": "Ticket held, awaiting payment", "ticket_id": ticket.ID, "expires_at": ticket.ExpiresAt, "payment": payment})
 }
 
 // CancelTicket godoc
 // @Summary Cancel a ticket
-// @Description Cancel a purchased ticket
+// @Description Cancel a purchased ticket. The refund follows the event's cancellation policy, or is in full when opting out of a postponed event.
 // @Tags tickets
 // @Accept json
 // @Produce json
@@ -221,7 +243,7 @@ func (ctrl *ticketController) CancelTicket(c *gin.Context) {
 	
 	oldTicketJSON, _ := json.Marshal(oldTicket)
 
-	err = ctrl.ticketService.CancelTicket(uint(id), uint(userIDUint))
+	refund, err := ctrl.ticketService.CancelTicket(uint(id), uint(userIDUint))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -246,5 +268,543 @@ func (ctrl *ticketController) CancelTicket(c *gin.Context) {
 		userAgent,
 	)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Ticket cancelled successfully"})
-} 
\ No newline at end of file
+	c.JSON(http.StatusOK, gin.H{"message": "Ticket cancelled successfully", "refund": refund})
+}
+
+// HoldTicket godoc
+// @Summary Hold a ticket during checkout
+// @Description Reserve a seat for a limited time while the buyer completes payment. The hold counts against capacity until it is confirmed, released or expires.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param ticket body entity.Ticket true "Ticket Data (eventID is required, seat_id is required for events with reserved seating, promo_code is optional)"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400,409,422 {object} map[string]interface{}
+// @Router /tickets/hold [post]
+func (ctrl *ticketController) HoldTicket(c *gin.Context) {
+	var ticket entity.Ticket
+	if err := c.ShouldBindJSON(&ticket); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set user ID from token
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Validate ticket data
+	if ticket.EventID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "event_id is required"})
+		return
+	}
+
+	ticket.UserID = uint(id)
+
+	err := ctrl.ticketService.HoldTicket(&ticket)
+	if err != nil {
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the hold in the audit trail
+	newTicket, _ := json.Marshal(ticket)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(id),
+		entity.ActionCreate,
+		"ticket",
+		ticket.ID,
+		nil,
+		string(newTicket),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Ticket held successfully", "ticket_id": ticket.ID, "expires_at": ticket.ExpiresAt})
+}
+
+// ConfirmHold godoc
+// @Summary Confirm a held ticket
+// @Description Start the payment for a ticket that is on hold. The ticket becomes purchased once the payment succeeds before the hold expires.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Security BearerAuth
+// @Success 201 {object} entity.Payment
+// @Failure 400 {object} map[string]interface{}
+// @Router /tickets/{id}/confirm [post]
+func (ctrl *ticketController) ConfirmHold(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	// Get user ID from token
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userIDUint, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	payment, err := ctrl.ticketService.ConfirmHold(uint(id), uint(userIDUint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the payment in the audit trail
+	newPayment, _ := json.Marshal(payment)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(userIDUint),
+		entity.ActionCreate,
+		"payment",
+		payment.ID,
+		nil,
+		string(newPayment),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, payment)
+}
+
+// ReleaseHold godoc
+// @Summary Release a held ticket
+// @Description Abandon checkout and give the held seat back immediately
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /tickets/{id}/release [post]
+func (ctrl *ticketController) ReleaseHold(c *gin.Context) {
+	ctrl.changeHold(c, ctrl.ticketService.ReleaseHold, "Ticket hold released successfully")
+}
+
+// changeHold applies a hold transition for the current user and records it in the audit trail
+func (ctrl *ticketController) changeHold(c *gin.Context, change func(id uint, userID uint) error, message string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	// Get user ID from token
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userIDUint, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the ticket before the change for audit purposes
+	oldTicket, _ := ctrl.ticketService.GetTicketByID(uint(id))
+	if oldTicket == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
+		return
+	}
+
+	oldTicketJSON, _ := json.Marshal(oldTicket)
+
+	err = change(uint(id), uint(userIDUint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get updated ticket after the change
+	updatedTicket, _ := ctrl.ticketService.GetTicketByID(uint(id))
+	updatedTicketJSON, _ := json.Marshal(updatedTicket)
+
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(userIDUint),
+		entity.ActionUpdate,
+		"ticket",
+		uint(id),
+		string(oldTicketJSON),
+		string(updatedTicketJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// GetTicketQRCode godoc
+// @Summary Get the QR code of a ticket
+// @Description Get a PNG QR code holding the signed ticket credential that is scanned at the door
+// @Tags tickets
+// @Produce png
+// @Param id path int true "Ticket ID"
+// @Security BearerAuth
+// @Success 200 {file} binary
+// @Failure 400,403,404 {object} map[string]interface{}
+// @Router /tickets/{id}/qr [get]
+func (ctrl *ticketController) GetTicketQRCode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	if _, ok := ctrl.findViewableTicket(c, uint(id)); !ok {
+		return
+	}
+
+	png, err := ctrl.ticketService.GetTicketQRCode(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", png)
+}
+
+// GetTicketPDF godoc
+// @Summary Download a ticket as PDF
+// @Description Download a printable e-ticket with the event details, ticket holder, tier and QR code
+// @Tags tickets
+// @Produce application/pdf
+// @Param id path int true "Ticket ID"
+// @Security BearerAuth
+// @Success 200 {file} binary
+// @Failure 400,403,404 {object} map[string]interface{}
+// @Router /tickets/{id}/pdf [get]
+func (ctrl *ticketController) GetTicketPDF(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ticket ID"})
+		return
+	}
+
+	if _, ok := ctrl.findViewableTicket(c, uint(id)); !ok {
+		return
+	}
+
+	pdfBytes, err := ctrl.ticketService.GetTicketPDF(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set headers for PDF download
+	ticketID := strconv.FormatUint(id, 10)
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", "attachment; filename=ticket_"+ticketID+".pdf")
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Transfer-Encoding", "binary")
+	c.Header("Cache-Control", "no-cache")
+
+	c.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
+
+// CheckIn godoc
+// @Summary Check a ticket in at the door
+// @Description Verify a scanned ticket credential and admit its holder. Each ticket is admitted only once.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param checkin body map[string]string true "Scanned token, e.g. {\"token\": \"...\"}"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404,409 {object} map[string]interface{}
+// @Router /checkin [post]
+func (ctrl *ticketController) CheckIn(c *gin.Context) {
+	var request struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get staff user ID from token
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	staffID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	ticket, err := ctrl.ticketService.CheckIn(request.Token, uint(staffID))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrTicketAlreadyCheckedIn) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the check-in in the audit trail
+	checkIn, _ := json.Marshal(gin.H{"checked_in_at": ticket.CheckedInAt, "checked_in_by": ticket.CheckedInBy})
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(staffID),
+		entity.ActionUpdate,
+		"ticket",
+		ticket.ID,
+		nil,
+		string(checkIn),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Ticket checked in successfully",
+		"ticket_id":     ticket.ID,
+		"event":         ticket.Event.Name,
+		"holder":        ticket.User.Name,
+		"checked_in_at": ticket.CheckedInAt,
+	})
+}
+
+// StartTransfer godoc
+// @Summary Transfer a ticket to another user
+// @Description Offer a purchased ticket to the user registered with the given email. The ticket changes hands once they accept, and transfers close when the event starts.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param transfer body map[string]string true "Recipient, e.g. {\"email\": \"friend@example.com\"}"
+// @Security BearerAuth
+// @Success 201 {object} entity.TicketTransfer
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /tickets/{id}/transfer [post]
+func (ctrl *ticketController) StartTransfer(c *gin.Context) {
+	id, userID, ok := transferParams(c, "Invalid ticket ID")
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transfer, err := ctrl.ticketService.StartTransfer(id, userID, request.Email)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the transfer offer in the audit trail
+	newTransfer, _ := json.Marshal(transfer)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		userID,
+		entity.ActionCreate,
+		"ticket_transfer",
+		transfer.ID,
+		nil,
+		string(newTransfer),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+// AcceptTransfer godoc
+// @Summary Accept a ticket transfer
+// @Description Accept a ticket offered to you. The ticket and its QR code move to your account and the previous holder's QR code stops working.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Security BearerAuth
+// @Success 200 {object} entity.TicketTransfer
+// @Failure 400,404,409 {object} map[string]interface{}
+// @Router /transfers/{id}/accept [post]
+func (ctrl *ticketController) AcceptTransfer(c *gin.Context) {
+	id, userID, ok := transferParams(c, "Invalid transfer ID")
+	if !ok {
+		return
+	}
+
+	transfer, err := ctrl.ticketService.AcceptTransfer(id, userID)
+	if err != nil {
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Record the change of owner in the audit trail of both the sender and
+	// the recipient
+	oldOwner, _ := json.Marshal(gin.H{"user_id": transfer.FromUserID})
+	newOwner, _ := json.Marshal(gin.H{"user_id": transfer.ToUserID, "transfer_id": transfer.ID})
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	for _, party := range []uint{transfer.FromUserID, transfer.ToUserID} {
+		go ctrl.auditService.LogActivity(
+			party,
+			entity.ActionUpdate,
+			"ticket",
+			transfer.TicketID,
+			string(oldOwner),
+			string(newOwner),
+			ipAddress,
+			userAgent,
+		)
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// CancelTransfer godoc
+// @Summary Cancel or decline a ticket transfer
+// @Description Close a pending transfer. The sender withdraws it and the recipient declines it; the ticket stays with the sender.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Security BearerAuth
+// @Success 200 {object} entity.TicketTransfer
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /transfers/{id}/cancel [post]
+func (ctrl *ticketController) CancelTransfer(c *gin.Context) {
+	id, userID, ok := transferParams(c, "Invalid transfer ID")
+	if !ok {
+		return
+	}
+
+	transfer, err := ctrl.ticketService.CancelTransfer(id, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the closed transfer in the audit trail
+	oldStatus, _ := json.Marshal(gin.H{"status": entity.TransferStatusPending})
+	newStatus, _ := json.Marshal(gin.H{"status": transfer.Status})
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		userID,
+		entity.ActionUpdate,
+		"ticket_transfer",
+		transfer.ID,
+		string(oldStatus),
+		string(newStatus),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// GetMyTransfers godoc
+// @Summary Get my ticket transfers
+// @Description Get the ticket transfers the current user sent or received
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} entity.TicketTransfer
+// @Router /my-transfers [get]
+func (ctrl *ticketController) GetMyTransfers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	transfers, err := ctrl.ticketService.GetMyTransfers(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": transfers})
+}
+
+// transferParams reads the ID from the path and the user ID from the token.
+// It writes the error response and returns false when either is missing.
+func transferParams(c *gin.Context, invalidID string) (uint, uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidID})
+		return 0, 0, false
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return 0, 0, false
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return 0, 0, false
+	}
+
+	return uint(id), uint(uID), true
+}
+
+// purchaseErrorStatus maps a failed purchase to its response status. Breaking
+// the event's purchase rules is a conflict with the buyer's earlier purchases,
+// or an unprocessable request when the purchase itself is too large. A seat
+// someone else took first is a conflict as well.
+func purchaseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrOrderLimitExceeded):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrUserLimitReached), errors.Is(err, service.ErrPurchaseCooldown), errors.Is(err, service.ErrSeatTaken):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,267 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+type TicketTierController interface {
+	GetEventTiers(c *gin.Context)
+	CreateTier(c *gin.Context)
+	UpdateTier(c *gin.Context)
+	DeleteTier(c *gin.Context)
+}
+
+type ticketTierController struct {
+	tierService  service.TicketTierService
+	auditService service.AuditService
+}
+
+func NewTicketTierController(tierService service.TicketTierService, auditService service.AuditService) TicketTierController {
+	return &ticketTierController{
+		tierService:  tierService,
+		auditService: auditService,
+	}
+}
+
+// GetEventTiers godoc
+// @Summary Get ticket tiers of an event
+// @Description Get the price tiers an event is sold in, cheapest first. Only admins see the tiers of drafts and scheduled events.
+// @Tags tiers
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security BearerAuth
+// @Success 200 {array} entity.TicketTier
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/tiers [get]
+func (ctrl *ticketTierController) GetEventTiers(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	tiers, err := ctrl.tierService.GetEventTiers(uint(eventID), isAdmin(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": tiers})
+}
+
+// CreateTier godoc
+// @Summary Create a ticket tier
+// @Description Add a price tier with its own capacity and sales window to an event
+// @Tags tiers
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param tier body entity.TicketTier true "Tier Data"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /events/{id}/tiers [post]
+func (ctrl *ticketTierController) CreateTier(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var tier entity.TicketTier
+	if err := c.ShouldBindJSON(&tier); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	tier.EventID = uint(eventID)
+
+	err = ctrl.tierService.CreateTier(&tier)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log tier creation in the audit trail
+	newTier, _ := json.Marshal(tier)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionCreate,
+		"ticket_tier",
+		tier.ID,
+		nil,
+		string(newTier),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Ticket tier created successfully", "tier_id": tier.ID})
+}
+
+// UpdateTier godoc
+// @Summary Update a ticket tier
+// @Description Update the name, price, capacity or sales window of a ticket tier
+// @Tags tiers
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param tier_id path int true "Tier ID"
+// @Param tier body entity.TicketTier true "Tier Data"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/tiers/{tier_id} [put]
+func (ctrl *ticketTierController) UpdateTier(c *gin.Context) {
+	oldTier, ok := ctrl.findEventTier(c)
+	if !ok {
+		return
+	}
+	oldTierJSON, _ := json.Marshal(oldTier)
+
+	var tier entity.TicketTier
+	if err := c.ShouldBindJSON(&tier); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err := ctrl.tierService.UpdateTier(oldTier.ID, &tier)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log tier update in the audit trail
+	updatedTier, _ := ctrl.tierService.GetTierByID(oldTier.ID)
+	updatedTierJSON, _ := json.Marshal(updatedTier)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"ticket_tier",
+		oldTier.ID,
+		string(oldTierJSON),
+		string(updatedTierJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ticket tier updated successfully"})
+}
+
+// DeleteTier godoc
+// @Summary Delete a ticket tier
+// @Description Delete a ticket tier that has not issued any tickets
+// @Tags tiers
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Param tier_id path int true "Tier ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/tiers/{tier_id} [delete]
+func (ctrl *ticketTierController) DeleteTier(c *gin.Context) {
+	oldTier, ok := ctrl.findEventTier(c)
+	if !ok {
+		return
+	}
+	oldTierJSON, _ := json.Marshal(oldTier)
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err := ctrl.tierService.DeleteTier(oldTier.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log tier deletion in the audit trail
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionDelete,
+		"ticket_tier",
+		oldTier.ID,
+		string(oldTierJSON),
+		"", // No new state after deletion
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Ticket tier deleted successfully"})
+}
+
+// findEventTier loads the tier named in the URL and makes sure it belongs to
+// the event in the URL, writing the error response when it does not
+func (ctrl *ticketTierController) findEventTier(c *gin.Context) (*entity.TicketTier, bool) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return nil, false
+	}
+
+	tierID, err := strconv.ParseUint(c.Param("tier_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tier ID"})
+		return nil, false
+	}
+
+	tier, err := ctrl.tierService.GetTierByID(uint(tierID))
+	if err != nil || tier.EventID != uint(eventID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ticket tier not found"})
+		return nil, false
+	}
+
+	return tier, true
+}
@@ -0,0 +1,303 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+	"github.com/taufikmulyawan/ticketing-system/utils"
+)
+
+type VenueController interface {
+	GetAllVenues(c *gin.Context)
+	GetVenueByID(c *gin.Context)
+	GetVenueEvents(c *gin.Context)
+	CreateVenue(c *gin.Context)
+	UpdateVenue(c *gin.Context)
+	DeleteVenue(c *gin.Context)
+}
+
+type venueController struct {
+	venueService service.VenueService
+	auditService service.AuditService
+}
+
+func NewVenueController(venueService service.VenueService, auditService service.AuditService) VenueController {
+	return &venueController{
+		venueService: venueService,
+		auditService: auditService,
+	}
+}
+
+// GetAllVenues godoc
+// @Summary Get all venues
+// @Description Get a list of venues ordered by name with pagination, optionally only those in one city
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Param city query string false "City of the venue"
+// @Success 200 {object} map[string]interface{}
+// @Router /venues [get]
+func (ctrl *venueController) GetAllVenues(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+
+	venues, count, err := ctrl.venueService.GetAllVenues(page, limit, c.Query("city"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.GeneratePaginationResponse(venues, page, limit, count))
+}
+
+// GetVenueByID godoc
+// @Summary Get venue by ID
+// @Description Get the address, coordinates, timezone and capacity of a venue
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Success 200 {object} entity.Venue
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /venues/{id} [get]
+func (ctrl *venueController) GetVenueByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return
+	}
+
+	venue, err := ctrl.venueService.GetVenueByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, venue)
+}
+
+// GetVenueEvents godoc
+// @Summary Get events at a venue
+// @Description Get the events held at a venue with pagination, soonest first
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /venues/{id}/events [get]
+func (ctrl *venueController) GetVenueEvents(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return
+	}
+
+	page, limit := utils.GetPaginationParams(c)
+
+	events, count, err := ctrl.venueService.GetVenueEvents(uint(id), page, limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.GeneratePaginationResponse(events, page, limit, count))
+}
+
+// CreateVenue godoc
+// @Summary Create a venue
+// @Description Create a venue events can be linked to. The timezone is an IANA name such as Asia/Jakarta.
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param venue body entity.Venue true "Venue Data"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /venues [post]
+func (ctrl *venueController) CreateVenue(c *gin.Context) {
+	var venue entity.Venue
+	if err := c.ShouldBindJSON(&venue); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	err := ctrl.venueService.CreateVenue(&venue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log venue creation in the audit trail
+	newVenue, _ := json.Marshal(venue)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionCreate,
+		"venue",
+		venue.ID,
+		nil,
+		string(newVenue),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Venue created successfully", "venue": venue})
+}
+
+// UpdateVenue godoc
+// @Summary Update a venue
+// @Description Update a venue. The max capacity cannot drop below the capacity of an upcoming event held there.
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param venue body entity.Venue true "Venue Data"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /venues/{id} [put]
+func (ctrl *venueController) UpdateVenue(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return
+	}
+
+	var venue entity.Venue
+	if err := c.ShouldBindJSON(&venue); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the old venue for audit purposes
+	oldVenue, err := ctrl.venueService.GetVenueByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+		return
+	}
+	oldVenueJSON, _ := json.Marshal(oldVenue)
+
+	err = ctrl.venueService.UpdateVenue(uint(id), &venue)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log venue update in the audit trail
+	updatedVenue, _ := ctrl.venueService.GetVenueByID(uint(id))
+	updatedVenueJSON, _ := json.Marshal(updatedVenue)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionUpdate,
+		"venue",
+		uint(id),
+		string(oldVenueJSON),
+		string(updatedVenueJSON),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Venue updated successfully", "venue": updatedVenue})
+}
+
+// DeleteVenue godoc
+// @Summary Delete a venue
+// @Description Delete a venue that no event has been linked to
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /venues/{id} [delete]
+func (ctrl *venueController) DeleteVenue(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue ID"})
+		return
+	}
+
+	// Get user ID from token for audit
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uID, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// Get the venue before deletion for audit purposes
+	oldVenue, err := ctrl.venueService.GetVenueByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+		return
+	}
+	oldVenueJSON, _ := json.Marshal(oldVenue)
+
+	err = ctrl.venueService.DeleteVenue(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log venue deletion in the audit trail
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		uint(uID),
+		entity.ActionDelete,
+		"venue",
+		uint(id),
+		string(oldVenueJSON),
+		"", // No new state after deletion
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Venue deleted successfully"})
+}
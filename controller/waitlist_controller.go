@@ -0,0 +1,241 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+type WaitlistController interface {
+	JoinWaitlist(c *gin.Context)
+	LeaveWaitlist(c *gin.Context)
+	GetMyWaitlistEntry(c *gin.Context)
+	ClaimOffer(c *gin.Context)
+	GetEventWaitlist(c *gin.Context)
+}
+
+type waitlistController struct {
+	waitlistService service.WaitlistService
+	auditService    service.AuditService
+}
+
+func NewWaitlistController(waitlistService service.WaitlistService, auditService service.AuditService) WaitlistController {
+	return &waitlistController{
+		waitlistService: waitlistService,
+		auditService:    auditService,
+	}
+}
+
+// JoinWaitlist godoc
+// @Summary Join the waitlist of a sold out event
+// @Description Get in line for a sold out event. When a ticket is cancelled the next person in line is offered the seat for a limited time.
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security BearerAuth
+// @Success 201 {object} entity.WaitlistEntry
+// @Failure 400 {object} map[string]interface{}
+// @Router /events/{id}/waitlist [post]
+func (ctrl *waitlistController) JoinWaitlist(c *gin.Context) {
+	eventID, userID, ok := waitlistParams(c)
+	if !ok {
+		return
+	}
+
+	entry, err := ctrl.waitlistService.JoinWaitlist(eventID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log joining the waitlist in the audit trail
+	newEntry, _ := json.Marshal(entry)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		userID,
+		entity.ActionCreate,
+		"waitlist_entry",
+		entry.ID,
+		nil,
+		string(newEntry),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// LeaveWaitlist godoc
+// @Summary Leave the waitlist of an event
+// @Description Give up your place in line. A seat you were offered is passed on to the next person.
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /events/{id}/waitlist [delete]
+func (ctrl *waitlistController) LeaveWaitlist(c *gin.Context) {
+	eventID, userID, ok := waitlistParams(c)
+	if !ok {
+		return
+	}
+
+	oldEntry, err := ctrl.waitlistService.GetMyEntry(eventID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "You are not on the waitlist for this event"})
+		return
+	}
+
+	oldEntryJSON, _ := json.Marshal(oldEntry)
+
+	err = ctrl.waitlistService.LeaveWaitlist(eventID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log leaving the waitlist in the audit trail
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		userID,
+		entity.ActionDelete,
+		"waitlist_entry",
+		oldEntry.ID,
+		string(oldEntryJSON),
+		nil,
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left the waitlist successfully"})
+}
+
+// GetMyWaitlistEntry godoc
+// @Summary Get your place on the waitlist
+// @Description Get your waitlist entry for an event with your position in line, or the seat offer waiting to be claimed
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security BearerAuth
+// @Success 200 {object} entity.WaitlistEntry
+// @Failure 404 {object} map[string]interface{}
+// @Router /events/{id}/waitlist/me [get]
+func (ctrl *waitlistController) GetMyWaitlistEntry(c *gin.Context) {
+	eventID, userID, ok := waitlistParams(c)
+	if !ok {
+		return
+	}
+
+	entry, err := ctrl.waitlistService.GetMyEntry(eventID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "You are not on the waitlist for this event"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// ClaimOffer godoc
+// @Summary Claim an offered seat
+// @Description Hold the seat offered to you from the waitlist and start its payment before the offer expires
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security BearerAuth
+// @Success 201 {object} map[string]interface{}
+// @Failure 400,409 {object} map[string]interface{}
+// @Router /events/{id}/waitlist/claim [post]
+func (ctrl *waitlistController) ClaimOffer(c *gin.Context) {
+	eventID, userID, ok := waitlistParams(c)
+	if !ok {
+		return
+	}
+
+	ticket, payment, err := ctrl.waitlistService.ClaimOffer(eventID, userID)
+	if err != nil {
+		c.JSON(purchaseErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Log the claimed ticket in the audit trail
+	newTicket, _ := json.Marshal(ticket)
+	ipAddress := c.ClientIP()
+	userAgent := c.Request.UserAgent()
+
+	go ctrl.auditService.LogActivity(
+		userID,
+		entity.ActionCreate,
+		"ticket",
+		ticket.ID,
+		nil,
+		string(newTicket),
+		ipAddress,
+		userAgent,
+	)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Seat claimed, awaiting payment", "ticket_id": ticket.ID, "expires_at": ticket.ExpiresAt, "payment": payment})
+}
+
+// GetEventWaitlist godoc
+// @Summary Get the waitlist of an event
+// @Description Get everyone in line for an event with their position, including open seat offers
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Event ID"
+// @Security BearerAuth
+// @Success 200 {array} entity.WaitlistEntry
+// @Failure 400,404 {object} map[string]interface{}
+// @Router /events/{id}/waitlist [get]
+func (ctrl *waitlistController) GetEventWaitlist(c *gin.Context) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	entries, err := ctrl.waitlistService.GetEventWaitlist(uint(eventID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": entries})
+}
+
+// waitlistParams reads the event ID from the path and the user ID from the token.
+// It writes the error response and returns false when either is missing.
+func waitlistParams(c *gin.Context) (uint, uint, bool) {
+	eventID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return 0, 0, false
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return 0, 0, false
+	}
+
+	id, ok := userID.(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return 0, 0, false
+	}
+
+	return uint(eventID), uint(id), true
+}
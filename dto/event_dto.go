@@ -47,12 +47,25 @@ type EventDetailResponse struct {
 
 // EventFilterRequest represents filters for event listing
 type EventFilterRequest struct {
-	Name      string `form:"name"`
-	StartDate string `form:"start_date"`
-	EndDate   string `form:"end_date"`
-	Location  string `form:"location"`
-	Page      int    `form:"page,default=1"`
-	Limit     int    `form:"limit,default=10"`
+	Name      string   `form:"name"`
+	StartDate string   `form:"start_date"` // Events starting on or after this day (YYYY-MM-DD)
+	EndDate   string   `form:"end_date"`   // Events starting on or before this day (YYYY-MM-DD)
+	Location  string   `form:"location"`
+	VenueID   *uint    `form:"venue_id"`
+	City      string   `form:"city"` // City of the venue
+	SeriesID  *uint    `form:"series_id"`
+	Category  string   `form:"category"` // Slug or name of a category
+	Tag       string   `form:"tag"`      // Slug or name of a tag
+	Status    string   `form:"status"`
+	MinPrice  *float64 `form:"min_price"`
+	MaxPrice  *float64 `form:"max_price"`
+	Available bool     `form:"available"`
+	Sort      string   `form:"sort"` // start_date, -start_date, price, -price or popularity
+	Page      int      `form:"page,default=1"`
+	Limit     int      `form:"limit,default=10"`
+
+	// Set for admins, who also see drafts and events yet to be published
+	Drafts bool `form:"-"`
 }
 
 // EventListResponse represents paginated list of events
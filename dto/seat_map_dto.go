@@ -0,0 +1,33 @@
+package dto
+
+// SeatMapCreateRequest describes a seat map as sections made of rows of seats
+type SeatMapCreateRequest struct {
+	Name     string               `json:"name" binding:"required"`
+	VenueID  *uint                `json:"venue_id"` // Shared by the venue's events when set
+	Sections []SeatSectionRequest `json:"sections" binding:"required,min=1,dive"`
+}
+
+// SeatSectionRequest is a section of a seat map with the category and price
+// its seats are sold at
+type SeatSectionRequest struct {
+	Name     string           `json:"name" binding:"required"`
+	Category string           `json:"category"`
+	Price    float64          `json:"price"`
+	Rows     []SeatRowRequest `json:"rows" binding:"required,min=1,dive"`
+}
+
+// SeatRowRequest is a row of a section. Its seats are either listed or
+// numbered 1 to seat_count.
+type SeatRowRequest struct {
+	Label     string        `json:"label" binding:"required"`
+	SeatCount int           `json:"seat_count"`
+	Seats     []SeatRequest `json:"seats" binding:"dive"`
+}
+
+// SeatRequest is a single seat, optionally in another category or at another
+// price than the rest of its section
+type SeatRequest struct {
+	Number   string   `json:"number" binding:"required"`
+	Category string   `json:"category"`
+	Price    *float64 `json:"price"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+)
+
+// Category groups events by kind, such as concerts or workshops. An event can
+// be in several categories.
+type Category struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"size:100;not null;unique" json:"name"`
+	Slug        string    `gorm:"size:100;not null;unique" json:"slug"` // Derived from the name, used to filter events
+	Description string    `gorm:"type:text" json:"description"`
+	EventCount  int64     `gorm:"->;-:migration" json:"event_count"` // Upcoming public events, only filled in listings
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Tag is a free-form label such as "outdoor" or "family friendly"
+type Tag struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Name       string    `gorm:"size:50;not null;unique" json:"name"`
+	Slug       string    `gorm:"size:50;not null;unique" json:"slug"`
+	EventCount int64     `gorm:"->;-:migration" json:"event_count"` // Upcoming public events, only filled in listings
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -1,28 +1,82 @@
 package entity
 
 import (
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type EventStatus string
 
 const (
-	EventStatusActive   EventStatus = "active"
-	EventStatusOngoing  EventStatus = "ongoing"
-	EventStatusFinished EventStatus = "finished"
+	EventStatusActive    EventStatus = "active"
+	EventStatusOngoing   EventStatus = "ongoing"
+	EventStatusFinished  EventStatus = "finished"
+	EventStatusCancelled EventStatus = "cancelled"
+	EventStatusPostponed EventStatus = "postponed"
 )
 
+// OpenEventStatuses are the statuses of events that have not started yet and
+// still sell tickets. A postponed event keeps selling for its new dates.
+var OpenEventStatuses = []EventStatus{EventStatusActive, EventStatusPostponed}
+
 type Event struct {
 	ID          uint        `gorm:"primaryKey" json:"id"`
-	Name        string      `gorm:"size:255;not null;unique" json:"name"`
+	Name        string      `gorm:"size:255;not null" json:"name"` // Unique outside of series, whose occurrences share a name
 	Description string      `gorm:"type:text" json:"description"`
 	Location    string      `gorm:"size:255;not null" json:"location"`
+	VenueID     *uint       `gorm:"index" json:"venue_id,omitempty"`
+	Venue       *Venue      `gorm:"foreignKey:VenueID" json:"venue,omitempty"`
+	SeatMapID   *uint       `gorm:"index" json:"seat_map_id,omitempty"` // Set for events with reserved seating
 	StartDate   time.Time   `gorm:"not null" json:"start_date"`
 	EndDate     time.Time   `gorm:"not null" json:"end_date"`
 	Capacity    int         `gorm:"not null" json:"capacity"`
 	Price       float64     `gorm:"not null" json:"price"`
+	SoldCount   int         `gorm:"not null;default:0" json:"sold_count"` // Seats currently taken, kept in step with tickets
 	Status      EventStatus `gorm:"size:50;not null;default:active" json:"status"`
 	CreatedAt   time.Time   `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
 	Tickets     []Ticket    `gorm:"foreignKey:EventID" json:"tickets,omitempty"`
-} 
\ No newline at end of file
+	Categories  []Category  `gorm:"many2many:event_categories" json:"categories,omitempty"`
+	Tags        []Tag       `gorm:"many2many:event_tags" json:"tags,omitempty"`
+	Media       []Media     `gorm:"foreignKey:EventID" json:"media,omitempty"` // Gallery in display order, only loaded with a single event
+
+	// Purchase rules that keep tickets out of the hands of scalpers, zero means no limit
+	MaxTicketsPerUser       int `gorm:"not null;default:0" json:"max_tickets_per_user"`      // Seats one buyer may hold for the event
+	MaxTicketsPerOrder      int `gorm:"not null;default:0" json:"max_tickets_per_order"`     // Seats one purchase may take
+	PurchaseCooldownSeconds int `gorm:"not null;default:0" json:"purchase_cooldown_seconds"` // Wait between purchases by the same buyer
+
+	// The series this event is an occurrence of and the day it was generated for
+	SeriesID   *uint  `gorm:"index" json:"series_id,omitempty"`
+	SeriesDate string `gorm:"size:10" json:"series_date,omitempty"`
+
+	// The lower cased name of events outside of a series, so the database
+	// rejects duplicates even when two events are created at once
+	StandaloneName *string `gorm:"size:255;uniqueIndex" json:"-"`
+
+	// Drafts are only visible to admins, and published events go public at
+	// PublishAt when it is set. Tickets sell between SalesStart and SalesEnd,
+	// from publication until the event starts when they are not set.
+	Draft      bool       `gorm:"not null;default:false" json:"draft"`
+	PublishAt  *time.Time `json:"publish_at,omitempty"`
+	SalesStart *time.Time `json:"sales_start,omitempty"`
+	SalesEnd   *time.Time `json:"sales_end,omitempty"`
+
+	// Why the event was cancelled or postponed, when it was first due to start
+	// and when it was last postponed
+	StatusReason      string     `gorm:"type:text" json:"status_reason,omitempty"`
+	OriginalStartDate *time.Time `json:"original_start_date,omitempty"`
+	PostponedAt       *time.Time `json:"postponed_at,omitempty"`
+}
+
+// BeforeSave keeps StandaloneName in step with the name. Occurrences of a
+// series leave it empty as they share the name of their series.
+func (e *Event) BeforeSave(tx *gorm.DB) error {
+	e.StandaloneName = nil
+	if e.SeriesID == nil {
+		name := strings.ToLower(e.Name)
+		e.StandaloneName = &name
+	}
+	return nil
+}
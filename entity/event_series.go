@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily   RecurrenceFrequency = "daily"
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceMonthly RecurrenceFrequency = "monthly"
+)
+
+// EventSeries is a recurring event such as a weekly workshop. Its occurrences
+// are ordinary events with their own capacity and tickets, generated from the
+// recurrence rule: every Interval days, weeks or months from StartDate until
+// Until or for Count occurrences, skipping the Exceptions. Monthly series skip
+// months that do not have the day of the month the series started on.
+type EventSeries struct {
+	ID              uint                `gorm:"primaryKey" json:"id"`
+	Name            string              `gorm:"size:255;not null;unique" json:"name"`
+	Description     string              `gorm:"type:text" json:"description"`
+	Location        string              `gorm:"size:255" json:"location"`
+	VenueID         *uint               `gorm:"index" json:"venue_id,omitempty"`
+	Capacity        int                 `gorm:"not null" json:"capacity"` // Of every occurrence
+	Price           float64             `gorm:"not null" json:"price"`
+	Frequency       RecurrenceFrequency `gorm:"size:20;not null" json:"frequency"`
+	Interval        int                 `gorm:"not null;default:1" json:"interval"`
+	StartDate       time.Time           `gorm:"not null" json:"start_date"` // Start of the first occurrence
+	DurationMinutes int                 `gorm:"not null" json:"duration_minutes"`
+	Until           *time.Time          `json:"until,omitempty"`                             // Last day an occurrence may start on
+	Count           int                 `gorm:"not null;default:0" json:"count,omitempty"`   // Occurrences when Until is not set, exceptions included
+	Exceptions      []string            `gorm:"type:text;serializer:json" json:"exceptions"` // Skipped days (YYYY-MM-DD)
+	CreatedAt       time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+)
+
+type IdempotencyStatus string
+
+const (
+	IdempotencyStatusProcessing IdempotencyStatus = "processing"
+	IdempotencyStatusCompleted  IdempotencyStatus = "completed"
+)
+
+// IdempotencyKey remembers the response to a request sent with an
+// Idempotency-Key header so a retry of the same request gets the same answer
+// instead of being carried out twice
+type IdempotencyKey struct {
+	ID             uint              `gorm:"primaryKey" json:"id"`
+	UserID         uint              `gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key" json:"user_id"`
+	Key            string            `gorm:"size:255;not null;uniqueIndex:idx_idempotency_keys_user_key" json:"key"`
+	RequestHash    string            `gorm:"size:64;not null" json:"request_hash"` // SHA-256 of the method, path and body
+	Status         IdempotencyStatus `gorm:"size:50;not null;default:processing" json:"status"`
+	ResponseStatus int               `json:"response_status"`
+	ResponseBody   string            `gorm:"type:text" json:"response_body"`
+	ExpiresAt      time.Time         `gorm:"not null;index" json:"expires_at"`
+	CreatedAt      time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+)
+
+// Media is an image in the gallery of an event. The file itself is kept by
+// the file service under the events upload type.
+type Media struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	EventID   uint      `gorm:"not null;index" json:"event_id"`
+	Filename  string    `gorm:"size:255;not null" json:"filename"`
+	Caption   string    `gorm:"size:255" json:"caption"`
+	Position  int       `gorm:"not null;default:0" json:"position"`     // Place in the gallery, lowest first
+	IsCover   bool      `gorm:"not null;default:false" json:"is_cover"` // At most one image per event is its cover
+	URL       string    `gorm:"-" json:"url"`                           // Filled in when the media is returned
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusCompleted OrderStatus = "completed"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+// Order groups the tickets bought together in a single checkout
+type Order struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	UserID      uint        `gorm:"not null;index" json:"user_id"`
+	Status      OrderStatus `gorm:"size:50;not null;default:pending" json:"status"`
+	TotalAmount float64     `gorm:"not null" json:"total_amount"`
+	CreatedAt   time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+	User        User        `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Items       []OrderItem `gorm:"foreignKey:OrderID" json:"items"`
+	Tickets     []Ticket    `gorm:"foreignKey:OrderID" json:"tickets,omitempty"`
+}
+
+// OrderItem is a line of an order: a quantity of tickets for one event, or a
+// single seat at events with reserved seating
+type OrderItem struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	OrderID   uint        `gorm:"not null;index" json:"order_id"`
+	EventID   uint        `gorm:"not null" json:"event_id"`
+	TierID    *uint       `json:"tier_id,omitempty"`
+	SeatID    *uint       `json:"seat_id,omitempty"`
+	Quantity  int         `gorm:"not null" json:"quantity"`
+	UnitPrice float64     `gorm:"not null" json:"unit_price"`
+	Subtotal  float64     `gorm:"not null" json:"subtotal"`
+	Event     Event       `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	Tier      *TicketTier `gorm:"foreignKey:TierID" json:"tier,omitempty"`
+	Seat      *Seat       `gorm:"foreignKey:SeatID" json:"seat,omitempty"`
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "pending"
+	PaymentStatusSucceeded PaymentStatus = "succeeded"
+	PaymentStatusFailed    PaymentStatus = "failed"
+	PaymentStatusRefunded  PaymentStatus = "refunded"
+)
+
+// Payment tracks a charge at the payment gateway for a single ticket or a whole order
+type Payment struct {
+	ID           uint          `gorm:"primaryKey" json:"id"`
+	UserID       uint          `gorm:"not null;index" json:"user_id"`
+	TicketID     *uint         `gorm:"index" json:"ticket_id,omitempty"`
+	OrderID      *uint         `gorm:"index" json:"order_id,omitempty"`
+	Amount       float64       `gorm:"not null" json:"amount"`
+	Currency     string        `gorm:"size:3;not null" json:"currency"`
+	Provider     string        `gorm:"size:50;not null" json:"provider"`
+	IntentID     string        `gorm:"size:255;index" json:"intent_id"`
+	ClientSecret string        `gorm:"-" json:"client_secret,omitempty"` // Only returned when the payment is started
+	Status       PaymentStatus `gorm:"size:50;not null;default:pending" json:"status"`
+	CreatedAt    time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
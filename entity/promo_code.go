@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+)
+
+type DiscountType string
+
+const (
+	DiscountTypePercentage DiscountType = "percentage"
+	DiscountTypeFixed      DiscountType = "fixed"
+)
+
+// PromoCode is a discount buyers can apply when purchasing a ticket. A zero
+// usage cap means the code can be used without limit.
+type PromoCode struct {
+	ID             uint         `gorm:"primaryKey" json:"id"`
+	Code           string       `gorm:"size:50;not null;unique" json:"code"`
+	Description    string       `gorm:"size:255" json:"description"`
+	DiscountType   DiscountType `gorm:"size:20;not null" json:"discount_type"`
+	DiscountValue  float64      `gorm:"not null" json:"discount_value"`  // Percent off, or amount off for fixed discounts
+	EventID        *uint        `gorm:"index" json:"event_id,omitempty"` // Only valid for this event when set
+	MaxUses        int          `gorm:"not null;default:0" json:"max_uses"`
+	MaxUsesPerUser int          `gorm:"not null;default:0" json:"max_uses_per_user"`
+	UsedCount      int          `gorm:"not null;default:0" json:"used_count"` // Tickets currently holding a seat with this code
+	ValidFrom      *time.Time   `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time   `json:"valid_until,omitempty"`
+	CreatedAt      time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+)
+
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+// Refund records money returned for a cancelled ticket
+type Refund struct {
+	ID               uint         `gorm:"primaryKey" json:"id"`
+	PaymentID        uint         `gorm:"not null;index" json:"payment_id"`
+	TicketID         uint         `gorm:"not null;index" json:"ticket_id"`
+	EventID          uint         `gorm:"not null;index" json:"event_id"`
+	UserID           uint         `gorm:"not null;index" json:"user_id"`
+	TicketAmount     float64      `gorm:"not null" json:"ticket_amount"` // What was paid for the ticket
+	Percentage       float64      `gorm:"not null" json:"percentage"`
+	Amount           float64      `gorm:"not null" json:"amount"`
+	Reason           string       `gorm:"size:255" json:"reason"`
+	ProviderRefundID string       `gorm:"size:255" json:"provider_refund_id,omitempty"`
+	Status           RefundStatus `gorm:"size:50;not null;default:pending" json:"status"`
+	CreatedAt        time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CancellationPolicyRule refunds a percentage of the ticket price when a ticket
+// is cancelled at least HoursBeforeStart hours before the event begins
+type CancellationPolicyRule struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	EventID          uint      `gorm:"not null;index" json:"event_id"`
+	HoursBeforeStart int       `gorm:"not null" json:"hours_before_start"`
+	RefundPercentage float64   `gorm:"not null" json:"refund_percentage"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+)
+
+// SeatMap lays out the reserved seats of a venue, shared by the events held
+// there, or of a single event when it has no venue
+type SeatMap struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:255;not null" json:"name"`
+	VenueID   *uint     `gorm:"index" json:"venue_id,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	Seats     []Seat    `gorm:"foreignKey:SeatMapID" json:"seats,omitempty"`
+}
+
+// Seat is one place on a seat map, identified by its section, row and number.
+// Its category and price are resolved from its section when the map is created.
+type Seat struct {
+	ID        uint    `gorm:"primaryKey" json:"id"`
+	SeatMapID uint    `gorm:"not null;uniqueIndex:idx_seats_position" json:"seat_map_id"`
+	Section   string  `gorm:"size:100;not null;uniqueIndex:idx_seats_position" json:"section"`
+	Row       string  `gorm:"column:row_label;size:20;not null;uniqueIndex:idx_seats_position" json:"row"`
+	Number    string  `gorm:"size:20;not null;uniqueIndex:idx_seats_position" json:"number"`
+	Category  string  `gorm:"size:100" json:"category"`
+	Price     float64 `gorm:"not null" json:"price"`
+	Available *bool   `gorm:"-" json:"available,omitempty"` // Only filled in when looking at an event's seats
+}
+
+// SeatReservation marks a seat of an event as taken by a ticket. It exists
+// only while the ticket holds the seat, and the unique index lets the database
+// refuse a second ticket for the same seat.
+type SeatReservation struct {
+	ID        uint      `gorm:"primaryKey"`
+	EventID   uint      `gorm:"not null;uniqueIndex:idx_seat_reservations_event_seat"`
+	SeatID    uint      `gorm:"not null;uniqueIndex:idx_seat_reservations_event_seat"`
+	TicketID  uint      `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
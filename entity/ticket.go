@@ -8,18 +8,38 @@ type TicketStatus string
 
 const (
 	TicketStatusAvailable  TicketStatus = "available"
+	TicketStatusReserved   TicketStatus = "reserved"
 	TicketStatusPurchased  TicketStatus = "purchased"
 	TicketStatusCancelled  TicketStatus = "cancelled"
+	TicketStatusExpired    TicketStatus = "expired"
 )
 
+// SeatTakingStatuses are the ticket statuses that occupy a seat of the event
+var SeatTakingStatuses = []TicketStatus{TicketStatusReserved, TicketStatusPurchased}
+
 type Ticket struct {
-	ID        uint         `gorm:"primaryKey" json:"id"`
-	UserID    uint         `gorm:"not null" json:"user_id"`
-	EventID   uint         `gorm:"not null" json:"event_id"`
-	Status    TicketStatus `gorm:"size:50;not null;default:purchased" json:"status"`
-	PurchasedAt time.Time  `gorm:"not null" json:"purchased_at"`
-	CreatedAt time.Time    `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
-	User      User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Event     Event        `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	UserID      uint         `gorm:"not null" json:"user_id"`
+	EventID     uint         `gorm:"not null" json:"event_id"`
+	OrderID     *uint        `gorm:"index" json:"order_id,omitempty"`
+	TierID      *uint        `gorm:"index" json:"tier_id,omitempty"`
+	SeatID      *uint        `gorm:"index" json:"seat_id,omitempty"` // Assigned seat at events with reserved seating
+	PromoCodeID *uint        `gorm:"index" json:"promo_code_id,omitempty"`
+	PromoCode   string       `gorm:"-" json:"promo_code,omitempty"` // Code entered at purchase, resolved to PromoCodeID
+	UnitPrice   float64      `gorm:"not null;default:0" json:"unit_price"` // List price at the time of purchase
+	Discount    float64      `gorm:"not null;default:0" json:"discount"`
+	Fee         float64      `gorm:"not null;default:0" json:"fee"`
+	PricePaid   float64      `gorm:"not null;default:0" json:"price_paid"` // Unit price less the discount plus the fee
+	Currency    string       `gorm:"size:3;not null;default:''" json:"currency"`
+	Status      TicketStatus `gorm:"size:50;not null;default:purchased" json:"status"`
+	PurchasedAt time.Time    `gorm:"not null" json:"purchased_at"`
+	ExpiresAt   *time.Time   `gorm:"index" json:"expires_at,omitempty"` // Only set while the ticket is a reserved hold
+	CheckedInAt *time.Time   `json:"checked_in_at,omitempty"`
+	CheckedInBy *uint        `json:"checked_in_by,omitempty"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+	User        User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Event       Event        `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	Tier        *TicketTier  `gorm:"foreignKey:TierID" json:"tier,omitempty"`
+	Seat        *Seat        `gorm:"foreignKey:SeatID" json:"seat,omitempty"`
 } 
\ No newline at end of file
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+)
+
+// TicketTier is a priced allocation of an event's capacity, such as VIP or Early Bird
+type TicketTier struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	EventID    uint       `gorm:"not null;uniqueIndex:idx_ticket_tiers_event_name" json:"event_id"`
+	Name       string     `gorm:"size:100;not null;uniqueIndex:idx_ticket_tiers_event_name" json:"name"`
+	Price      float64    `gorm:"not null" json:"price"`
+	Capacity   int        `gorm:"not null" json:"capacity"`
+	SoldCount  int        `gorm:"not null;default:0" json:"sold_count"`
+	SalesStart *time.Time `json:"sales_start,omitempty"`
+	SalesEnd   *time.Time `json:"sales_end,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
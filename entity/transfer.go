@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+)
+
+type TransferStatus string
+
+const (
+	TransferStatusPending   TransferStatus = "pending"
+	TransferStatusAccepted  TransferStatus = "accepted"
+	TransferStatusDeclined  TransferStatus = "declined"
+	TransferStatusCancelled TransferStatus = "cancelled"
+)
+
+// TicketTransfer is an offer by a ticket holder to hand their ticket to another
+// user. The ticket changes owner once the recipient accepts.
+type TicketTransfer struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	TicketID   uint           `gorm:"not null;index" json:"ticket_id"`
+	FromUserID uint           `gorm:"not null;index" json:"from_user_id"`
+	ToUserID   uint           `gorm:"not null;index" json:"to_user_id"`
+	Status     TransferStatus `gorm:"size:50;not null;default:pending" json:"status"`
+	AcceptedAt *time.Time     `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	Ticket     Ticket         `gorm:"foreignKey:TicketID" json:"ticket,omitempty"`
+	FromUser   User           `gorm:"foreignKey:FromUserID" json:"from_user,omitempty"`
+	ToUser     User           `gorm:"foreignKey:ToUserID" json:"to_user,omitempty"`
+}
@@ -11,6 +11,7 @@ type Role string
 
 const (
 	RoleAdmin Role = "admin"
+	RoleStaff Role = "staff" // Door staff who check attendees in
 	RoleUser  Role = "user"
 )
 
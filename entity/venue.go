@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+)
+
+// Venue is a place events are held at. Events linked to a venue cannot seat
+// more people than its max capacity.
+type Venue struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"size:255;not null;unique" json:"name"`
+	Address     string    `gorm:"size:255;not null" json:"address"`
+	City        string    `gorm:"size:100;not null;index" json:"city"`
+	Latitude    *float64  `json:"latitude,omitempty"`
+	Longitude   *float64  `json:"longitude,omitempty"`
+	Timezone    string    `gorm:"size:64;not null" json:"timezone"` // IANA name such as Asia/Jakarta
+	MaxCapacity int       `gorm:"not null" json:"max_capacity"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+)
+
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting WaitlistStatus = "waiting"
+	WaitlistStatusOffered WaitlistStatus = "offered"
+	WaitlistStatusClaimed WaitlistStatus = "claimed"
+	WaitlistStatusExpired WaitlistStatus = "expired"
+	WaitlistStatusLeft    WaitlistStatus = "left"
+	WaitlistStatusClosed  WaitlistStatus = "closed" // The event was cancelled
+)
+
+// WaitlistEntry is a user's place in line for a sold out event. When a seat is
+// freed the first waiting entry is offered it for a limited time.
+type WaitlistEntry struct {
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	EventID        uint           `gorm:"not null;index" json:"event_id"`
+	UserID         uint           `gorm:"not null;index" json:"user_id"`
+	Status         WaitlistStatus `gorm:"size:50;not null;default:waiting" json:"status"`
+	OfferTierID    *uint          `json:"offer_tier_id,omitempty"` // Tier of the seat being offered
+	OfferExpiresAt *time.Time     `gorm:"index" json:"offer_expires_at,omitempty"`
+	TicketID       *uint          `json:"ticket_id,omitempty"` // Ticket issued when the offer was claimed
+	Position       int64          `gorm:"-" json:"position,omitempty"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	User           User           `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// ActiveWaitlistStatuses are the statuses of entries still in line or holding an offer
+var ActiveWaitlistStatuses = []WaitlistStatus{WaitlistStatusWaiting, WaitlistStatusOffered}
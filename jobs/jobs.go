@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+// Every runs task in the background once per interval for the lifetime of
+// the process. Errors are logged and the task is retried on the next tick.
+func Every(interval time.Duration, name string, task func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := task(); err != nil {
+				log.Printf("Background job %q failed: %v", name, err)
+			}
+		}
+	}()
+}
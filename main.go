@@ -3,13 +3,16 @@ package main
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/taufikmulyawan/ticketing-system/config"
 	"github.com/taufikmulyawan/ticketing-system/controller"
 	_ "github.com/taufikmulyawan/ticketing-system/docs"
+	"github.com/taufikmulyawan/ticketing-system/jobs"
 	"github.com/taufikmulyawan/ticketing-system/repository"
 	"github.com/taufikmulyawan/ticketing-system/router"
 	"github.com/taufikmulyawan/ticketing-system/service"
+	"github.com/taufikmulyawan/ticketing-system/storage"
 )
 
 func main() {
@@ -21,13 +24,44 @@ func main() {
 	// Connect to database
 	config.ConnectDatabase()
 
+	// Select the payment provider
+	gateway, err := service.NewPaymentGateway(config.AppConfig)
+	if err != nil {
+		log.Fatalf("Failed to set up the payment gateway: %v", err)
+	}
+
+	// Ticket QR codes are signed with their own secret
+	if err := service.CheckTicketSigningSecret(config.AppConfig); err != nil {
+		log.Fatalf("Failed to set up ticket signing: %v", err)
+	}
+
+	// Select where uploaded files are kept
+	fileStorage, err := storage.NewDriver(config.AppConfig)
+	if err != nil {
+		log.Fatalf("Failed to set up file storage: %v", err)
+	}
+
 	// Initialize all application components
 	repositories := repository.InitRepositories()
-	services := service.InitServices(repositories)
+	services := service.InitServices(repositories, gateway, fileStorage)
 	controllers := controller.InitControllers(services)
 
+	// Load existing events into the search index
+	if err := services.EventService.RebuildSearchIndex(); err != nil {
+		log.Fatalf("Failed to build the event search index: %v", err)
+	}
+
+	// Start background jobs
+	jobs.Every(config.AppConfig.HoldSweepInterval, "release expired ticket holds", services.TicketService.ReleaseExpiredHolds)
+	jobs.Every(config.AppConfig.HoldSweepInterval, "roll over expired waitlist offers", services.WaitlistService.ExpireOffers)
+	jobs.Every(config.AppConfig.EventLifecycleInterval, "advance event lifecycle", func() error {
+		_, err := services.EventService.AdvanceLifecycle(0)
+		return err
+	})
+	jobs.Every(time.Hour, "purge expired idempotency keys", services.IdempotencyService.PurgeExpired)
+
 	// Setup router
-	r := router.InitRouter(controllers, services.AuditService)
+	r := router.InitRouter(controllers, services.AuditService, services.IdempotencyService)
 
 	// Start the server
 	port := config.AppConfig.Port
@@ -44,6 +44,23 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// OptionalAuthMiddleware identifies the user of public routes that show more
+// to signed in users. Requests without a valid token carry on anonymously.
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := validateToken(parts[1]); err == nil {
+				c.Set("user_id", claims["id"])
+				c.Set("user_email", claims["email"])
+				c.Set("user_role", claims["role"])
+			}
+		}
+
+		c.Next()
+	}
+}
+
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// First apply the auth middleware
@@ -71,6 +88,34 @@ func AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
+// StaffMiddleware lets door staff and admins through
+func StaffMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// First apply the auth middleware
+		AuthMiddleware()(c)
+		if c.IsAborted() {
+			return
+		}
+
+		// Check if user has a staff or admin role
+		userRole, exists := c.Get("user_role")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		role, ok := userRole.(string)
+		if !ok || (role != string(entity.RoleStaff) && role != string(entity.RoleAdmin)) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Staff access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 func validateToken(tokenString string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// Validate the signing method
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+// IdempotencyMiddleware makes retries of a request carrying an Idempotency-Key
+// header safe. The first request with a key is handled normally and its
+// response stored; repeats by the same user get the stored response back
+// instead of being carried out again. It must run after AuthMiddleware.
+func IdempotencyMiddleware(idempotencyService service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		uID, ok := userID.(float64)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		// Read the body to fingerprint the request and restore it for the handler
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record, err := idempotencyService.Begin(uint(uID), key, requestHash(c.Request.Method, c.Request.URL.Path, body))
+		if err != nil {
+			status := http.StatusBadRequest
+			switch {
+			case errors.Is(err, service.ErrIdempotencyKeyReused):
+				status = http.StatusUnprocessableEntity
+			case errors.Is(err, service.ErrIdempotencyKeyInProgress):
+				status = http.StatusConflict
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		if record.Status == entity.IdempotencyStatusCompleted {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.ResponseStatus, "application/json; charset=utf-8", []byte(record.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// Free the key if the handler panics so the request can be retried
+		finished := false
+		defer func() {
+			if !finished {
+				idempotencyService.Release(record)
+			}
+		}()
+
+		c.Next()
+
+		// Server errors are not stored so the client can retry them
+		if recorder.Status() >= http.StatusInternalServerError {
+			idempotencyService.Release(record)
+		} else {
+			idempotencyService.Complete(record, recorder.Status(), recorder.body.Bytes())
+		}
+		finished = true
+	}
+}
+
+// requestHash fingerprints a request so a key reused for a different one can be detected
+func requestHash(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body while writing it to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -31,6 +31,12 @@ func GenerateSalesSummaryPDF(summary *types.SalesSummary) ([]byte, error) {
 	pdf.Ln(8)
 	pdf.Cell(40, 10, fmt.Sprintf("Total Tickets Sold: %d", summary.TotalTickets))
 	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Total Discounts: Rp %.2f", summary.TotalDiscounts))
+	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Total Fees: Rp %.2f", summary.TotalFees))
+	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Total Refunds: Rp %.2f", summary.TotalRefunds))
+	pdf.Ln(8)
 	pdf.Cell(40, 10, fmt.Sprintf("Total Revenue: Rp %.2f", summary.TotalRevenue))
 	pdf.Ln(15)
 	
@@ -42,8 +48,9 @@ func GenerateSalesSummaryPDF(summary *types.SalesSummary) ([]byte, error) {
 	// Table header
 	pdf.SetFont("Arial", "B", 10)
 	pdf.Cell(10, 10, "ID")
-	pdf.Cell(80, 10, "Event Name")
+	pdf.Cell(70, 10, "Event Name")
 	pdf.Cell(30, 10, "Tickets Sold")
+	pdf.Cell(30, 10, "Refunds")
 	pdf.Cell(30, 10, "Revenue")
 	pdf.Ln(8)
 	
@@ -51,8 +58,9 @@ func GenerateSalesSummaryPDF(summary *types.SalesSummary) ([]byte, error) {
 	pdf.SetFont("Arial", "", 10)
 	for _, event := range summary.EventSummary {
 		pdf.Cell(10, 10, fmt.Sprintf("%d", event.EventID))
-		pdf.Cell(80, 10, event.EventName)
+		pdf.Cell(70, 10, event.EventName)
 		pdf.Cell(30, 10, fmt.Sprintf("%d", event.TotalTickets))
+		pdf.Cell(30, 10, fmt.Sprintf("Rp %.2f", event.TotalRefunds))
 		pdf.Cell(30, 10, fmt.Sprintf("Rp %.2f", event.TotalRevenue))
 		pdf.Ln(8)
 	}
@@ -92,9 +100,40 @@ func GenerateEventSalesPDF(summary *types.EventSalesSummary) ([]byte, error) {
 	pdf.Ln(8)
 	pdf.Cell(40, 10, fmt.Sprintf("Total Tickets Sold: %d", summary.TotalTickets))
 	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Total Discounts: Rp %.2f", summary.TotalDiscounts))
+	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Total Fees: Rp %.2f", summary.TotalFees))
+	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Total Refunds: Rp %.2f", summary.TotalRefunds))
+	pdf.Ln(8)
 	pdf.Cell(40, 10, fmt.Sprintf("Total Revenue: Rp %.2f", summary.TotalRevenue))
 	pdf.Ln(15)
 	
+	// Tier breakdown
+	if len(summary.TierSummary) > 0 {
+		pdf.SetFont("Arial", "B", 12)
+		pdf.Cell(40, 10, "Ticket Tiers")
+		pdf.Ln(10)
+		
+		pdf.SetFont("Arial", "B", 10)
+		pdf.Cell(60, 10, "Tier")
+		pdf.Cell(30, 10, "Price")
+		pdf.Cell(30, 10, "Tickets Sold")
+		pdf.Cell(30, 10, "Refunds")
+		pdf.Cell(30, 10, "Revenue")
+		pdf.Ln(8)
+		
+		pdf.SetFont("Arial", "", 10)
+		for _, tier := range summary.TierSummary {
+			pdf.Cell(60, 10, tier.TierName)
+			pdf.Cell(30, 10, fmt.Sprintf("Rp %.2f", tier.Price))
+			pdf.Cell(30, 10, fmt.Sprintf("%d", tier.TotalTickets))
+			pdf.Cell(30, 10, fmt.Sprintf("Rp %.2f", tier.TotalRefunds))
+			pdf.Cell(30, 10, fmt.Sprintf("Rp %.2f", tier.TotalRevenue))
+			pdf.Ln(8)
+		}
+	}
+	
 	pdf.Ln(10)
 	pdf.SetFont("Arial", "I", 8)
 	pdf.Cell(0, 10, fmt.Sprintf("Generated on %s", time.Now().Format("2006-01-02 15:04:05")))
@@ -114,7 +153,7 @@ func GenerateSalesSummaryCSV(summary *types.SalesSummary) ([]byte, error) {
 	writer := csv.NewWriter(buf)
 	
 	// Write headers
-	headers := []string{"Event ID", "Event Name", "Tickets Sold", "Revenue (Rp)"}
+	headers := []string{"Event ID", "Event Name", "Tickets Sold", "Discounts (Rp)", "Fees (Rp)", "Refunds (Rp)", "Revenue (Rp)"}
 	if err := writer.Write(headers); err != nil {
 		return nil, err
 	}
@@ -125,6 +164,9 @@ func GenerateSalesSummaryCSV(summary *types.SalesSummary) ([]byte, error) {
 			strconv.FormatUint(uint64(event.EventID), 10),
 			event.EventName,
 			strconv.FormatInt(event.TotalTickets, 10),
+			fmt.Sprintf("%.2f", event.TotalDiscounts),
+			fmt.Sprintf("%.2f", event.TotalFees),
+			fmt.Sprintf("%.2f", event.TotalRefunds),
 			fmt.Sprintf("%.2f", event.TotalRevenue),
 		}
 		if err := writer.Write(row); err != nil {
@@ -133,11 +175,14 @@ func GenerateSalesSummaryCSV(summary *types.SalesSummary) ([]byte, error) {
 	}
 	
 	// Write summary row
-	writer.Write([]string{"", "", "", ""})
+	writer.Write([]string{"", "", "", "", "", "", ""})
 	writer.Write([]string{
 		"TOTAL",
 		fmt.Sprintf("%d events", summary.TotalEvents),
 		strconv.FormatInt(summary.TotalTickets, 10),
+		fmt.Sprintf("%.2f", summary.TotalDiscounts),
+		fmt.Sprintf("%.2f", summary.TotalFees),
+		fmt.Sprintf("%.2f", summary.TotalRefunds),
 		fmt.Sprintf("%.2f", summary.TotalRevenue),
 	})
 	
@@ -150,20 +195,46 @@ func GenerateSalesSummaryCSV(summary *types.SalesSummary) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// eventSalesColumns is the width of every row of the event sales CSV, so
+// strict readers accept the tier table below the event
+const eventSalesColumns = 8
+
 // GenerateEventSalesCSV creates a CSV report for a specific event
 func GenerateEventSalesCSV(summary *types.EventSalesSummary) ([]byte, error) {
 	buf := &bytes.Buffer{}
 	writer := csv.NewWriter(buf)
 	
 	// Write headers and data for the event
-	writer.Write([]string{"Event ID", "Event Name", "Tickets Sold", "Revenue (Rp)"})
+	writer.Write([]string{"Event ID", "Event Name", "Tickets Sold", "Discounts (Rp)", "Fees (Rp)", "Gross Revenue (Rp)", "Refunds (Rp)", "Revenue (Rp)"})
 	writer.Write([]string{
 		strconv.FormatUint(uint64(summary.EventID), 10),
 		summary.EventName,
 		strconv.FormatInt(summary.TotalTickets, 10),
+		fmt.Sprintf("%.2f", summary.TotalDiscounts),
+		fmt.Sprintf("%.2f", summary.TotalFees),
+		fmt.Sprintf("%.2f", summary.GrossRevenue),
+		fmt.Sprintf("%.2f", summary.TotalRefunds),
 		fmt.Sprintf("%.2f", summary.TotalRevenue),
 	})
 	
+	// Write the tier breakdown, if the event is sold in tiers
+	if len(summary.TierSummary) > 0 {
+		writer.Write(padRow(nil, eventSalesColumns))
+		writer.Write(padRow([]string{"Tier ID", "Tier Name", "Price (Rp)", "Tickets Sold", "Discounts (Rp)", "Fees (Rp)", "Refunds (Rp)", "Revenue (Rp)"}, eventSalesColumns))
+		for _, tier := range summary.TierSummary {
+			writer.Write(padRow([]string{
+				strconv.FormatUint(uint64(tier.TierID), 10),
+				tier.TierName,
+				fmt.Sprintf("%.2f", tier.Price),
+				strconv.FormatInt(tier.TotalTickets, 10),
+				fmt.Sprintf("%.2f", tier.TotalDiscounts),
+				fmt.Sprintf("%.2f", tier.TotalFees),
+				fmt.Sprintf("%.2f", tier.TotalRefunds),
+				fmt.Sprintf("%.2f", tier.TotalRevenue),
+			}, eventSalesColumns))
+		}
+	}
+	
 	writer.Flush()
 	
 	if err := writer.Error(); err != nil {
@@ -171,4 +242,12 @@ func GenerateEventSalesCSV(summary *types.EventSalesSummary) ([]byte, error) {
 	}
 	
 	return buf.Bytes(), nil
-} 
\ No newline at end of file
+}
+
+// padRow fills a row up with empty fields to the given width
+func padRow(row []string, width int) []string {
+	for len(row) < width {
+		row = append(row, "")
+	}
+	return row
+}
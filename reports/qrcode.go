@@ -0,0 +1,10 @@
+package reports
+
+import (
+	"github.com/skip2/go-qrcode"
+)
+
+// GenerateTicketQRCode renders a ticket credential as a PNG QR code for gate scanners
+func GenerateTicketQRCode(token string) ([]byte, error) {
+	return qrcode.Encode(token, qrcode.Medium, 256)
+}
@@ -0,0 +1,71 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+)
+
+// GenerateTicketPDF creates a printable e-ticket with the QR code scanned at the door
+func GenerateTicketPDF(ticket *entity.Ticket, qrCode []byte) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+
+	// Title
+	pdf.SetFont("Arial", "B", 20)
+	pdf.Cell(0, 12, ticket.Event.Name)
+	pdf.Ln(16)
+
+	// Event Details
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(40, 10, "Event Details")
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(40, 10, fmt.Sprintf("Date: %s", ticket.Event.StartDate.Format("Monday, 02 January 2006 15:04")))
+	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Location: %s", ticket.Event.Location))
+	pdf.Ln(15)
+
+	// Ticket Details
+	tier := "General Admission"
+	if ticket.Tier != nil {
+		tier = ticket.Tier.Name
+	}
+
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(40, 10, "Ticket Details")
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "", 12)
+	pdf.Cell(40, 10, fmt.Sprintf("Ticket Holder: %s", ticket.User.Name))
+	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Tier: %s", tier))
+	pdf.Ln(8)
+	if ticket.Seat != nil {
+		pdf.Cell(40, 10, fmt.Sprintf("Seat: Section %s, Row %s, Seat %s", ticket.Seat.Section, ticket.Seat.Row, ticket.Seat.Number))
+		pdf.Ln(8)
+	}
+	pdf.Cell(40, 10, fmt.Sprintf("Ticket Number: %d", ticket.ID))
+	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Purchased: %s", ticket.PurchasedAt.Format("2006-01-02 15:04")))
+	pdf.Ln(15)
+
+	// QR code
+	options := gofpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader("ticket-qr", options, bytes.NewReader(qrCode))
+	pdf.ImageOptions("ticket-qr", 65, pdf.GetY(), 80, 80, true, options, 0, "")
+
+	pdf.SetFont("Arial", "I", 8)
+	pdf.Cell(0, 10, "Present this QR code at the entrance. Each ticket admits one person once.")
+
+	var buf bytes.Buffer
+	err := pdf.Output(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
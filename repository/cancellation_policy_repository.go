@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type CancellationPolicyRepository interface {
+	FindByEventID(eventID uint) ([]entity.CancellationPolicyRule, error)
+	ReplaceForEvent(eventID uint, rules []entity.CancellationPolicyRule) error
+}
+
+type cancellationPolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewCancellationPolicyRepository() CancellationPolicyRepository {
+	return &cancellationPolicyRepository{
+		db: config.DB,
+	}
+}
+
+// FindByEventID returns the event's rules, earliest cancellation window first
+func (r *cancellationPolicyRepository) FindByEventID(eventID uint) ([]entity.CancellationPolicyRule, error) {
+	var rules []entity.CancellationPolicyRule
+	if err := r.db.Where("event_id = ?", eventID).Order("hours_before_start DESC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ReplaceForEvent swaps the whole policy of an event in one go
+func (r *cancellationPolicyRepository) ReplaceForEvent(eventID uint, rules []entity.CancellationPolicyRule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ?", eventID).Delete(&entity.CancellationPolicyRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+}
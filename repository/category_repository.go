@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type CategoryRepository interface {
+	FindAll(at time.Time) ([]entity.Category, error)
+	FindByID(id uint) (*entity.Category, error)
+	FindBySlug(slug string) (*entity.Category, error)
+	FindByIDs(ids []uint) ([]entity.Category, error)
+	Save(category *entity.Category) error
+	Delete(id uint) error
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository() CategoryRepository {
+	return &categoryRepository{
+		db: config.DB,
+	}
+}
+
+// FindAll lists categories by name with the number of events in each that
+// are public at the given time and have not started
+func (r *categoryRepository) FindAll(at time.Time) ([]entity.Category, error) {
+	var categories []entity.Category
+	err := r.db.Model(&entity.Category{}).
+		Select("categories.*, COUNT(events.id) AS event_count").
+		Joins("LEFT JOIN event_categories ON event_categories.category_id = categories.id").
+		Joins("LEFT JOIN events ON events.id = event_categories.event_id AND events.status IN ? AND events.draft = ? AND (events.publish_at IS NULL OR events.publish_at <= ?)", entity.OpenEventStatuses, false, at).
+		Group("categories.id").
+		Order("categories.name ASC").
+		Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) FindByID(id uint) (*entity.Category, error) {
+	var category entity.Category
+	result := r.db.First(&category, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, result.Error
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindBySlug(slug string) (*entity.Category, error) {
+	var category entity.Category
+	result := r.db.Where("slug = ?", slug).First(&category)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("category not found")
+		}
+		return nil, result.Error
+	}
+	return &category, nil
+}
+
+// FindByIDs loads the given categories, skipping IDs that do not exist
+func (r *categoryRepository) FindByIDs(ids []uint) ([]entity.Category, error) {
+	var categories []entity.Category
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Order("name ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) Save(category *entity.Category) error {
+	return r.db.Save(category).Error
+}
+
+// Delete removes the category, taking it off the events that were in it
+func (r *categoryRepository) Delete(id uint) error {
+	category, err := r.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM event_categories WHERE category_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(category).Error
+	})
+}
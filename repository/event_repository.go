@@ -2,6 +2,8 @@ package repository
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/taufikmulyawan/ticketing-system/config"
 	"github.com/taufikmulyawan/ticketing-system/entity"
@@ -9,10 +11,49 @@ import (
 )
 
 type EventRepository interface {
-	FindAll(page, limit int) ([]entity.Event, int64, error)
+	FindAll(page, limit int, filter EventFilter) ([]entity.Event, int64, error)
 	FindByID(id uint) (*entity.Event, error)
+	FindByIDs(ids []uint) ([]entity.Event, error)
+	FindBySeriesID(seriesID uint) ([]entity.Event, error)
+	NameTaken(name string, excludeID uint) (bool, error)
+	ReplaceCategories(eventID uint, categories []entity.Category) error
+	ReplaceTags(eventID uint, tags []entity.Tag) error
 	Save(event *entity.Event) error
 	Delete(id uint) error
+	ReserveSeats(eventID uint, quantity int) error
+	ReleaseSeats(eventID uint, quantity int) error
+	FindDueToStart(at time.Time) ([]entity.Event, error)
+	FindDueToFinish(at time.Time) ([]entity.Event, error)
+	UpdateStatus(id uint, from, to entity.EventStatus) (bool, error)
+	ChangeStatus(id uint, from, to entity.EventStatus, changes map[string]interface{}) (bool, error)
+}
+
+// EventFilter narrows and orders the event listing. Zero values do not filter.
+type EventFilter struct {
+	Name      string // Substring of the name
+	Location  string // Substring of the location
+	VenueID   *uint
+	City      string // City of the venue
+	SeriesID  *uint
+	Category  string     // Slug of a category
+	Tag       string     // Slug of a tag
+	Published *time.Time // Only events that are public at this time
+	StartFrom *time.Time
+	StartTo   *time.Time
+	Status    entity.EventStatus
+	MinPrice  *float64
+	MaxPrice  *float64
+	Available bool   // Only events with seats left
+	Sort      string // One of EventSorts, start_date when empty
+}
+
+// EventSorts maps the sort options of the event listing to their ORDER BY clause
+var EventSorts = map[string]string{
+	"start_date":  "start_date ASC",
+	"-start_date": "start_date DESC",
+	"price":       "price ASC",
+	"-price":      "price DESC",
+	"popularity":  "sold_count DESC",
 }
 
 type eventRepository struct {
@@ -25,28 +66,98 @@ func NewEventRepository() EventRepository {
 	}
 }
 
-func (r *eventRepository) FindAll(page, limit int) ([]entity.Event, int64, error) {
+func (r *eventRepository) FindAll(page, limit int, filter EventFilter) ([]entity.Event, int64, error) {
 	var events []entity.Event
 	var count int64
 
 	offset := (page - 1) * limit
+	query := r.filterEvents(filter)
 
 	// Get total count
-	if err := r.db.Model(&entity.Event{}).Count(&count).Error; err != nil {
+	if err := query.Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// Get paginated events
-	if err := r.db.Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+	// Get paginated events, with the ID as tie breaker so pages do not overlap
+	order, ok := EventSorts[filter.Sort]
+	if !ok {
+		order = EventSorts["start_date"]
+	}
+	if err := query.Order(order).Order("id ASC").Offset(offset).Limit(limit).Preload("Categories").Preload("Tags").Find(&events).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return events, count, nil
 }
 
+// filterEvents builds the WHERE clause of the event listing. LIKE patterns use
+// ! as escape character because MySQL and SQLite disagree on backslashes.
+func (r *eventRepository) filterEvents(filter EventFilter) *gorm.DB {
+	query := r.db.Model(&entity.Event{})
+
+	if filter.Name != "" {
+		query = query.Where("name LIKE ? ESCAPE '!'", containsPattern(filter.Name))
+	}
+	if filter.Location != "" {
+		query = query.Where("location LIKE ? ESCAPE '!'", containsPattern(filter.Location))
+	}
+	if filter.VenueID != nil {
+		query = query.Where("venue_id = ?", *filter.VenueID)
+	}
+	if filter.City != "" {
+		query = query.Where("venue_id IN (?)", r.db.Model(&entity.Venue{}).Select("id").Where("LOWER(city) = LOWER(?)", filter.City))
+	}
+	if filter.Published != nil {
+		query = query.Where("draft = ? AND (publish_at IS NULL OR publish_at <= ?)", false, *filter.Published)
+	}
+	if filter.SeriesID != nil {
+		query = query.Where("series_id = ?", *filter.SeriesID)
+	}
+	if filter.Category != "" {
+		query = query.Where("id IN (?)", r.db.Table("event_categories").
+			Select("event_categories.event_id").
+			Joins("JOIN categories ON categories.id = event_categories.category_id").
+			Where("categories.slug = ?", filter.Category))
+	}
+	if filter.Tag != "" {
+		query = query.Where("id IN (?)", r.db.Table("event_tags").
+			Select("event_tags.event_id").
+			Joins("JOIN tags ON tags.id = event_tags.tag_id").
+			Where("tags.slug = ?", filter.Tag))
+	}
+	if filter.StartFrom != nil {
+		query = query.Where("start_date >= ?", *filter.StartFrom)
+	}
+	if filter.StartTo != nil {
+		query = query.Where("start_date <= ?", *filter.StartTo)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.MinPrice != nil {
+		query = query.Where("price >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		query = query.Where("price <= ?", *filter.MaxPrice)
+	}
+	if filter.Available {
+		query = query.Where("sold_count < capacity")
+	}
+
+	return query
+}
+
+// containsPattern turns user input into a LIKE pattern matching it anywhere
+func containsPattern(value string) string {
+	escaped := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+	return "%" + escaped + "%"
+}
+
 func (r *eventRepository) FindByID(id uint) (*entity.Event, error) {
 	var event entity.Event
-	result := r.db.First(&event, id)
+	result := r.db.Preload("Venue").Preload("Categories").Preload("Tags").
+		Preload("Media", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC, id ASC") }).
+		First(&event, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, errors.New("event not found")
@@ -56,8 +167,60 @@ func (r *eventRepository) FindByID(id uint) (*entity.Event, error) {
 	return &event, nil
 }
 
+// FindByIDs loads the given events in no particular order, skipping IDs that do not exist
+func (r *eventRepository) FindByIDs(ids []uint) ([]entity.Event, error) {
+	var events []entity.Event
+	if len(ids) == 0 {
+		return events, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Preload("Categories").Preload("Tags").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// FindBySeriesID loads every occurrence of an event series, soonest first
+func (r *eventRepository) FindBySeriesID(seriesID uint) ([]entity.Event, error) {
+	var events []entity.Event
+	if err := r.db.Where("series_id = ?", seriesID).Order("start_date ASC").Order("id ASC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// NameTaken reports whether an event other than excludeID that is not part of
+// a series already uses the name
+func (r *eventRepository) NameTaken(name string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Event{}).
+		Where("LOWER(name) = LOWER(?) AND series_id IS NULL AND id <> ?", name, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func (r *eventRepository) Save(event *entity.Event) error {
-	return r.db.Save(event).Error
+	// The sold counter is only ever moved by ReserveSeats/ReleaseSeats so a
+	// stale copy of the event cannot overwrite concurrent purchases. The venue,
+	// categories, tags and media are managed on their own.
+	return r.db.Omit("SoldCount", "Venue", "Categories", "Tags", "Media").Save(event).Error
+}
+
+// ReplaceCategories puts the event in exactly the given categories
+func (r *eventRepository) ReplaceCategories(eventID uint, categories []entity.Category) error {
+	association := r.db.Model(&entity.Event{ID: eventID}).Association("Categories")
+	if len(categories) == 0 {
+		return association.Clear()
+	}
+	return association.Replace(categories)
+}
+
+// ReplaceTags gives the event exactly the given tags
+func (r *eventRepository) ReplaceTags(eventID uint, tags []entity.Tag) error {
+	association := r.db.Model(&entity.Event{ID: eventID}).Association("Tags")
+	if len(tags) == 0 {
+		return association.Clear()
+	}
+	return association.Replace(tags)
 }
 
 func (r *eventRepository) Delete(id uint) error {
@@ -69,7 +232,7 @@ func (r *eventRepository) Delete(id uint) error {
 
 	// Then check if there are tickets sold for this event
 	var ticketCount int64
-	if err := r.db.Model(&entity.Ticket{}).Where("event_id = ? AND status = ?", id, entity.TicketStatusPurchased).Count(&ticketCount).Error; err != nil {
+	if err := r.db.Model(&entity.Ticket{}).Where("event_id = ? AND status IN ?", id, entity.SeatTakingStatuses).Count(&ticketCount).Error; err != nil {
 		return err
 	}
 
@@ -77,5 +240,70 @@ func (r *eventRepository) Delete(id uint) error {
 		return errors.New("cannot delete event with sold tickets")
 	}
 
-	return r.db.Delete(event).Error
-} 
\ No newline at end of file
+	// Its category and tag links and its gallery go with it
+	return r.db.Select("Categories", "Tags", "Media").Delete(event).Error
+}
+
+// ReserveSeats atomically takes seats from the event's remaining capacity.
+// The conditional update lets the database arbitrate between concurrent
+// buyers, so the capacity holds on both MySQL and SQLite.
+func (r *eventRepository) ReserveSeats(eventID uint, quantity int) error {
+	result := r.db.Model(&entity.Event{}).
+		Where("id = ? AND sold_count + ? <= capacity", eventID, quantity).
+		UpdateColumn("sold_count", gorm.Expr("sold_count + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("event is sold out")
+	}
+	return nil
+}
+
+// ReleaseSeats gives previously reserved seats back to the event
+func (r *eventRepository) ReleaseSeats(eventID uint, quantity int) error {
+	return r.db.Model(&entity.Event{}).
+		Where("id = ? AND sold_count >= ?", eventID, quantity).
+		UpdateColumn("sold_count", gorm.Expr("sold_count - ?", quantity)).Error
+}
+
+// FindDueToStart returns active and postponed events whose start date has passed
+func (r *eventRepository) FindDueToStart(at time.Time) ([]entity.Event, error) {
+	var events []entity.Event
+	err := r.db.Where("status IN ? AND start_date <= ?", entity.OpenEventStatuses, at).
+		Order("start_date").
+		Find(&events).Error
+	return events, err
+}
+
+// FindDueToFinish returns ongoing events whose end date has passed
+func (r *eventRepository) FindDueToFinish(at time.Time) ([]entity.Event, error) {
+	var events []entity.Event
+	err := r.db.Where("status = ? AND end_date <= ?", entity.EventStatusOngoing, at).
+		Order("end_date").
+		Find(&events).Error
+	return events, err
+}
+
+// UpdateStatus moves an event between statuses only if it is still in the
+// expected one, reporting false when it was changed in the meantime
+func (r *eventRepository) UpdateStatus(id uint, from, to entity.EventStatus) (bool, error) {
+	return r.ChangeStatus(id, from, to, nil)
+}
+
+// ChangeStatus moves an event between statuses like UpdateStatus and applies
+// the other column changes that go with the move in the same update
+func (r *eventRepository) ChangeStatus(id uint, from, to entity.EventStatus, changes map[string]interface{}) (bool, error) {
+	updates := map[string]interface{}{"status": to}
+	for column, value := range changes {
+		updates[column] = value
+	}
+
+	result := r.db.Model(&entity.Event{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type EventSeriesRepository interface {
+	FindAll(page, limit int) ([]entity.EventSeries, int64, error)
+	FindByID(id uint) (*entity.EventSeries, error)
+	FindByName(name string) (*entity.EventSeries, error)
+	Save(series *entity.EventSeries) error
+	Delete(id uint) error
+}
+
+type eventSeriesRepository struct {
+	db *gorm.DB
+}
+
+func NewEventSeriesRepository() EventSeriesRepository {
+	return &eventSeriesRepository{
+		db: config.DB,
+	}
+}
+
+// FindAll lists event series by the start of their first occurrence
+func (r *eventSeriesRepository) FindAll(page, limit int) ([]entity.EventSeries, int64, error) {
+	var series []entity.EventSeries
+	var count int64
+
+	offset := (page - 1) * limit
+	query := r.db.Model(&entity.EventSeries{})
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("start_date ASC").Order("id ASC").Offset(offset).Limit(limit).Find(&series).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return series, count, nil
+}
+
+func (r *eventSeriesRepository) FindByID(id uint) (*entity.EventSeries, error) {
+	var series entity.EventSeries
+	result := r.db.First(&series, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event series not found")
+		}
+		return nil, result.Error
+	}
+	return &series, nil
+}
+
+func (r *eventSeriesRepository) FindByName(name string) (*entity.EventSeries, error) {
+	var series entity.EventSeries
+	result := r.db.Where("LOWER(name) = LOWER(?)", name).First(&series)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event series not found")
+		}
+		return nil, result.Error
+	}
+	return &series, nil
+}
+
+func (r *eventSeriesRepository) Save(series *entity.EventSeries) error {
+	return r.db.Save(series).Error
+}
+
+// Delete removes the series only, its occurrences are handled by the caller
+func (r *eventSeriesRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.EventSeries{}, id).Error
+}
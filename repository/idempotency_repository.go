@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type IdempotencyRepository interface {
+	FindByUserAndKey(userID uint, key string) (*entity.IdempotencyKey, error)
+	Create(record *entity.IdempotencyKey) error
+	Complete(id uint, status int, body string, expiresAt time.Time) error
+	Delete(id uint) error
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository() IdempotencyRepository {
+	return &idempotencyRepository{
+		db: config.DB,
+	}
+}
+
+func (r *idempotencyRepository) FindByUserAndKey(userID uint, key string) (*entity.IdempotencyKey, error) {
+	var record entity.IdempotencyKey
+	result := r.db.Where(map[string]interface{}{"user_id": userID, "key": key}).First(&record)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("idempotency key not found")
+		}
+		return nil, result.Error
+	}
+	return &record, nil
+}
+
+// Create inserts a new key. It fails when the user already has a record for
+// the same key, which is how concurrent requests with one key are told apart.
+func (r *idempotencyRepository) Create(record *entity.IdempotencyKey) error {
+	return r.db.Create(record).Error
+}
+
+// Complete stores the response to replay for the key
+func (r *idempotencyRepository) Complete(id uint, status int, body string, expiresAt time.Time) error {
+	return r.db.Model(&entity.IdempotencyKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          entity.IdempotencyStatusCompleted,
+			"response_status": status,
+			"response_body":   body,
+			"expires_at":      expiresAt,
+		}).Error
+}
+
+func (r *idempotencyRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.IdempotencyKey{}, id).Error
+}
+
+// DeleteExpired removes keys that expired before the given time and reports how many were removed
+func (r *idempotencyRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", before).Delete(&entity.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
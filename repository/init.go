@@ -2,18 +2,50 @@ package repository
 
 // Repositories holds all repository instances
 type Repositories struct {
-	UserRepository   UserRepository
-	EventRepository  EventRepository
-	TicketRepository TicketRepository
-	AuditRepository  AuditRepository
+	UserRepository        UserRepository
+	EventRepository       EventRepository
+	TicketRepository      TicketRepository
+	AuditRepository       AuditRepository
+	OrderRepository       OrderRepository
+	TierRepository        TicketTierRepository
+	PaymentRepository     PaymentRepository
+	RefundRepository      RefundRepository
+	PolicyRepository      CancellationPolicyRepository
+	WaitlistRepository    WaitlistRepository
+	TransferRepository    TransferRepository
+	PromoCodeRepository   PromoCodeRepository
+	IdempotencyRepository IdempotencyRepository
+	VenueRepository       VenueRepository
+	SeatMapRepository     SeatMapRepository
+	EventSeriesRepository EventSeriesRepository
+	CategoryRepository    CategoryRepository
+	TagRepository         TagRepository
+	MediaRepository       MediaRepository
+	Transactor            Transactor
 }
 
 // InitRepositories initializes all repositories
 func InitRepositories() *Repositories {
 	return &Repositories{
-		UserRepository:   NewUserRepository(),
-		EventRepository:  NewEventRepository(),
-		TicketRepository: NewTicketRepository(),
-		AuditRepository:  NewAuditRepository(),
+		UserRepository:        NewUserRepository(),
+		EventRepository:       NewEventRepository(),
+		TicketRepository:      NewTicketRepository(),
+		AuditRepository:       NewAuditRepository(),
+		OrderRepository:       NewOrderRepository(),
+		TierRepository:        NewTicketTierRepository(),
+		PaymentRepository:     NewPaymentRepository(),
+		RefundRepository:      NewRefundRepository(),
+		PolicyRepository:      NewCancellationPolicyRepository(),
+		WaitlistRepository:    NewWaitlistRepository(),
+		TransferRepository:    NewTransferRepository(),
+		PromoCodeRepository:   NewPromoCodeRepository(),
+		IdempotencyRepository: NewIdempotencyRepository(),
+		VenueRepository:       NewVenueRepository(),
+		SeatMapRepository:     NewSeatMapRepository(),
+		EventSeriesRepository: NewEventSeriesRepository(),
+		CategoryRepository:    NewCategoryRepository(),
+		TagRepository:         NewTagRepository(),
+		MediaRepository:       NewMediaRepository(),
+		Transactor:            NewTransactor(),
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type MediaRepository interface {
+	FindByEventID(eventID uint) ([]entity.Media, error)
+	FindByID(id uint) (*entity.Media, error)
+	Save(media *entity.Media) error
+	SetCover(eventID uint, mediaID uint) error
+	Reorder(eventID uint, mediaIDs []uint) error
+	Delete(id uint) error
+}
+
+type mediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository() MediaRepository {
+	return &mediaRepository{
+		db: config.DB,
+	}
+}
+
+// FindByEventID lists the gallery of an event in display order
+func (r *mediaRepository) FindByEventID(eventID uint) ([]entity.Media, error) {
+	var media []entity.Media
+	if err := r.db.Where("event_id = ?", eventID).Order("position ASC, id ASC").Find(&media).Error; err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+func (r *mediaRepository) FindByID(id uint) (*entity.Media, error) {
+	var media entity.Media
+	result := r.db.First(&media, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("media not found")
+		}
+		return nil, result.Error
+	}
+	return &media, nil
+}
+
+func (r *mediaRepository) Save(media *entity.Media) error {
+	return r.db.Save(media).Error
+}
+
+// SetCover makes the media the only cover image of its event
+func (r *mediaRepository) SetCover(eventID uint, mediaID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Media{}).Where("event_id = ? AND id <> ?", eventID, mediaID).Update("is_cover", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.Media{}).Where("event_id = ? AND id = ?", eventID, mediaID).Update("is_cover", true).Error
+	})
+}
+
+// Reorder numbers the event's media in the given order
+func (r *mediaRepository) Reorder(eventID uint, mediaIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for position, id := range mediaIDs {
+			if err := tx.Model(&entity.Media{}).Where("event_id = ? AND id = ?", eventID, id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *mediaRepository) Delete(id uint) error {
+	return r.db.Delete(&entity.Media{}, id).Error
+}
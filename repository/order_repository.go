@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type OrderRepository interface {
+	FindByID(id uint) (*entity.Order, error)
+	FindByUserID(page, limit int, userID uint) ([]entity.Order, int64, error)
+	Save(order *entity.Order) error
+	UpdateStatus(id uint, from, to entity.OrderStatus) (bool, error)
+}
+
+type orderRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository() OrderRepository {
+	return &orderRepository{
+		db: config.DB,
+	}
+}
+
+func (r *orderRepository) FindByID(id uint) (*entity.Order, error) {
+	var order entity.Order
+	result := r.db.Preload("Items.Event").Preload("Tickets").First(&order, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order not found")
+		}
+		return nil, result.Error
+	}
+	return &order, nil
+}
+
+func (r *orderRepository) FindByUserID(page, limit int, userID uint) ([]entity.Order, int64, error) {
+	var orders []entity.Order
+	var count int64
+
+	offset := (page - 1) * limit
+	query := r.db.Where("user_id = ?", userID)
+
+	// Get total count
+	if err := query.Model(&entity.Order{}).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get the newest orders first
+	if err := query.Preload("Items.Event").Order("created_at DESC").Offset(offset).Limit(limit).Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return orders, count, nil
+}
+
+// Save persists the order together with its line items
+func (r *orderRepository) Save(order *entity.Order) error {
+	return r.db.Save(order).Error
+}
+
+// UpdateStatus moves an order between statuses only if it is still in the expected one
+func (r *orderRepository) UpdateStatus(id uint, from, to entity.OrderStatus) (bool, error) {
+	result := r.db.Model(&entity.Order{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
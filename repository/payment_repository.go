@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type PaymentRepository interface {
+	FindByID(id uint) (*entity.Payment, error)
+	FindByIntentID(intentID string) (*entity.Payment, error)
+	FindSucceededForTicket(ticket *entity.Ticket) (*entity.Payment, error)
+	Save(payment *entity.Payment) error
+	UpdateStatus(id uint, from, to entity.PaymentStatus) (bool, error)
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository() PaymentRepository {
+	return &paymentRepository{
+		db: config.DB,
+	}
+}
+
+func (r *paymentRepository) FindByID(id uint) (*entity.Payment, error) {
+	var payment entity.Payment
+	result := r.db.First(&payment, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment not found")
+		}
+		return nil, result.Error
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) FindByIntentID(intentID string) (*entity.Payment, error) {
+	var payment entity.Payment
+	result := r.db.Where("intent_id = ?", intentID).First(&payment)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment not found")
+		}
+		return nil, result.Error
+	}
+	return &payment, nil
+}
+
+// FindSucceededForTicket finds the settled payment that paid for a ticket,
+// either on its own or as part of its order. It returns nil when the ticket
+// was never paid through the gateway.
+func (r *paymentRepository) FindSucceededForTicket(ticket *entity.Ticket) (*entity.Payment, error) {
+	query := r.db.Where("status = ?", entity.PaymentStatusSucceeded)
+	if ticket.OrderID != nil {
+		query = query.Where("order_id = ?", *ticket.OrderID)
+	} else {
+		query = query.Where("ticket_id = ?", ticket.ID)
+	}
+
+	var payments []entity.Payment
+	if err := query.Limit(1).Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	if len(payments) == 0 {
+		return nil, nil
+	}
+	return &payments[0], nil
+}
+
+func (r *paymentRepository) Save(payment *entity.Payment) error {
+	return r.db.Save(payment).Error
+}
+
+// UpdateStatus moves a payment between statuses only if it is still in the
+// expected one, so each gateway notification is applied at most once
+func (r *paymentRepository) UpdateStatus(id uint, from, to entity.PaymentStatus) (bool, error) {
+	result := r.db.Model(&entity.Payment{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type PromoCodeRepository interface {
+	FindAll(page, limit int) ([]entity.PromoCode, int64, error)
+	FindByID(id uint) (*entity.PromoCode, error)
+	FindByCode(code string) (*entity.PromoCode, error)
+	Save(promoCode *entity.PromoCode) error
+	Delete(id uint) error
+	CountUsesByUser(id uint, userID uint) (int64, error)
+	Redeem(id uint) (bool, error)
+	Restore(id uint) error
+}
+
+type promoCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewPromoCodeRepository() PromoCodeRepository {
+	return &promoCodeRepository{
+		db: config.DB,
+	}
+}
+
+func (r *promoCodeRepository) FindAll(page, limit int) ([]entity.PromoCode, int64, error) {
+	var promoCodes []entity.PromoCode
+	var count int64
+
+	offset := (page - 1) * limit
+
+	if err := r.db.Model(&entity.PromoCode{}).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.db.Order("id DESC").Offset(offset).Limit(limit).Find(&promoCodes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return promoCodes, count, nil
+}
+
+func (r *promoCodeRepository) FindByID(id uint) (*entity.PromoCode, error) {
+	var promoCode entity.PromoCode
+	result := r.db.First(&promoCode, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("promo code not found")
+		}
+		return nil, result.Error
+	}
+	return &promoCode, nil
+}
+
+func (r *promoCodeRepository) FindByCode(code string) (*entity.PromoCode, error) {
+	var promoCode entity.PromoCode
+	result := r.db.Where("code = ?", code).First(&promoCode)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("promo code not found")
+		}
+		return nil, result.Error
+	}
+	return &promoCode, nil
+}
+
+func (r *promoCodeRepository) Save(promoCode *entity.PromoCode) error {
+	return r.db.Save(promoCode).Error
+}
+
+func (r *promoCodeRepository) Delete(id uint) error {
+	promoCode, err := r.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Codes that were applied to tickets are kept for reporting
+	var ticketCount int64
+	if err := r.db.Model(&entity.Ticket{}).Where("promo_code_id = ?", id).Count(&ticketCount).Error; err != nil {
+		return err
+	}
+
+	if ticketCount > 0 {
+		return errors.New("cannot delete a promo code that has been used, end it with valid_until instead")
+	}
+
+	return r.db.Delete(promoCode).Error
+}
+
+// CountUsesByUser counts the user's tickets that hold a seat with the code
+func (r *promoCodeRepository) CountUsesByUser(id uint, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Ticket{}).
+		Where("promo_code_id = ? AND user_id = ? AND status IN ?", id, userID, entity.SeatTakingStatuses).
+		Count(&count).Error
+	return count, err
+}
+
+// Redeem atomically counts one use of the code, reporting false when the
+// global usage cap has been reached
+func (r *promoCodeRepository) Redeem(id uint) (bool, error) {
+	result := r.db.Model(&entity.PromoCode{}).
+		Where("id = ? AND (max_uses = 0 OR used_count < max_uses)", id).
+		UpdateColumn("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Restore gives back a use of the code when its ticket stops holding a seat
+func (r *promoCodeRepository) Restore(id uint) error {
+	return r.db.Model(&entity.PromoCode{}).
+		Where("id = ? AND used_count > 0", id).
+		UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type RefundRepository interface {
+	FindByID(id uint) (*entity.Refund, error)
+	FindByEventID(eventID uint) ([]entity.Refund, error)
+	Save(refund *entity.Refund) error
+	SumByEventID(eventID uint) (ticketAmount float64, refunded float64, err error)
+	SumByTierID(tierID uint) (ticketAmount float64, refunded float64, err error)
+}
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository() RefundRepository {
+	return &refundRepository{
+		db: config.DB,
+	}
+}
+
+func (r *refundRepository) FindByID(id uint) (*entity.Refund, error) {
+	var refund entity.Refund
+	result := r.db.First(&refund, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refund not found")
+		}
+		return nil, result.Error
+	}
+	return &refund, nil
+}
+
+func (r *refundRepository) FindByEventID(eventID uint) ([]entity.Refund, error) {
+	var refunds []entity.Refund
+	if err := r.db.Where("event_id = ?", eventID).Order("id ASC").Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+func (r *refundRepository) Save(refund *entity.Refund) error {
+	return r.db.Save(refund).Error
+}
+
+// SumByEventID totals what was paid for the refunded tickets of an event and
+// how much of it was given back. Failed refunds left the money with us, so
+// their tickets still count as paid but nothing is given back for them.
+func (r *refundRepository) SumByEventID(eventID uint) (float64, float64, error) {
+	return r.sumRefunds(r.db.Where("refunds.event_id = ?", eventID))
+}
+
+// SumByTierID totals the refunds of the tickets sold in a tier
+func (r *refundRepository) SumByTierID(tierID uint) (float64, float64, error) {
+	return r.sumRefunds(r.db.Joins("JOIN tickets ON tickets.id = refunds.ticket_id").Where("tickets.tier_id = ?", tierID))
+}
+
+func (r *refundRepository) sumRefunds(query *gorm.DB) (float64, float64, error) {
+	var totals struct {
+		TicketAmount float64
+		Refunded     float64
+	}
+	err := query.Model(&entity.Refund{}).
+		Select("COALESCE(SUM(refunds.ticket_amount), 0) AS ticket_amount, COALESCE(SUM(CASE WHEN refunds.status <> ? THEN refunds.amount ELSE 0 END), 0) AS refunded", entity.RefundStatusFailed).
+		Scan(&totals).Error
+	return totals.TicketAmount, totals.Refunded, err
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SeatMapRepository interface {
+	FindAll(page, limit int, venueID *uint) ([]entity.SeatMap, int64, error)
+	FindByID(id uint) (*entity.SeatMap, error)
+	Create(seatMap *entity.SeatMap) error
+	Delete(id uint) error
+	FindSeats(seatMapID uint) ([]entity.Seat, error)
+	FindSeatByID(id uint) (*entity.Seat, error)
+	CountSeats(seatMapID uint) (int64, error)
+	FindTakenSeatIDs(eventID uint) ([]uint, error)
+	ReserveSeat(eventID uint, seatID uint, ticketID uint) (bool, error)
+	ReleaseTicketSeat(ticketID uint) error
+}
+
+type seatMapRepository struct {
+	db *gorm.DB
+}
+
+func NewSeatMapRepository() SeatMapRepository {
+	return &seatMapRepository{
+		db: config.DB,
+	}
+}
+
+// FindAll lists seat maps without their seats, only those of the given venue when set
+func (r *seatMapRepository) FindAll(page, limit int, venueID *uint) ([]entity.SeatMap, int64, error) {
+	var seatMaps []entity.SeatMap
+	var count int64
+
+	offset := (page - 1) * limit
+	query := r.db.Model(&entity.SeatMap{})
+	if venueID != nil {
+		query = query.Where("venue_id = ?", *venueID)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&seatMaps).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return seatMaps, count, nil
+}
+
+func (r *seatMapRepository) FindByID(id uint) (*entity.SeatMap, error) {
+	var seatMap entity.SeatMap
+	result := r.db.First(&seatMap, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("seat map not found")
+		}
+		return nil, result.Error
+	}
+	return &seatMap, nil
+}
+
+// Create saves a new seat map together with all of its seats
+func (r *seatMapRepository) Create(seatMap *entity.SeatMap) error {
+	return r.db.Create(seatMap).Error
+}
+
+func (r *seatMapRepository) Delete(id uint) error {
+	seatMap, err := r.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Events keep their seat map for the tickets sold on it
+	var eventCount int64
+	if err := r.db.Model(&entity.Event{}).Where("seat_map_id = ?", id).Count(&eventCount).Error; err != nil {
+		return err
+	}
+
+	if eventCount > 0 {
+		return errors.New("cannot delete a seat map that is used by an event")
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("seat_map_id = ?", id).Delete(&entity.Seat{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(seatMap).Error
+	})
+}
+
+// FindSeats returns the seats of a map grouped by section and row
+func (r *seatMapRepository) FindSeats(seatMapID uint) ([]entity.Seat, error) {
+	var seats []entity.Seat
+	err := r.db.Where("seat_map_id = ?", seatMapID).
+		Order("section ASC").Order("row_label ASC").Order("id ASC").
+		Find(&seats).Error
+	return seats, err
+}
+
+func (r *seatMapRepository) FindSeatByID(id uint) (*entity.Seat, error) {
+	var seat entity.Seat
+	result := r.db.First(&seat, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("seat not found")
+		}
+		return nil, result.Error
+	}
+	return &seat, nil
+}
+
+func (r *seatMapRepository) CountSeats(seatMapID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Seat{}).Where("seat_map_id = ?", seatMapID).Count(&count).Error
+	return count, err
+}
+
+// FindTakenSeatIDs returns the seats of an event that are held or sold
+func (r *seatMapRepository) FindTakenSeatIDs(eventID uint) ([]uint, error) {
+	var seatIDs []uint
+	err := r.db.Model(&entity.SeatReservation{}).
+		Where("event_id = ?", eventID).
+		Pluck("seat_id", &seatIDs).Error
+	return seatIDs, err
+}
+
+// ReserveSeat claims a seat of an event for a ticket, reporting false when
+// another ticket already has it. The unique index on event and seat decides
+// between concurrent buyers on both MySQL and SQLite.
+func (r *seatMapRepository) ReserveSeat(eventID uint, seatID uint, ticketID uint) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.SeatReservation{
+		EventID:  eventID,
+		SeatID:   seatID,
+		TicketID: ticketID,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseTicketSeat frees the seat held by a ticket, if any
+func (r *seatMapRepository) ReleaseTicketSeat(ticketID uint) error {
+	return r.db.Where("ticket_id = ?", ticketID).Delete(&entity.SeatReservation{}).Error
+}
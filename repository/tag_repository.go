@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	FindAll(at time.Time) ([]entity.Tag, error)
+	FindByID(id uint) (*entity.Tag, error)
+	FindBySlug(slug string) (*entity.Tag, error)
+	FindByIDs(ids []uint) ([]entity.Tag, error)
+	Save(tag *entity.Tag) error
+	Delete(id uint) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository() TagRepository {
+	return &tagRepository{
+		db: config.DB,
+	}
+}
+
+// FindAll lists tags by name with the number of events carrying each that
+// are public at the given time and have not started
+func (r *tagRepository) FindAll(at time.Time) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	err := r.db.Model(&entity.Tag{}).
+		Select("tags.*, COUNT(events.id) AS event_count").
+		Joins("LEFT JOIN event_tags ON event_tags.tag_id = tags.id").
+		Joins("LEFT JOIN events ON events.id = event_tags.event_id AND events.status IN ? AND events.draft = ? AND (events.publish_at IS NULL OR events.publish_at <= ?)", entity.OpenEventStatuses, false, at).
+		Group("tags.id").
+		Order("tags.name ASC").
+		Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) FindByID(id uint) (*entity.Tag, error) {
+	var tag entity.Tag
+	result := r.db.First(&tag, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, result.Error
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) FindBySlug(slug string) (*entity.Tag, error) {
+	var tag entity.Tag
+	result := r.db.Where("slug = ?", slug).First(&tag)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, result.Error
+	}
+	return &tag, nil
+}
+
+// FindByIDs loads the given tags, skipping IDs that do not exist
+func (r *tagRepository) FindByIDs(ids []uint) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Order("name ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) Save(tag *entity.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+// Delete removes the tag, taking it off the events that carried it
+func (r *tagRepository) Delete(id uint) error {
+	tag, err := r.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM event_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(tag).Error
+	})
+}
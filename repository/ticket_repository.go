@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/taufikmulyawan/ticketing-system/config"
 	"github.com/taufikmulyawan/ticketing-system/entity"
@@ -13,7 +14,28 @@ type TicketRepository interface {
 	FindByID(id uint) (*entity.Ticket, error)
 	Save(ticket *entity.Ticket) error
 	FindByEventID(eventID uint) ([]entity.Ticket, error)
+	FindByOrderID(orderID uint) ([]entity.Ticket, error)
 	CountSoldTicketsByEventID(eventID uint) (int64, error)
+	CountPurchasedTicketsByEventID(eventID uint) (int64, error)
+	CountPurchasedTicketsByTierID(tierID uint) (int64, error)
+	CountUserTicketsByEventID(eventID uint, userID uint) (int64, error)
+	FindLastPurchaseTime(eventID uint, userID uint) (*time.Time, error)
+	SumSalesByEventID(eventID uint) (*TicketSales, error)
+	SumSalesByTierID(tierID uint) (*TicketSales, error)
+	UpdateStatus(id uint, from, to entity.TicketStatus) (bool, error)
+	ConfirmHold(id uint, at time.Time) (bool, error)
+	CheckIn(id uint, staffID uint, at time.Time) (bool, error)
+	Transfer(id uint, fromUserID uint, toUserID uint) (bool, error)
+	FindExpiredHolds(before time.Time, limit int) ([]entity.Ticket, error)
+}
+
+// TicketSales totals the purchased tickets of an event or tier at the prices
+// they were sold for
+type TicketSales struct {
+	Tickets   int64
+	Revenue   float64 // What buyers paid, after discounts and including fees
+	Discounts float64
+	Fees      float64
 }
 
 type ticketRepository struct {
@@ -44,7 +66,7 @@ func (r *ticketRepository) FindAll(page, limit int, userID uint) ([]entity.Ticke
 	}
 
 	// Get tickets with pagination
-	if err := query.Preload("Event").Preload("User").Offset(offset).Limit(limit).Find(&tickets).Error; err != nil {
+	if err := query.Preload("Event").Preload("User").Preload("Tier").Offset(offset).Limit(limit).Find(&tickets).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -53,7 +75,7 @@ func (r *ticketRepository) FindAll(page, limit int, userID uint) ([]entity.Ticke
 
 func (r *ticketRepository) FindByID(id uint) (*entity.Ticket, error) {
 	var ticket entity.Ticket
-	result := r.db.Preload("Event").Preload("User").First(&ticket, id)
+	result := r.db.Preload("Event").Preload("User").Preload("Tier").Preload("Seat").First(&ticket, id)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, errors.New("ticket not found")
@@ -75,10 +97,147 @@ func (r *ticketRepository) FindByEventID(eventID uint) ([]entity.Ticket, error)
 	return tickets, nil
 }
 
+func (r *ticketRepository) FindByOrderID(orderID uint) ([]entity.Ticket, error) {
+	var tickets []entity.Ticket
+	if err := r.db.Where("order_id = ?", orderID).Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// CountSoldTicketsByEventID counts every ticket occupying a seat, including
+// holds that have not been paid for yet
 func (r *ticketRepository) CountSoldTicketsByEventID(eventID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Ticket{}).
+		Where("event_id = ? AND status IN ?", eventID, entity.SeatTakingStatuses).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *ticketRepository) CountPurchasedTicketsByEventID(eventID uint) (int64, error) {
 	var count int64
 	err := r.db.Model(&entity.Ticket{}).
 		Where("event_id = ? AND status = ?", eventID, entity.TicketStatusPurchased).
 		Count(&count).Error
 	return count, err
-} 
\ No newline at end of file
+}
+
+func (r *ticketRepository) CountPurchasedTicketsByTierID(tierID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Ticket{}).
+		Where("tier_id = ? AND status = ?", tierID, entity.TicketStatusPurchased).
+		Count(&count).Error
+	return count, err
+}
+
+// CountUserTicketsByEventID counts the seats a user holds for an event,
+// including unpaid holds
+func (r *ticketRepository) CountUserTicketsByEventID(eventID uint, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.Ticket{}).
+		Where("event_id = ? AND user_id = ? AND status IN ?", eventID, userID, entity.SeatTakingStatuses).
+		Count(&count).Error
+	return count, err
+}
+
+// FindLastPurchaseTime returns when the user last took a ticket for the event,
+// whatever became of it, or nil when they never did
+func (r *ticketRepository) FindLastPurchaseTime(eventID uint, userID uint) (*time.Time, error) {
+	var tickets []entity.Ticket
+	err := r.db.Where("event_id = ? AND user_id = ?", eventID, userID).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&tickets).Error
+	if err != nil || len(tickets) == 0 {
+		return nil, err
+	}
+	return &tickets[0].CreatedAt, nil
+}
+
+func (r *ticketRepository) SumSalesByEventID(eventID uint) (*TicketSales, error) {
+	return r.sumSales(r.db.Where("event_id = ?", eventID))
+}
+
+func (r *ticketRepository) SumSalesByTierID(tierID uint) (*TicketSales, error) {
+	return r.sumSales(r.db.Where("tier_id = ?", tierID))
+}
+
+func (r *ticketRepository) sumSales(query *gorm.DB) (*TicketSales, error) {
+	var sales TicketSales
+	err := query.Model(&entity.Ticket{}).
+		Where("status = ?", entity.TicketStatusPurchased).
+		Select("COUNT(*) AS tickets, COALESCE(SUM(price_paid), 0) AS revenue, COALESCE(SUM(discount), 0) AS discounts, COALESCE(SUM(fee), 0) AS fees").
+		Scan(&sales).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sales, nil
+}
+
+// UpdateStatus moves a ticket between statuses only if it is still in the
+// expected one, reporting false when another request got there first
+func (r *ticketRepository) UpdateStatus(id uint, from, to entity.TicketStatus) (bool, error) {
+	result := r.db.Model(&entity.Ticket{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ConfirmHold turns a reserved ticket into a purchase as long as the hold has
+// not expired, reporting false when it is no longer an active hold
+func (r *ticketRepository) ConfirmHold(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&entity.Ticket{}).
+		Where("id = ? AND status = ? AND expires_at > ?", id, entity.TicketStatusReserved, at).
+		Updates(map[string]interface{}{
+			"status":       entity.TicketStatusPurchased,
+			"purchased_at": at,
+			"expires_at":   nil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CheckIn marks a purchased ticket as used. Only the first of several
+// concurrent scans gets true, so a ticket admits a single person.
+func (r *ticketRepository) CheckIn(id uint, staffID uint, at time.Time) (bool, error) {
+	result := r.db.Model(&entity.Ticket{}).
+		Where("id = ? AND status = ? AND checked_in_at IS NULL", id, entity.TicketStatusPurchased).
+		Updates(map[string]interface{}{
+			"checked_in_at": at,
+			"checked_in_by": staffID,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Transfer hands a purchased, unused ticket to another user as long as it is
+// still owned by the sender, reporting false when it changed in the meantime
+func (r *ticketRepository) Transfer(id uint, fromUserID uint, toUserID uint) (bool, error) {
+	result := r.db.Model(&entity.Ticket{}).
+		Where("id = ? AND user_id = ? AND status = ? AND checked_in_at IS NULL", id, fromUserID, entity.TicketStatusPurchased).
+		Update("user_id", toUserID)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindExpiredHolds returns reserved tickets whose hold ran out before the given time
+func (r *ticketRepository) FindExpiredHolds(before time.Time, limit int) ([]entity.Ticket, error) {
+	var tickets []entity.Ticket
+	if err := r.db.Where("status = ? AND expires_at <= ?", entity.TicketStatusReserved, before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
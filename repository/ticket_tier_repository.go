@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type TicketTierRepository interface {
+	FindByID(id uint) (*entity.TicketTier, error)
+	FindByEventID(eventID uint) ([]entity.TicketTier, error)
+	CountByEventID(eventID uint) (int64, error)
+	Save(tier *entity.TicketTier) error
+	Delete(id uint) error
+	ReserveSeats(tierID uint, quantity int) error
+	ReleaseSeats(tierID uint, quantity int) error
+}
+
+type ticketTierRepository struct {
+	db *gorm.DB
+}
+
+func NewTicketTierRepository() TicketTierRepository {
+	return &ticketTierRepository{
+		db: config.DB,
+	}
+}
+
+func (r *ticketTierRepository) FindByID(id uint) (*entity.TicketTier, error) {
+	var tier entity.TicketTier
+	result := r.db.First(&tier, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ticket tier not found")
+		}
+		return nil, result.Error
+	}
+	return &tier, nil
+}
+
+func (r *ticketTierRepository) FindByEventID(eventID uint) ([]entity.TicketTier, error) {
+	var tiers []entity.TicketTier
+	if err := r.db.Where("event_id = ?", eventID).Order("price ASC").Find(&tiers).Error; err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+func (r *ticketTierRepository) CountByEventID(eventID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.TicketTier{}).Where("event_id = ?", eventID).Count(&count).Error
+	return count, err
+}
+
+func (r *ticketTierRepository) Save(tier *entity.TicketTier) error {
+	// Like events, the sold counter is only moved by ReserveSeats/ReleaseSeats
+	return r.db.Omit("SoldCount").Save(tier).Error
+}
+
+func (r *ticketTierRepository) Delete(id uint) error {
+	tier, err := r.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Tiers that have issued tickets are kept for reporting
+	var ticketCount int64
+	if err := r.db.Model(&entity.Ticket{}).Where("tier_id = ?", id).Count(&ticketCount).Error; err != nil {
+		return err
+	}
+
+	if ticketCount > 0 {
+		return errors.New("cannot delete ticket tier with issued tickets")
+	}
+
+	return r.db.Delete(tier).Error
+}
+
+// ReserveSeats atomically takes seats from the tier's remaining capacity
+func (r *ticketTierRepository) ReserveSeats(tierID uint, quantity int) error {
+	result := r.db.Model(&entity.TicketTier{}).
+		Where("id = ? AND sold_count + ? <= capacity", tierID, quantity).
+		UpdateColumn("sold_count", gorm.Expr("sold_count + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("ticket tier is sold out")
+	}
+	return nil
+}
+
+// ReleaseSeats gives previously reserved seats back to the tier
+func (r *ticketTierRepository) ReleaseSeats(tierID uint, quantity int) error {
+	return r.db.Model(&entity.TicketTier{}).
+		Where("id = ? AND sold_count >= ?", tierID, quantity).
+		UpdateColumn("sold_count", gorm.Expr("sold_count - ?", quantity)).Error
+}
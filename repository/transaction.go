@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"gorm.io/gorm"
+)
+
+// Transactor runs a unit of work inside a single database transaction
+type Transactor interface {
+	WithinTransaction(fn func(repos *Repositories) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor() Transactor {
+	return &transactor{
+		db: config.DB,
+	}
+}
+
+// WithinTransaction hands fn a set of repositories bound to one transaction.
+// The transaction is committed when fn returns nil and rolled back otherwise.
+func (t *transactor) WithinTransaction(fn func(repos *Repositories) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&Repositories{
+			UserRepository:        &userRepository{db: tx},
+			EventRepository:       &eventRepository{db: tx},
+			TicketRepository:      &ticketRepository{db: tx},
+			AuditRepository:       &auditRepository{db: tx},
+			OrderRepository:       &orderRepository{db: tx},
+			TierRepository:        &ticketTierRepository{db: tx},
+			PaymentRepository:     &paymentRepository{db: tx},
+			RefundRepository:      &refundRepository{db: tx},
+			PolicyRepository:      &cancellationPolicyRepository{db: tx},
+			WaitlistRepository:    &waitlistRepository{db: tx},
+			TransferRepository:    &transferRepository{db: tx},
+			PromoCodeRepository:   &promoCodeRepository{db: tx},
+			IdempotencyRepository: &idempotencyRepository{db: tx},
+			VenueRepository:       &venueRepository{db: tx},
+			SeatMapRepository:     &seatMapRepository{db: tx},
+			EventSeriesRepository: &eventSeriesRepository{db: tx},
+			CategoryRepository:    &categoryRepository{db: tx},
+			TagRepository:         &tagRepository{db: tx},
+			MediaRepository:       &mediaRepository{db: tx},
+			Transactor:            &transactor{db: tx},
+		})
+	})
+}
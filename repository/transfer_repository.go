@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type TransferRepository interface {
+	FindByID(id uint) (*entity.TicketTransfer, error)
+	FindPendingByTicketID(ticketID uint) (*entity.TicketTransfer, error)
+	FindByUserID(userID uint) ([]entity.TicketTransfer, error)
+	Save(transfer *entity.TicketTransfer) error
+	UpdateStatus(id uint, from, to entity.TransferStatus) (bool, error)
+	Accept(id uint, at time.Time) (bool, error)
+}
+
+type transferRepository struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository() TransferRepository {
+	return &transferRepository{
+		db: config.DB,
+	}
+}
+
+func (r *transferRepository) FindByID(id uint) (*entity.TicketTransfer, error) {
+	var transfer entity.TicketTransfer
+	result := r.db.Preload("Ticket.Event").Preload("FromUser").Preload("ToUser").First(&transfer, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transfer not found")
+		}
+		return nil, result.Error
+	}
+	return &transfer, nil
+}
+
+// FindPendingByTicketID returns the open transfer of a ticket, or nil when there is none
+func (r *transferRepository) FindPendingByTicketID(ticketID uint) (*entity.TicketTransfer, error) {
+	var transfers []entity.TicketTransfer
+	if err := r.db.Where("ticket_id = ? AND status = ?", ticketID, entity.TransferStatusPending).
+		Limit(1).
+		Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	if len(transfers) == 0 {
+		return nil, nil
+	}
+	return &transfers[0], nil
+}
+
+// FindByUserID returns the transfers a user sent or received, newest first
+func (r *transferRepository) FindByUserID(userID uint) ([]entity.TicketTransfer, error) {
+	var transfers []entity.TicketTransfer
+	if err := r.db.Preload("Ticket.Event").Preload("FromUser").Preload("ToUser").
+		Where("from_user_id = ? OR to_user_id = ?", userID, userID).
+		Order("id DESC").
+		Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func (r *transferRepository) Save(transfer *entity.TicketTransfer) error {
+	return r.db.Save(transfer).Error
+}
+
+// UpdateStatus moves a transfer between statuses only if it is still in the
+// expected one, reporting false when another request got there first
+func (r *transferRepository) UpdateStatus(id uint, from, to entity.TransferStatus) (bool, error) {
+	result := r.db.Model(&entity.TicketTransfer{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Accept marks a pending transfer as accepted, reporting false when it was
+// withdrawn or answered concurrently
+func (r *transferRepository) Accept(id uint, at time.Time) (bool, error) {
+	result := r.db.Model(&entity.TicketTransfer{}).
+		Where("id = ? AND status = ?", id, entity.TransferStatusPending).
+		Updates(map[string]interface{}{
+			"status":      entity.TransferStatusAccepted,
+			"accepted_at": at,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type VenueRepository interface {
+	FindAll(page, limit int, city string) ([]entity.Venue, int64, error)
+	FindByID(id uint) (*entity.Venue, error)
+	FindByName(name string) (*entity.Venue, error)
+	Save(venue *entity.Venue) error
+	Delete(id uint) error
+	LargestEventCapacity(id uint) (int, error)
+}
+
+type venueRepository struct {
+	db *gorm.DB
+}
+
+func NewVenueRepository() VenueRepository {
+	return &venueRepository{
+		db: config.DB,
+	}
+}
+
+// FindAll lists venues by name, only those in the given city when it is set
+func (r *venueRepository) FindAll(page, limit int, city string) ([]entity.Venue, int64, error) {
+	var venues []entity.Venue
+	var count int64
+
+	offset := (page - 1) * limit
+	query := r.db.Model(&entity.Venue{})
+	if city != "" {
+		query = query.Where("LOWER(city) = LOWER(?)", city)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("name ASC").Offset(offset).Limit(limit).Find(&venues).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return venues, count, nil
+}
+
+func (r *venueRepository) FindByID(id uint) (*entity.Venue, error) {
+	var venue entity.Venue
+	result := r.db.First(&venue, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("venue not found")
+		}
+		return nil, result.Error
+	}
+	return &venue, nil
+}
+
+func (r *venueRepository) FindByName(name string) (*entity.Venue, error) {
+	var venue entity.Venue
+	result := r.db.Where("LOWER(name) = LOWER(?)", name).First(&venue)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("venue not found")
+		}
+		return nil, result.Error
+	}
+	return &venue, nil
+}
+
+func (r *venueRepository) Save(venue *entity.Venue) error {
+	return r.db.Save(venue).Error
+}
+
+func (r *venueRepository) Delete(id uint) error {
+	venue, err := r.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Past events keep pointing at their venue for reporting
+	var eventCount int64
+	if err := r.db.Model(&entity.Event{}).Where("venue_id = ?", id).Count(&eventCount).Error; err != nil {
+		return err
+	}
+
+	if eventCount > 0 {
+		return errors.New("cannot delete a venue that has events")
+	}
+
+	return r.db.Delete(venue).Error
+}
+
+// LargestEventCapacity returns the highest capacity among the venue's events
+// that are still to be held, 0 when there are none
+func (r *venueRepository) LargestEventCapacity(id uint) (int, error) {
+	var capacity int
+	err := r.db.Model(&entity.Event{}).
+		Where("venue_id = ? AND status NOT IN ?", id, []entity.EventStatus{entity.EventStatusFinished, entity.EventStatusCancelled}).
+		Select("COALESCE(MAX(capacity), 0)").
+		Scan(&capacity).Error
+	return capacity, err
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"gorm.io/gorm"
+)
+
+type WaitlistRepository interface {
+	FindByID(id uint) (*entity.WaitlistEntry, error)
+	FindActiveByUser(eventID uint, userID uint) (*entity.WaitlistEntry, error)
+	FindActiveByEventID(eventID uint) ([]entity.WaitlistEntry, error)
+	FindNextWaiting(eventID uint) (*entity.WaitlistEntry, error)
+	CountWaitingBefore(eventID uint, id uint) (int64, error)
+	FindExpiredOffers(before time.Time, limit int) ([]entity.WaitlistEntry, error)
+	Save(entry *entity.WaitlistEntry) error
+	UpdateStatus(id uint, from, to entity.WaitlistStatus) (bool, error)
+	Offer(id uint, tierID *uint, expiresAt time.Time) (bool, error)
+	Claim(id uint, ticketID uint, at time.Time) (bool, error)
+}
+
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository() WaitlistRepository {
+	return &waitlistRepository{
+		db: config.DB,
+	}
+}
+
+func (r *waitlistRepository) FindByID(id uint) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	result := r.db.First(&entry, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("waitlist entry not found")
+		}
+		return nil, result.Error
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepository) FindActiveByUser(eventID uint, userID uint) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	result := r.db.Where("event_id = ? AND user_id = ? AND status IN ?", eventID, userID, entity.ActiveWaitlistStatuses).
+		First(&entry)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("waitlist entry not found")
+		}
+		return nil, result.Error
+	}
+	return &entry, nil
+}
+
+// FindActiveByEventID returns the queue of an event in the order it is served
+func (r *waitlistRepository) FindActiveByEventID(eventID uint) ([]entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry
+	if err := r.db.Preload("User").
+		Where("event_id = ? AND status IN ?", eventID, entity.ActiveWaitlistStatuses).
+		Order("id ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// FindNextWaiting returns the first entry still in line, or nil when nobody is waiting
+func (r *waitlistRepository) FindNextWaiting(eventID uint) (*entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry
+	if err := r.db.Where("event_id = ? AND status = ?", eventID, entity.WaitlistStatusWaiting).
+		Order("id ASC").
+		Limit(1).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return &entries[0], nil
+}
+
+func (r *waitlistRepository) CountWaitingBefore(eventID uint, id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.WaitlistEntry{}).
+		Where("event_id = ? AND status = ? AND id < ?", eventID, entity.WaitlistStatusWaiting, id).
+		Count(&count).Error
+	return count, err
+}
+
+// FindExpiredOffers returns offers that were not claimed in time
+func (r *waitlistRepository) FindExpiredOffers(before time.Time, limit int) ([]entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry
+	if err := r.db.Where("status = ? AND offer_expires_at <= ?", entity.WaitlistStatusOffered, before).
+		Order("offer_expires_at ASC").
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *waitlistRepository) Save(entry *entity.WaitlistEntry) error {
+	return r.db.Save(entry).Error
+}
+
+// UpdateStatus moves an entry between statuses only if it is still in the expected one
+func (r *waitlistRepository) UpdateStatus(id uint, from, to entity.WaitlistStatus) (bool, error) {
+	result := r.db.Model(&entity.WaitlistEntry{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Offer hands a freed seat to a waiting entry until the given time
+func (r *waitlistRepository) Offer(id uint, tierID *uint, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.WaitlistEntry{}).
+		Where("id = ? AND status = ?", id, entity.WaitlistStatusWaiting).
+		Updates(map[string]interface{}{
+			"status":           entity.WaitlistStatusOffered,
+			"offer_tier_id":    tierID,
+			"offer_expires_at": expiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Claim accepts an offer as long as it has not expired at the given time
+func (r *waitlistRepository) Claim(id uint, ticketID uint, at time.Time) (bool, error) {
+	result := r.db.Model(&entity.WaitlistEntry{}).
+		Where("id = ? AND status = ? AND offer_expires_at > ?", id, entity.WaitlistStatusOffered, at).
+		Updates(map[string]interface{}{
+			"status":    entity.WaitlistStatusClaimed,
+			"ticket_id": ticketID,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -7,13 +7,26 @@ import (
 )
 
 // InitRouter initializes the router with all controllers and services
-func InitRouter(controllers *controller.Controllers, auditService service.AuditService) *gin.Engine {
+func InitRouter(controllers *controller.Controllers, auditService service.AuditService, idempotencyService service.IdempotencyService) *gin.Engine {
 	return SetupRouter(
 		controllers.UserController,
 		controllers.EventController,
 		controllers.TicketController,
 		controllers.ReportController,
 		controllers.AuditController,
+		controllers.OrderController,
+		controllers.TierController,
+		controllers.PaymentController,
+		controllers.RefundController,
+		controllers.WaitlistController,
+		controllers.PromoCodeController,
+		controllers.VenueController,
+		controllers.SeatMapController,
+		controllers.SeriesController,
+		controllers.CategoryController,
+		controllers.FileController,
+		controllers.MediaController,
 		auditService,
+		idempotencyService,
 	)
 } 
\ No newline at end of file
@@ -16,7 +16,20 @@ func SetupRouter(
 	ticketController controller.TicketController,
 	reportController controller.ReportController,
 	auditController controller.AuditController,
+	orderController controller.OrderController,
+	tierController controller.TicketTierController,
+	paymentController controller.PaymentController,
+	refundController controller.RefundController,
+	waitlistController controller.WaitlistController,
+	promoCodeController controller.PromoCodeController,
+	venueController controller.VenueController,
+	seatMapController controller.SeatMapController,
+	seriesController controller.EventSeriesController,
+	categoryController controller.CategoryController,
+	fileController controller.FileController,
+	mediaController controller.MediaController,
 	auditService service.AuditService,
+	idempotencyService service.IdempotencyService,
 ) *gin.Engine {
 	// Initialize router
 	router := gin.Default()
@@ -25,7 +38,7 @@ func SetupRouter(
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Idempotency-Key")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
@@ -45,13 +58,33 @@ func SetupRouter(
 	// Public routes
 	router.POST("/register", userController.Register)
 	router.POST("/login", userController.Login)
-	router.GET("/events", eventController.GetAllEvents)
-	router.GET("/events/:id", eventController.GetEventByID)
+	router.GET("/events", middleware.OptionalAuthMiddleware(), eventController.GetAllEvents)
+	router.GET("/events/search", eventController.SearchEvents)
+	router.GET("/events/:id", middleware.OptionalAuthMiddleware(), eventController.GetEventByID)
+	router.GET("/events/:id/tiers", middleware.OptionalAuthMiddleware(), tierController.GetEventTiers)
+	router.GET("/events/:id/cancellation-policy", refundController.GetCancellationPolicy)
+	router.GET("/events/:id/seats", middleware.OptionalAuthMiddleware(), seatMapController.GetEventSeats)
+	router.GET("/venues", venueController.GetAllVenues)
+	router.GET("/venues/:id", venueController.GetVenueByID)
+	router.GET("/venues/:id/events", venueController.GetVenueEvents)
+	router.GET("/seat-maps/:id", seatMapController.GetSeatMapByID)
+	router.GET("/event-series", seriesController.GetAllSeries)
+	router.GET("/event-series/:id", seriesController.GetSeriesByID)
+	router.GET("/event-series/:id/events", seriesController.GetSeriesOccurrences)
+	router.GET("/categories", categoryController.GetAllCategories)
+	router.GET("/categories/:id", categoryController.GetCategoryByID)
+	router.GET("/tags", categoryController.GetAllTags)
+	router.GET("/files/:type/:filename", middleware.OptionalAuthMiddleware(), fileController.DownloadFile)
+	router.POST("/payments/webhook", paymentController.HandleWebhook)
 
 	// Protected routes
 	authRoutes := router.Group("/")
 	authRoutes.Use(middleware.AuthMiddleware())
 	{
+		// Retries of requests that take seats or start payments are answered
+		// from the first attempt when they carry an Idempotency-Key header
+		idempotent := middleware.IdempotencyMiddleware(idempotencyService)
+
 		// User routes
 		authRoutes.GET("/profile", userController.Profile)
 		authRoutes.POST("/logout", userController.Logout)
@@ -60,8 +93,40 @@ func SetupRouter(
 		// Ticket routes
 		authRoutes.GET("/tickets", ticketController.GetAllTickets)
 		authRoutes.GET("/tickets/:id", ticketController.GetTicketByID)
-		authRoutes.POST("/tickets", ticketController.PurchaseTicket)
+		authRoutes.POST("/tickets", idempotent, ticketController.PurchaseTicket)
 		authRoutes.PATCH("/tickets/:id", ticketController.CancelTicket)
+		authRoutes.POST("/tickets/hold", idempotent, ticketController.HoldTicket)
+		authRoutes.POST("/tickets/:id/confirm", idempotent, ticketController.ConfirmHold)
+		authRoutes.POST("/tickets/:id/release", ticketController.ReleaseHold)
+		authRoutes.GET("/tickets/:id/qr", ticketController.GetTicketQRCode)
+		authRoutes.GET("/tickets/:id/pdf", ticketController.GetTicketPDF)
+
+		// Ticket transfer routes
+		authRoutes.POST("/tickets/:id/transfer", ticketController.StartTransfer)
+		authRoutes.POST("/transfers/:id/accept", ticketController.AcceptTransfer)
+		authRoutes.POST("/transfers/:id/cancel", ticketController.CancelTransfer)
+		authRoutes.GET("/my-transfers", ticketController.GetMyTransfers)
+
+		// Order routes
+		authRoutes.POST("/orders", idempotent, orderController.CreateOrder)
+		authRoutes.GET("/orders/:id", orderController.GetOrderByID)
+		authRoutes.GET("/my-orders", orderController.GetMyOrders)
+
+		// Waitlist routes
+		authRoutes.POST("/events/:id/waitlist", waitlistController.JoinWaitlist)
+		authRoutes.DELETE("/events/:id/waitlist", waitlistController.LeaveWaitlist)
+		authRoutes.GET("/events/:id/waitlist/me", waitlistController.GetMyWaitlistEntry)
+		authRoutes.POST("/events/:id/waitlist/claim", idempotent, waitlistController.ClaimOffer)
+
+		// Payment routes
+		authRoutes.GET("/payments/:id", paymentController.GetPaymentByID)
+	}
+
+	// Door staff routes
+	staffRoutes := router.Group("/")
+	staffRoutes.Use(middleware.StaffMiddleware())
+	{
+		staffRoutes.POST("/checkin", ticketController.CheckIn)
 	}
 
 	// Admin routes
@@ -72,10 +137,70 @@ func SetupRouter(
 		adminRoutes.POST("/events", eventController.CreateEvent)
 		adminRoutes.PUT("/events/:id", eventController.UpdateEvent)
 		adminRoutes.DELETE("/events/:id", eventController.DeleteEvent)
+		adminRoutes.POST("/events/:id/cancel", eventController.CancelEvent)
+		adminRoutes.POST("/events/:id/postpone", eventController.PostponeEvent)
+		adminRoutes.POST("/events/:id/publish", eventController.PublishEvent)
+		adminRoutes.POST("/events/lifecycle/run", eventController.RunLifecycle)
+
+		// Ticket tier management
+		adminRoutes.POST("/events/:id/tiers", tierController.CreateTier)
+		adminRoutes.PUT("/events/:id/tiers/:tier_id", tierController.UpdateTier)
+		adminRoutes.DELETE("/events/:id/tiers/:tier_id", tierController.DeleteTier)
+
+		// Cancellation policy and refunds
+		adminRoutes.PUT("/events/:id/cancellation-policy", refundController.SetCancellationPolicy)
+		adminRoutes.GET("/events/:id/refunds", refundController.GetEventRefunds)
+		adminRoutes.POST("/events/:id/refunds", refundController.RefundCancelledEvent)
+
+		// Waitlist management
+		adminRoutes.GET("/events/:id/waitlist", waitlistController.GetEventWaitlist)
+
+		// Promo code management
+		adminRoutes.GET("/promo-codes", promoCodeController.GetAllPromoCodes)
+		adminRoutes.GET("/promo-codes/:id", promoCodeController.GetPromoCodeByID)
+		adminRoutes.POST("/promo-codes", promoCodeController.CreatePromoCode)
+		adminRoutes.PUT("/promo-codes/:id", promoCodeController.UpdatePromoCode)
+		adminRoutes.DELETE("/promo-codes/:id", promoCodeController.DeletePromoCode)
+
+		// Venue management
+		adminRoutes.POST("/venues", venueController.CreateVenue)
+		adminRoutes.PUT("/venues/:id", venueController.UpdateVenue)
+		adminRoutes.DELETE("/venues/:id", venueController.DeleteVenue)
+
+		// Seat map management
+		adminRoutes.GET("/seat-maps", seatMapController.GetAllSeatMaps)
+		adminRoutes.POST("/seat-maps", seatMapController.CreateSeatMap)
+		adminRoutes.DELETE("/seat-maps/:id", seatMapController.DeleteSeatMap)
+
+		// Event series management
+		adminRoutes.POST("/event-series", seriesController.CreateSeries)
+		adminRoutes.PUT("/event-series/:id", seriesController.UpdateSeries)
+		adminRoutes.DELETE("/event-series/:id", seriesController.DeleteSeries)
+
+		// Categories and tags
+		adminRoutes.POST("/categories", categoryController.CreateCategory)
+		adminRoutes.PUT("/categories/:id", categoryController.UpdateCategory)
+		adminRoutes.DELETE("/categories/:id", categoryController.DeleteCategory)
+		adminRoutes.POST("/tags", categoryController.CreateTag)
+		adminRoutes.PUT("/tags/:id", categoryController.UpdateTag)
+		adminRoutes.DELETE("/tags/:id", categoryController.DeleteTag)
+		adminRoutes.PUT("/events/:id/categories", categoryController.SetEventCategories)
+		adminRoutes.PUT("/events/:id/tags", categoryController.SetEventTags)
+
+		// Event media
+		adminRoutes.POST("/events/:id/media", mediaController.AddMedia)
+		adminRoutes.PUT("/events/:id/media", mediaController.ReorderMedia)
+		adminRoutes.PUT("/events/:id/media/:media_id", mediaController.UpdateMedia)
+		adminRoutes.DELETE("/events/:id/media/:media_id", mediaController.DeleteMedia)
+
+		// File management
+		adminRoutes.POST("/files/upload", fileController.UploadFile)
+		adminRoutes.DELETE("/files/:type/:filename", fileController.DeleteFile)
 
 		// Reports
 		adminRoutes.GET("/reports/summary", reportController.GetSalesReport)
 		adminRoutes.GET("/reports/event/:id", reportController.GetEventSalesReport)
+		adminRoutes.GET("/reports/categories", reportController.GetCategorySalesReport)
 		
 		// Report exports
 		adminRoutes.GET("/reports/summary/pdf", reportController.ExportSalesReportPDF)
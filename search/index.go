@@ -0,0 +1,22 @@
+package search
+
+import (
+	"github.com/taufikmulyawan/ticketing-system/entity"
+)
+
+// Index answers free text queries over events. Implementations must be safe
+// for concurrent use.
+type Index interface {
+	// Index adds the event or replaces what is indexed for it
+	Index(event *entity.Event)
+	// Remove drops the event from the index
+	Remove(eventID uint)
+	// Search returns the events matching the query, most relevant first
+	Search(query string) []Hit
+}
+
+// Hit is an event matching a query with its relevance score
+type Hit struct {
+	EventID uint
+	Score   float64
+}
@@ -0,0 +1,226 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/taufikmulyawan/ticketing-system/entity"
+)
+
+// Field weights, a match in the name counts more than one in the description
+const (
+	nameWeight        = 3.0
+	locationWeight    = 2.0
+	descriptionWeight = 1.0
+)
+
+// Similarity of an indexed term to a query term that was not matched exactly
+const (
+	prefixSimilarity = 0.8
+	typoSimilarity   = 0.6
+)
+
+// memoryIndex is an inverted index held in process memory. It is rebuilt from
+// the database on startup and kept in step by the event service.
+type memoryIndex struct {
+	mu sync.RWMutex
+	// postings maps each term to the weighted frequency of the term per event
+	postings map[string]map[uint]float64
+	// terms lists the terms indexed for each event so they can be removed
+	terms map[uint][]string
+}
+
+func NewMemoryIndex() Index {
+	return &memoryIndex{
+		postings: make(map[string]map[uint]float64),
+		terms:    make(map[uint][]string),
+	}
+}
+
+func (idx *memoryIndex) Index(event *entity.Event) {
+	frequencies := make(map[string]float64)
+	for _, field := range []struct {
+		text   string
+		weight float64
+	}{
+		{event.Name, nameWeight},
+		{event.Location, locationWeight},
+		{event.Description, descriptionWeight},
+	} {
+		for _, term := range tokenize(field.text) {
+			frequencies[term] += field.weight
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(event.ID)
+	terms := make([]string, 0, len(frequencies))
+	for term, frequency := range frequencies {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[uint]float64)
+		}
+		idx.postings[term][event.ID] = frequency
+		terms = append(terms, term)
+	}
+	idx.terms[event.ID] = terms
+}
+
+func (idx *memoryIndex) Remove(eventID uint) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(eventID)
+}
+
+// remove drops an event from the postings. The caller must hold the write lock.
+func (idx *memoryIndex) remove(eventID uint) {
+	for _, term := range idx.terms[eventID] {
+		delete(idx.postings[term], eventID)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.terms, eventID)
+}
+
+// Search scores every event containing at least one query term. Each query
+// term contributes its best match in the event, exact, as a prefix or within
+// a small edit distance, weighted by how rare the matched term is. Events
+// matching more of the query rank higher.
+func (idx *memoryIndex) Search(query string) []Hit {
+	queryTerms := tokenize(query)
+	if len(queryTerms) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	totalEvents := float64(len(idx.terms))
+	scores := make(map[uint]float64)
+	matched := make(map[uint]int)
+
+	for _, queryTerm := range queryTerms {
+		best := make(map[uint]float64)
+		for term, events := range idx.postings {
+			similarity := termSimilarity(queryTerm, term)
+			if similarity == 0 {
+				continue
+			}
+
+			idf := math.Log(1 + totalEvents/float64(len(events)))
+			for eventID, frequency := range events {
+				score := similarity * idf * (1 + math.Log(frequency))
+				if score > best[eventID] {
+					best[eventID] = score
+				}
+			}
+		}
+
+		for eventID, score := range best {
+			scores[eventID] += score
+			matched[eventID]++
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for eventID, score := range scores {
+		coverage := float64(matched[eventID]) / float64(len(queryTerms))
+		hits = append(hits, Hit{EventID: eventID, Score: score * coverage * coverage})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].EventID < hits[j].EventID
+	})
+	return hits
+}
+
+// tokenize splits text into lower case words of at least two characters
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	terms := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) >= 2 {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// termSimilarity rates how well an indexed term matches a query term, from 1
+// for an exact match to 0 for no match. Prefixes match once the query term has
+// three characters, and longer terms tolerate one or two typos.
+func termSimilarity(queryTerm, term string) float64 {
+	if queryTerm == term {
+		return 1
+	}
+
+	queryLength := len([]rune(queryTerm))
+	if queryLength >= 3 && strings.HasPrefix(term, queryTerm) {
+		return prefixSimilarity
+	}
+
+	maxEdits := 0
+	switch {
+	case queryLength >= 8:
+		maxEdits = 2
+	case queryLength >= 4:
+		maxEdits = 1
+	}
+	if maxEdits > 0 && editDistance(queryTerm, term, maxEdits) <= maxEdits {
+		return typoSimilarity
+	}
+
+	return 0
+}
+
+// editDistance counts the insertions, deletions, substitutions and swaps of
+// adjacent characters turning a into b. It gives up once the distance is
+// known to exceed limit and then returns limit+1.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > limit || -diff > limit {
+		return limit + 1
+	}
+
+	// Three rows of the dynamic programming table are enough for swaps
+	previous2 := make([]int, len(rb)+1)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				current[j] = min(current[j], previous2[j-2]+1)
+			}
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		previous2, previous, current = previous, current, previous2
+	}
+
+	return previous[len(rb)]
+}
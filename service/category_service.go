@@ -0,0 +1,235 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+)
+
+// CategoryService manages the categories and tags events are classified with
+type CategoryService interface {
+	GetAllCategories() ([]entity.Category, error)
+	GetCategoryByID(id uint) (*entity.Category, error)
+	CreateCategory(category *entity.Category) error
+	UpdateCategory(id uint, category *entity.Category) error
+	DeleteCategory(id uint) error
+	GetAllTags() ([]entity.Tag, error)
+	GetTagByID(id uint) (*entity.Tag, error)
+	CreateTag(tag *entity.Tag) error
+	UpdateTag(id uint, tag *entity.Tag) error
+	DeleteTag(id uint) error
+	SetEventCategories(eventID uint, categoryIDs []uint) (*entity.Event, error)
+	SetEventTags(eventID uint, tagIDs []uint) (*entity.Event, error)
+}
+
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+	tagRepo      repository.TagRepository
+	eventRepo    repository.EventRepository
+}
+
+func NewCategoryService(categoryRepo repository.CategoryRepository, tagRepo repository.TagRepository, eventRepo repository.EventRepository) CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		eventRepo:    eventRepo,
+	}
+}
+
+// GetAllCategories lists every category with its number of upcoming public events
+func (s *categoryService) GetAllCategories() ([]entity.Category, error) {
+	return s.categoryRepo.FindAll(time.Now())
+}
+
+func (s *categoryService) GetCategoryByID(id uint) (*entity.Category, error) {
+	return s.categoryRepo.FindByID(id)
+}
+
+func (s *categoryService) CreateCategory(category *entity.Category) error {
+	if err := s.validateCategory(0, category); err != nil {
+		return err
+	}
+
+	category.ID = 0
+	return s.categoryRepo.Save(category)
+}
+
+func (s *categoryService) UpdateCategory(id uint, category *entity.Category) error {
+	// Get existing category
+	existingCategory, err := s.categoryRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateCategory(id, category); err != nil {
+		return err
+	}
+
+	// Update category fields
+	existingCategory.Name = category.Name
+	existingCategory.Slug = category.Slug
+	existingCategory.Description = category.Description
+
+	return s.categoryRepo.Save(existingCategory)
+}
+
+func (s *categoryService) DeleteCategory(id uint) error {
+	return s.categoryRepo.Delete(id)
+}
+
+// GetAllTags lists every tag with its number of upcoming public events
+func (s *categoryService) GetAllTags() ([]entity.Tag, error) {
+	return s.tagRepo.FindAll(time.Now())
+}
+
+func (s *categoryService) GetTagByID(id uint) (*entity.Tag, error) {
+	return s.tagRepo.FindByID(id)
+}
+
+func (s *categoryService) CreateTag(tag *entity.Tag) error {
+	if err := s.validateTag(0, tag); err != nil {
+		return err
+	}
+
+	tag.ID = 0
+	return s.tagRepo.Save(tag)
+}
+
+func (s *categoryService) UpdateTag(id uint, tag *entity.Tag) error {
+	// Get existing tag
+	existingTag, err := s.tagRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateTag(id, tag); err != nil {
+		return err
+	}
+
+	existingTag.Name = tag.Name
+	existingTag.Slug = tag.Slug
+
+	return s.tagRepo.Save(existingTag)
+}
+
+func (s *categoryService) DeleteTag(id uint) error {
+	return s.tagRepo.Delete(id)
+}
+
+// SetEventCategories puts an event in exactly the given categories, an empty
+// list takes it out of all of them
+func (s *categoryService) SetEventCategories(eventID uint, categoryIDs []uint) (*entity.Event, error) {
+	// Check if event exists
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.FindByIDs(categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) != countDistinct(categoryIDs) {
+		return nil, errors.New("category not found")
+	}
+
+	if err := s.eventRepo.ReplaceCategories(eventID, categories); err != nil {
+		return nil, err
+	}
+
+	return s.eventRepo.FindByID(eventID)
+}
+
+// SetEventTags gives an event exactly the given tags, an empty list removes all of them
+func (s *categoryService) SetEventTags(eventID uint, tagIDs []uint) (*entity.Event, error) {
+	// Check if event exists
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.FindByIDs(tagIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != countDistinct(tagIDs) {
+		return nil, errors.New("tag not found")
+	}
+
+	if err := s.eventRepo.ReplaceTags(eventID, tags); err != nil {
+		return nil, err
+	}
+
+	return s.eventRepo.FindByID(eventID)
+}
+
+// validateCategory trims the fields of a category and derives its slug.
+// categoryID is the category being replaced, if any.
+func (s *categoryService) validateCategory(categoryID uint, category *entity.Category) error {
+	category.Name = strings.TrimSpace(category.Name)
+	category.Description = strings.TrimSpace(category.Description)
+	category.Slug = slugify(category.Name)
+
+	if category.Name == "" {
+		return errors.New("category name is required")
+	}
+	if category.Slug == "" {
+		return errors.New("category name must contain letters or digits")
+	}
+
+	if existing, err := s.categoryRepo.FindBySlug(category.Slug); err == nil && existing.ID != categoryID {
+		return errors.New("category already exists")
+	}
+
+	return nil
+}
+
+// validateTag trims the name of a tag and derives its slug. tagID is the tag
+// being replaced, if any.
+func (s *categoryService) validateTag(tagID uint, tag *entity.Tag) error {
+	tag.Name = strings.TrimSpace(tag.Name)
+	tag.Slug = slugify(tag.Name)
+
+	if tag.Name == "" {
+		return errors.New("tag name is required")
+	}
+	if tag.Slug == "" {
+		return errors.New("tag name must contain letters or digits")
+	}
+
+	if existing, err := s.tagRepo.FindBySlug(tag.Slug); err == nil && existing.ID != tagID {
+		return errors.New("tag already exists")
+	}
+
+	return nil
+}
+
+// slugify turns a name into the lowercase, dash separated form used to filter
+// events, so "Live Music & Jazz" becomes "live-music-jazz"
+func slugify(name string) string {
+	var slug strings.Builder
+	separate := false
+	for _, r := range strings.ToLower(name) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			separate = true
+			continue
+		}
+		if separate && slug.Len() > 0 {
+			slug.WriteByte('-')
+		}
+		slug.WriteRune(r)
+		separate = false
+	}
+	return slug.String()
+}
+
+// countDistinct counts the different IDs in a list
+func countDistinct(ids []uint) int {
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	return len(seen)
+}
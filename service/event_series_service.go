@@ -0,0 +1,415 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+)
+
+// maxSeriesOccurrences caps how many events one series can generate, a year of
+// daily occurrences
+const maxSeriesOccurrences = 366
+
+type EventSeriesService interface {
+	GetAllSeries(page, limit int) ([]entity.EventSeries, int64, error)
+	GetSeriesByID(id uint) (*entity.EventSeries, error)
+	GetSeriesOccurrences(id uint, page, limit int) ([]entity.Event, int64, error)
+	CreateSeries(series *entity.EventSeries) error
+	UpdateSeries(id uint, series *entity.EventSeries) error
+	DeleteSeries(id uint) error
+}
+
+type eventSeriesService struct {
+	seriesRepo   repository.EventSeriesRepository
+	eventRepo    repository.EventRepository
+	venueRepo    repository.VenueRepository
+	eventService EventService
+}
+
+func NewEventSeriesService(seriesRepo repository.EventSeriesRepository, eventRepo repository.EventRepository, venueRepo repository.VenueRepository, eventService EventService) EventSeriesService {
+	return &eventSeriesService{
+		seriesRepo:   seriesRepo,
+		eventRepo:    eventRepo,
+		venueRepo:    venueRepo,
+		eventService: eventService,
+	}
+}
+
+// seriesOccurrence is a day a series takes place on and when it starts that day
+type seriesOccurrence struct {
+	Date  string
+	Start time.Time
+}
+
+func (s *eventSeriesService) GetAllSeries(page, limit int) ([]entity.EventSeries, int64, error) {
+	// Default pagination values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	return s.seriesRepo.FindAll(page, limit)
+}
+
+func (s *eventSeriesService) GetSeriesByID(id uint) (*entity.EventSeries, error) {
+	return s.seriesRepo.FindByID(id)
+}
+
+// GetSeriesOccurrences lists the events generated for a series, soonest first
+func (s *eventSeriesService) GetSeriesOccurrences(id uint, page, limit int) ([]entity.Event, int64, error) {
+	// Check if series exists
+	if _, err := s.seriesRepo.FindByID(id); err != nil {
+		return nil, 0, err
+	}
+
+	// Default pagination values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	now := time.Now()
+	return s.eventRepo.FindAll(page, limit, repository.EventFilter{SeriesID: &id, Published: &now})
+}
+
+// CreateSeries saves a series and generates an event for each of its occurrences
+func (s *eventSeriesService) CreateSeries(series *entity.EventSeries) error {
+	if series.Interval == 0 {
+		series.Interval = 1
+	}
+
+	loc, err := s.validateSeries(0, series)
+	if err != nil {
+		return err
+	}
+	if series.StartDate.Before(time.Now()) {
+		return errors.New("series start date must be in the future")
+	}
+
+	occurrences, err := occurrenceStarts(series, loc)
+	if err != nil {
+		return err
+	}
+	if len(occurrences) == 0 {
+		return errors.New("series has no occurrences")
+	}
+
+	// The series is only kept when all of its occurrences could be generated
+	series.ID = 0
+	return s.eventService.WithinTransaction(func(events EventService, repos *repository.Repositories) error {
+		if err := repos.EventSeriesRepository.Save(series); err != nil {
+			return err
+		}
+		return syncOccurrences(events, repos, series, occurrences)
+	})
+}
+
+// UpdateSeries changes the details, end and exceptions of a series and applies
+// them to every upcoming occurrence. Occurrences that are no longer part of
+// the series are deleted and new ones are generated. Single occurrences are
+// edited as events, but editing the series overwrites their details. The
+// series and its occurrences are changed all or nothing.
+func (s *eventSeriesService) UpdateSeries(id uint, series *entity.EventSeries) error {
+	existingSeries, err := s.seriesRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	// Occurrences are keyed by the days the recurrence rule produces
+	if (series.Frequency != "" && series.Frequency != existingSeries.Frequency) ||
+		(series.Interval != 0 && series.Interval != existingSeries.Interval) ||
+		(!series.StartDate.IsZero() && !series.StartDate.Equal(existingSeries.StartDate)) ||
+		(series.DurationMinutes != 0 && series.DurationMinutes != existingSeries.DurationMinutes) {
+		return errors.New("the recurrence of a series cannot be changed, create a new series instead")
+	}
+
+	updated := *existingSeries
+	updated.Name = series.Name
+	updated.Description = series.Description
+	updated.Location = series.Location
+	updated.VenueID = series.VenueID
+	updated.Capacity = series.Capacity
+	updated.Price = series.Price
+	updated.Until = series.Until
+	updated.Count = series.Count
+	updated.Exceptions = series.Exceptions
+
+	loc, err := s.validateSeries(id, &updated)
+	if err != nil {
+		return err
+	}
+
+	occurrences, err := occurrenceStarts(&updated, loc)
+	if err != nil {
+		return err
+	}
+	wanted := make(map[string]bool, len(occurrences))
+	for _, occurrence := range occurrences {
+		wanted[occurrence.Date] = true
+	}
+
+	existing, err := s.eventRepo.FindBySeriesID(id)
+	if err != nil {
+		return err
+	}
+
+	// Check every upcoming occurrence before changing any of them
+	upcoming := make([]entity.Event, 0, len(existing))
+	for _, event := range existing {
+		if !isUpcomingOccurrence(&event) {
+			continue
+		}
+		if !wanted[event.SeriesDate] {
+			if event.SoldCount > 0 {
+				return fmt.Errorf("the occurrence on %s has tickets sold, cancel it instead of removing it from the series", event.SeriesDate)
+			}
+			continue
+		}
+		if updated.Capacity < event.SoldCount {
+			return fmt.Errorf("series capacity cannot be lower than tickets already sold for the occurrence on %s", event.SeriesDate)
+		}
+		upcoming = append(upcoming, event)
+	}
+
+	err = s.eventService.WithinTransaction(func(events EventService, repos *repository.Repositories) error {
+		if err := repos.EventSeriesRepository.Save(&updated); err != nil {
+			return err
+		}
+
+		for _, event := range upcoming {
+			event.Name = updated.Name
+			event.Description = updated.Description
+			event.Location = updated.Location
+			event.VenueID = updated.VenueID
+			event.Capacity = updated.Capacity
+			event.Price = updated.Price
+			if err := events.UpdateEvent(event.ID, &event); err != nil {
+				return fmt.Errorf("updating the occurrence on %s: %w", event.SeriesDate, err)
+			}
+		}
+
+		return syncOccurrences(events, repos, &updated, occurrences)
+	})
+	if err != nil {
+		return err
+	}
+
+	*series = updated
+	return nil
+}
+
+// DeleteSeries deletes a series together with its occurrences. Series with
+// tickets sold for any occurrence are kept.
+func (s *eventSeriesService) DeleteSeries(id uint) error {
+	if _, err := s.seriesRepo.FindByID(id); err != nil {
+		return err
+	}
+
+	occurrences, err := s.eventRepo.FindBySeriesID(id)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range occurrences {
+		if event.SoldCount > 0 {
+			return errors.New("cannot delete a series with tickets sold, cancel its occurrences instead")
+		}
+	}
+
+	return s.eventService.WithinTransaction(func(events EventService, repos *repository.Repositories) error {
+		for _, event := range occurrences {
+			if err := events.DeleteEvent(event.ID); err != nil {
+				return err
+			}
+		}
+		return repos.EventSeriesRepository.Delete(id)
+	})
+}
+
+// syncOccurrences generates the events of upcoming occurrences that do not
+// have one yet and deletes unsold upcoming events that are no longer part of
+// the series. Cancelled occurrences keep their day, so they are not generated
+// again. It must run inside a transaction.
+func syncOccurrences(events EventService, repos *repository.Repositories, series *entity.EventSeries, occurrences []seriesOccurrence) error {
+	existing, err := repos.EventRepository.FindBySeriesID(series.ID)
+	if err != nil {
+		return err
+	}
+
+	byDate := make(map[string]bool, len(existing))
+	for _, event := range existing {
+		byDate[event.SeriesDate] = true
+	}
+
+	now := time.Now()
+	wanted := make(map[string]bool, len(occurrences))
+	for _, occurrence := range occurrences {
+		wanted[occurrence.Date] = true
+		if byDate[occurrence.Date] || !occurrence.Start.After(now) {
+			continue
+		}
+
+		seriesID := series.ID
+		event := &entity.Event{
+			Name:        series.Name,
+			Description: series.Description,
+			Location:    series.Location,
+			VenueID:     series.VenueID,
+			StartDate:   occurrence.Start,
+			EndDate:     occurrence.Start.Add(time.Duration(series.DurationMinutes) * time.Minute),
+			Capacity:    series.Capacity,
+			Price:       series.Price,
+			SeriesID:    &seriesID,
+			SeriesDate:  occurrence.Date,
+		}
+		if err := events.CreateEvent(event); err != nil {
+			return fmt.Errorf("generating the occurrence on %s: %w", occurrence.Date, err)
+		}
+	}
+
+	for _, event := range existing {
+		if wanted[event.SeriesDate] || !isUpcomingOccurrence(&event) || event.SoldCount > 0 {
+			continue
+		}
+		if err := events.DeleteEvent(event.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateSeries trims and checks the fields of a series and returns the
+// timezone it recurs in. seriesID is the series being replaced, if any.
+func (s *eventSeriesService) validateSeries(seriesID uint, series *entity.EventSeries) (*time.Location, error) {
+	series.Name = strings.TrimSpace(series.Name)
+	series.Location = strings.TrimSpace(series.Location)
+
+	if series.Name == "" {
+		return nil, errors.New("series name is required")
+	}
+	if series.Capacity <= 0 {
+		return nil, errors.New("series capacity must be positive")
+	}
+	if series.Price < 0 {
+		return nil, errors.New("series price cannot be negative")
+	}
+
+	switch series.Frequency {
+	case entity.RecurrenceDaily, entity.RecurrenceWeekly, entity.RecurrenceMonthly:
+	default:
+		return nil, fmt.Errorf("unknown frequency %q, use daily, weekly or monthly", series.Frequency)
+	}
+	if series.Interval <= 0 {
+		return nil, errors.New("series interval must be positive")
+	}
+	if series.DurationMinutes <= 0 {
+		return nil, errors.New("series duration_minutes must be positive")
+	}
+
+	if series.Count < 0 {
+		return nil, errors.New("series count cannot be negative")
+	}
+	if (series.Until == nil) == (series.Count == 0) {
+		return nil, errors.New("exactly one of until and count is required")
+	}
+
+	exceptions := make([]string, 0, len(series.Exceptions))
+	for _, exception := range series.Exceptions {
+		day, err := time.Parse("2006-01-02", strings.TrimSpace(exception))
+		if err != nil {
+			return nil, fmt.Errorf("invalid exception %q, use YYYY-MM-DD", exception)
+		}
+		exceptions = append(exceptions, day.Format("2006-01-02"))
+	}
+	slices.Sort(exceptions)
+	series.Exceptions = slices.Compact(exceptions)
+
+	// Series without a venue recur in UTC
+	loc := time.UTC
+	if series.VenueID != nil {
+		venue, err := s.venueRepo.FindByID(*series.VenueID)
+		if err != nil {
+			return nil, err
+		}
+		if series.Capacity > venue.MaxCapacity {
+			return nil, fmt.Errorf("series capacity cannot be higher than the venue's max capacity of %d", venue.MaxCapacity)
+		}
+		if loc, err = time.LoadLocation(venue.Timezone); err != nil {
+			return nil, err
+		}
+	} else if series.Location == "" {
+		return nil, errors.New("series location is required")
+	}
+
+	if existing, err := s.seriesRepo.FindByName(series.Name); err == nil && existing.ID != seriesID {
+		return nil, errors.New("event series already exists")
+	}
+
+	return loc, nil
+}
+
+// occurrenceStarts expands the recurrence rule of a series into the days it
+// takes place on, in the given timezone so occurrences keep their local start
+// time across daylight saving changes. Like RFC 5545, months without the start
+// day are skipped and not counted, while exceptions do count towards Count.
+func occurrenceStarts(series *entity.EventSeries, loc *time.Location) ([]seriesOccurrence, error) {
+	start := series.StartDate.In(loc)
+
+	until := ""
+	if series.Until != nil {
+		until = series.Until.In(loc).Format("2006-01-02")
+	}
+
+	occurrences := make([]seriesOccurrence, 0)
+	generated := 0
+	for i := 0; ; i++ {
+		var at time.Time
+		switch series.Frequency {
+		case entity.RecurrenceDaily:
+			at = start.AddDate(0, 0, i*series.Interval)
+		case entity.RecurrenceWeekly:
+			at = start.AddDate(0, 0, 7*i*series.Interval)
+		case entity.RecurrenceMonthly:
+			at = start.AddDate(0, i*series.Interval, 0)
+			if at.Day() != start.Day() {
+				continue
+			}
+		}
+
+		date := at.Format("2006-01-02")
+		if until != "" && date > until {
+			break
+		}
+		if series.Count > 0 && generated >= series.Count {
+			break
+		}
+
+		generated++
+		if generated > maxSeriesOccurrences {
+			return nil, fmt.Errorf("a series cannot have more than %d occurrences", maxSeriesOccurrences)
+		}
+
+		if slices.Contains(series.Exceptions, date) {
+			continue
+		}
+		occurrences = append(occurrences, seriesOccurrence{Date: date, Start: at})
+	}
+
+	return occurrences, nil
+}
+
+// isUpcomingOccurrence reports whether an occurrence has yet to start and is
+// still on sale, so that editing its series applies to it
+func isUpcomingOccurrence(event *entity.Event) bool {
+	return slices.Contains(entity.OpenEventStatuses, event.Status) && event.StartDate.After(time.Now())
+}
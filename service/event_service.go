@@ -2,31 +2,90 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/taufikmulyawan/ticketing-system/dto"
 	"github.com/taufikmulyawan/ticketing-system/entity"
 	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/search"
 )
 
+// ErrInvalidEventFilter is returned when the filters of the event listing cannot be used
+var ErrInvalidEventFilter = errors.New("invalid event filter")
+
 type EventService interface {
-	GetAllEvents(page, limit int) ([]entity.Event, int64, error)
+	GetAllEvents(page, limit int, filter dto.EventFilterRequest) ([]entity.Event, int64, error)
 	GetEventByID(id uint) (*entity.Event, error)
 	CreateEvent(event *entity.Event) error
 	UpdateEvent(id uint, event *entity.Event) error
 	DeleteEvent(id uint) error
+	SearchEvents(query string, page, limit int) ([]entity.Event, int64, error)
+	RebuildSearchIndex() error
+	CancelEvent(id uint, reason string) (*entity.Event, []entity.Refund, error)
+	PostponeEvent(id uint, startDate, endDate time.Time, reason string) (*entity.Event, error)
+	AdvanceLifecycle(actorID uint) ([]EventTransition, error)
+	PublishEvent(id uint, publishAt *time.Time) (*entity.Event, error)
+	WithinTransaction(fn func(events EventService, repos *repository.Repositories) error) error
+}
+
+// EventTransition records an event moving on to the next stage of its lifecycle
+type EventTransition struct {
+	EventID uint               `json:"event_id"`
+	Name    string             `json:"name"`
+	From    entity.EventStatus `json:"from"`
+	To      entity.EventStatus `json:"to"`
 }
 
 type eventService struct {
-	eventRepo repository.EventRepository
+	eventRepo     repository.EventRepository
+	venueRepo     repository.VenueRepository
+	seatMapRepo   repository.SeatMapRepository
+	refundService RefundService
+	auditService  AuditService
+	searchIndex   search.Index
+	fileService   FileService
+	transactor    repository.Transactor
 }
 
-func NewEventService(eventRepo repository.EventRepository) EventService {
+func NewEventService(eventRepo repository.EventRepository, venueRepo repository.VenueRepository, seatMapRepo repository.SeatMapRepository, refundService RefundService, auditService AuditService, searchIndex search.Index, fileService FileService, transactor repository.Transactor) EventService {
 	return &eventService{
-		eventRepo: eventRepo,
+		eventRepo:     eventRepo,
+		venueRepo:     venueRepo,
+		seatMapRepo:   seatMapRepo,
+		refundService: refundService,
+		auditService:  auditService,
+		searchIndex:   searchIndex,
+		fileService:   fileService,
+		transactor:    transactor,
 	}
 }
 
-func (s *eventService) GetAllEvents(page, limit int) ([]entity.Event, int64, error) {
+// WithinTransaction runs fn with an event service and repositories bound to
+// one database transaction, so several events can be changed all or nothing.
+// The search index only sees the changes once the transaction is committed.
+func (s *eventService) WithinTransaction(fn func(events EventService, repos *repository.Repositories) error) error {
+	pending := &pendingIndex{index: s.searchIndex}
+	err := s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		events := *s
+		events.eventRepo = repos.EventRepository
+		events.venueRepo = repos.VenueRepository
+		events.seatMapRepo = repos.SeatMapRepository
+		events.transactor = repos.Transactor
+		events.searchIndex = pending
+		return fn(&events, repos)
+	})
+	if err != nil {
+		return err
+	}
+
+	pending.apply()
+	return nil
+}
+
+func (s *eventService) GetAllEvents(page, limit int, filter dto.EventFilterRequest) ([]entity.Event, int64, error) {
 	// Default pagination values
 	if page <= 0 {
 		page = 1
@@ -35,11 +94,87 @@ func (s *eventService) GetAllEvents(page, limit int) ([]entity.Event, int64, err
 		limit = 10
 	}
 
-	return s.eventRepo.FindAll(page, limit)
+	eventFilter, err := parseEventFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.eventRepo.FindAll(page, limit, eventFilter)
+}
+
+// parseEventFilter checks the listing filters sent by a client and converts
+// them for the repository
+func parseEventFilter(filter dto.EventFilterRequest) (repository.EventFilter, error) {
+	eventFilter := repository.EventFilter{
+		Name:      strings.TrimSpace(filter.Name),
+		Location:  strings.TrimSpace(filter.Location),
+		VenueID:   filter.VenueID,
+		City:      strings.TrimSpace(filter.City),
+		SeriesID:  filter.SeriesID,
+		Category:  slugify(filter.Category),
+		Tag:       slugify(filter.Tag),
+		Status:    entity.EventStatus(filter.Status),
+		MinPrice:  filter.MinPrice,
+		MaxPrice:  filter.MaxPrice,
+		Available: filter.Available,
+		Sort:      filter.Sort,
+	}
+
+	// Drafts and events scheduled for later stay hidden from the public
+	if !filter.Drafts {
+		now := time.Now()
+		eventFilter.Published = &now
+	}
+
+	if filter.StartDate != "" {
+		startFrom, err := time.Parse("2006-01-02", filter.StartDate)
+		if err != nil {
+			return eventFilter, fmt.Errorf("%w: invalid start_date format, use YYYY-MM-DD", ErrInvalidEventFilter)
+		}
+		eventFilter.StartFrom = &startFrom
+	}
+
+	if filter.EndDate != "" {
+		startTo, err := time.Parse("2006-01-02", filter.EndDate)
+		if err != nil {
+			return eventFilter, fmt.Errorf("%w: invalid end_date format, use YYYY-MM-DD", ErrInvalidEventFilter)
+		}
+		// Include events starting at any time on the end date
+		startTo = startTo.Add(24*time.Hour - time.Second)
+		eventFilter.StartTo = &startTo
+	}
+
+	switch eventFilter.Status {
+	case "", entity.EventStatusActive, entity.EventStatusOngoing, entity.EventStatusFinished, entity.EventStatusCancelled, entity.EventStatusPostponed:
+	default:
+		return eventFilter, fmt.Errorf("%w: unknown status %q", ErrInvalidEventFilter, filter.Status)
+	}
+
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return eventFilter, fmt.Errorf("%w: min_price cannot be higher than max_price", ErrInvalidEventFilter)
+	}
+
+	if _, ok := repository.EventSorts[filter.Sort]; filter.Sort != "" && !ok {
+		return eventFilter, fmt.Errorf("%w: unknown sort %q", ErrInvalidEventFilter, filter.Sort)
+	}
+
+	return eventFilter, nil
 }
 
 func (s *eventService) GetEventByID(id uint) (*entity.Event, error) {
-	return s.eventRepo.FindByID(id)
+	event, err := s.eventRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Point the gallery at where its images are served from
+	for i := range event.Media {
+		if event.Media[i].URL, err = s.fileService.FileURL(event.Media[i].Filename, MediaFileType); err != nil {
+			return nil, err
+		}
+	}
+
+	return event, nil
 }
 
 func (s *eventService) CreateEvent(event *entity.Event) error {
@@ -47,12 +182,18 @@ func (s *eventService) CreateEvent(event *entity.Event) error {
 	if event.Name == "" {
 		return errors.New("event name is required")
 	}
-	if event.Location == "" {
-		return errors.New("event location is required")
-	}
 	if event.Capacity <= 0 {
 		return errors.New("event capacity must be positive")
 	}
+	if err := s.applyVenue(event); err != nil {
+		return err
+	}
+	if err := s.validateSeatMap(event); err != nil {
+		return err
+	}
+	if event.Location == "" {
+		return errors.New("event location is required")
+	}
 	if event.Price < 0 {
 		return errors.New("event price cannot be negative")
 	}
@@ -62,6 +203,15 @@ func (s *eventService) CreateEvent(event *entity.Event) error {
 	if event.EndDate.Before(event.StartDate) {
 		return errors.New("event end date must be after start date")
 	}
+	if err := validatePurchaseRules(event); err != nil {
+		return err
+	}
+	if err := validateSalesWindow(event); err != nil {
+		return err
+	}
+	if err := s.checkNameAvailable(0, event); err != nil {
+		return err
+	}
 
 	// Set default status
 	if event.Status == "" {
@@ -69,7 +219,12 @@ func (s *eventService) CreateEvent(event *entity.Event) error {
 	}
 
 	// Save event
-	return s.eventRepo.Save(event)
+	if err := s.eventRepo.Save(event); err != nil {
+		return err
+	}
+
+	s.searchIndex.Index(event)
+	return nil
 }
 
 func (s *eventService) UpdateEvent(id uint, event *entity.Event) error {
@@ -84,20 +239,475 @@ func (s *eventService) UpdateEvent(id uint, event *entity.Event) error {
 		return errors.New("cannot update a finished event")
 	}
 
+	// Cancelled events have had their tickets refunded
+	if existingEvent.Status == entity.EventStatusCancelled {
+		return errors.New("cannot update a cancelled event")
+	}
+
+	// The status only changes through the lifecycle job and the cancel and
+	// postpone workflows, an update without one keeps the current status
+	if event.Status == "" {
+		event.Status = existingEvent.Status
+	}
+	if event.Status != existingEvent.Status {
+		return errors.New("the event status cannot be updated, use the cancel or postpone endpoint instead")
+	}
+
+	// Capacity cannot drop below the seats that are already taken
+	if event.Capacity < existingEvent.SoldCount {
+		return errors.New("event capacity cannot be lower than tickets already sold")
+	}
+
+	if err := validatePurchaseRules(event); err != nil {
+		return err
+	}
+
+	if err := validateSalesWindow(event); err != nil {
+		return err
+	}
+
+	// Ticket holders must still be able to find their event
+	now := time.Now()
+	if existingEvent.SoldCount > 0 && IsEventPublished(existingEvent, now) && !IsEventPublished(event, now) {
+		return errors.New("cannot unpublish an event with tickets sold")
+	}
+
+	if err := s.applyVenue(event); err != nil {
+		return err
+	}
+
+	// Buyers picked their seats on the current seat map
+	if existingEvent.SoldCount > 0 && !sameID(event.SeatMapID, existingEvent.SeatMapID) {
+		return errors.New("cannot change the seat map of an event with tickets sold")
+	}
+	if err := s.validateSeatMap(event); err != nil {
+		return err
+	}
+
+	// Occurrences stay in their series
+	event.SeriesID = existingEvent.SeriesID
+	if err := s.checkNameAvailable(id, event); err != nil {
+		return err
+	}
+
+	addedSeats := event.Capacity - existingEvent.Capacity
+
 	// Update event fields
 	existingEvent.Name = event.Name
 	existingEvent.Description = event.Description
 	existingEvent.Location = event.Location
+	existingEvent.VenueID = event.VenueID
+	existingEvent.Venue = event.Venue
+	existingEvent.SeatMapID = event.SeatMapID
 	existingEvent.StartDate = event.StartDate
 	existingEvent.EndDate = event.EndDate
 	existingEvent.Capacity = event.Capacity
 	existingEvent.Price = event.Price
-	existingEvent.Status = event.Status
+	existingEvent.MaxTicketsPerUser = event.MaxTicketsPerUser
+	existingEvent.MaxTicketsPerOrder = event.MaxTicketsPerOrder
+	existingEvent.PurchaseCooldownSeconds = event.PurchaseCooldownSeconds
+	existingEvent.Draft = event.Draft
+	existingEvent.PublishAt = event.PublishAt
+	existingEvent.SalesStart = event.SalesStart
+	existingEvent.SalesEnd = event.SalesEnd
 
-	// Save updated event
-	return s.eventRepo.Save(existingEvent)
+	// Save updated event and offer the seats it gained to the waitlist
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.EventRepository.Save(existingEvent); err != nil {
+			return err
+		}
+		return offerNewSeats(repos, existingEvent.ID, nil, addedSeats)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.searchIndex.Index(existingEvent)
+	return nil
 }
 
 func (s *eventService) DeleteEvent(id uint) error {
-	return s.eventRepo.Delete(id)
-} 
\ No newline at end of file
+	if err := s.eventRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.searchIndex.Remove(id)
+	return nil
+}
+
+// SearchEvents finds public events matching free text in their name, location
+// or description, most relevant first
+func (s *eventService) SearchEvents(query string, page, limit int) ([]entity.Event, int64, error) {
+	// Default pagination values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	// Drafts are indexed too so they are found as soon as they are published
+	hits := s.searchIndex.Search(query)
+
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.EventID
+	}
+	found, err := s.eventRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Put the public events back in order of relevance
+	now := time.Now()
+	byID := make(map[uint]entity.Event, len(found))
+	for _, event := range found {
+		byID[event.ID] = event
+	}
+	events := make([]entity.Event, 0, len(hits))
+	for _, id := range ids {
+		if event, ok := byID[id]; ok && IsEventPublished(&event, now) {
+			events = append(events, event)
+		}
+	}
+	total := int64(len(events))
+
+	offset := (page - 1) * limit
+	if offset >= len(events) {
+		return []entity.Event{}, total, nil
+	}
+
+	return events[offset:min(offset+limit, len(events))], total, nil
+}
+
+// RebuildSearchIndex indexes every event in the database. It is run on startup.
+func (s *eventService) RebuildSearchIndex() error {
+	for page := 1; ; page++ {
+		events, _, err := s.eventRepo.FindAll(page, 500, repository.EventFilter{})
+		if err != nil {
+			return err
+		}
+
+		for i := range events {
+			s.searchIndex.Index(&events[i])
+		}
+
+		if len(events) < 500 {
+			return nil
+		}
+	}
+}
+
+// CancelEvent calls off an event that has not started and cascades to its
+// tickets: holds are released and paid tickets are refunded in full. When
+// refunding fails, for instance because the gateway rejected some refunds, the
+// event stays cancelled and is returned along with the error and the refunds
+// sent, and POST /events/:id/refunds retries whatever was not paid back.
+func (s *eventService) CancelEvent(id uint, reason string) (*entity.Event, []entity.Refund, error) {
+	event, err := s.eventRepo.FindByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		return nil, nil, errors.New("a reason is required to cancel an event")
+	}
+
+	if !slices.Contains(entity.OpenEventStatuses, event.Status) {
+		return nil, nil, errors.New("only events that have not started can be cancelled")
+	}
+
+	updated, err := s.eventRepo.ChangeStatus(id, event.Status, entity.EventStatusCancelled, map[string]interface{}{
+		"status_reason": reason,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !updated {
+		return nil, nil, errors.New("event has changed, please try again")
+	}
+
+	refunds, err := s.refundService.RefundCancelledEvent(id)
+	if err != nil {
+		event.Status = entity.EventStatusCancelled
+		event.StatusReason = reason
+		return event, refunds, fmt.Errorf("the event was cancelled but refunding its tickets failed, retry with POST /events/%d/refunds: %w", id, err)
+	}
+
+	event, err = s.eventRepo.FindByID(id)
+	return event, refunds, err
+}
+
+// PostponeEvent moves an event that has not started to later dates. Tickets
+// stay valid for the new dates and holders who bought them before the
+// postponement may opt out for a full refund.
+func (s *eventService) PostponeEvent(id uint, startDate, endDate time.Time, reason string) (*entity.Event, error) {
+	event, err := s.eventRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.New("a reason is required to postpone an event")
+	}
+
+	if !slices.Contains(entity.OpenEventStatuses, event.Status) {
+		return nil, errors.New("only events that have not started can be postponed")
+	}
+
+	if !startDate.After(event.StartDate) {
+		return nil, errors.New("the new start date must be after the current start date")
+	}
+	if endDate.Before(startDate) {
+		return nil, errors.New("event end date must be after start date")
+	}
+
+	// Keep the date the event was first announced for across postponements
+	originalStartDate := event.StartDate
+	if event.OriginalStartDate != nil {
+		originalStartDate = *event.OriginalStartDate
+	}
+
+	updated, err := s.eventRepo.ChangeStatus(id, event.Status, entity.EventStatusPostponed, map[string]interface{}{
+		"start_date":          startDate,
+		"end_date":            endDate,
+		"status_reason":       reason,
+		"original_start_date": originalStartDate,
+		"postponed_at":        time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("event has changed, please try again")
+	}
+
+	return s.eventRepo.FindByID(id)
+}
+
+// PublishEvent makes a draft public, right away or at publishAt when it is
+// given. Publishing an event that is already public moves its publish time.
+func (s *eventService) PublishEvent(id uint, publishAt *time.Time) (*entity.Event, error) {
+	event, err := s.eventRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(entity.OpenEventStatuses, event.Status) {
+		return nil, errors.New("only events that have not started can be published")
+	}
+
+	now := time.Now()
+	if publishAt == nil {
+		publishAt = &now
+	}
+	if event.SoldCount > 0 && publishAt.After(now) {
+		return nil, errors.New("cannot unpublish an event with tickets sold")
+	}
+
+	event.Draft = false
+	event.PublishAt = publishAt
+	if err := s.eventRepo.Save(event); err != nil {
+		return nil, err
+	}
+
+	s.searchIndex.Index(event)
+	return event, nil
+}
+
+// AdvanceLifecycle moves active and postponed events to ongoing once they
+// start and ongoing events to finished once they end, recording each step in
+// the audit trail under actorID (0 when run by the scheduler). Cancelled
+// events are left alone.
+func (s *eventService) AdvanceLifecycle(actorID uint) ([]EventTransition, error) {
+	now := time.Now()
+	transitions := make([]EventTransition, 0)
+
+	started, err := s.eventRepo.FindDueToStart(now)
+	if err != nil {
+		return transitions, err
+	}
+	for _, event := range started {
+		if err := s.transition(&event, entity.EventStatusOngoing, actorID, &transitions); err != nil {
+			return transitions, err
+		}
+	}
+
+	// Events that started and ended since the last run finish in the same run
+	ended, err := s.eventRepo.FindDueToFinish(now)
+	if err != nil {
+		return transitions, err
+	}
+	for _, event := range ended {
+		if err := s.transition(&event, entity.EventStatusFinished, actorID, &transitions); err != nil {
+			return transitions, err
+		}
+	}
+
+	return transitions, nil
+}
+
+// transition moves the event to the given status unless it was changed in the
+// meantime, and logs the change
+func (s *eventService) transition(event *entity.Event, to entity.EventStatus, actorID uint, transitions *[]EventTransition) error {
+	updated, err := s.eventRepo.UpdateStatus(event.ID, event.Status, to)
+	if err != nil || !updated {
+		return err
+	}
+
+	*transitions = append(*transitions, EventTransition{
+		EventID: event.ID,
+		Name:    event.Name,
+		From:    event.Status,
+		To:      to,
+	})
+
+	s.auditService.LogActivity(
+		actorID,
+		entity.ActionUpdate,
+		"event",
+		event.ID,
+		map[string]interface{}{"status": event.Status},
+		map[string]interface{}{"status": to},
+		"",
+		"",
+	)
+	return nil
+}
+
+// applyVenue links the event to its venue, if any, checking that the venue can
+// seat the event. Events at a venue are located there unless told otherwise.
+func (s *eventService) applyVenue(event *entity.Event) error {
+	event.Venue = nil
+	if event.VenueID == nil {
+		return nil
+	}
+
+	venue, err := s.venueRepo.FindByID(*event.VenueID)
+	if err != nil {
+		return err
+	}
+
+	if event.Capacity > venue.MaxCapacity {
+		return fmt.Errorf("event capacity cannot be higher than the venue's max capacity of %d", venue.MaxCapacity)
+	}
+
+	if strings.TrimSpace(event.Location) == "" {
+		event.Location = venue.Name + ", " + venue.City
+	}
+	event.Venue = venue
+	return nil
+}
+
+// validateSeatMap checks that the seat map of an event with reserved seating
+// belongs to its venue and has a seat for every ticket
+func (s *eventService) validateSeatMap(event *entity.Event) error {
+	if event.SeatMapID == nil {
+		return nil
+	}
+
+	seatMap, err := s.seatMapRepo.FindByID(*event.SeatMapID)
+	if err != nil {
+		return err
+	}
+
+	if seatMap.VenueID != nil && !sameID(seatMap.VenueID, event.VenueID) {
+		return errors.New("seat map belongs to another venue")
+	}
+
+	seats, err := s.seatMapRepo.CountSeats(seatMap.ID)
+	if err != nil {
+		return err
+	}
+	if int64(event.Capacity) > seats {
+		return fmt.Errorf("event capacity cannot be higher than the %d seats of its seat map", seats)
+	}
+
+	return nil
+}
+
+// checkNameAvailable makes sure no other standalone event uses the name of
+// the event. Occurrences of a series share the name of their series.
+func (s *eventService) checkNameAvailable(eventID uint, event *entity.Event) error {
+	if event.SeriesID != nil {
+		return nil
+	}
+
+	taken, err := s.eventRepo.NameTaken(event.Name, eventID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return errors.New("event name already exists")
+	}
+	return nil
+}
+
+// sameID reports whether two optional IDs refer to the same record
+func sameID(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// IsEventPublished reports whether an event is visible to the public at the given time
+func IsEventPublished(event *entity.Event, at time.Time) bool {
+	return !event.Draft && (event.PublishAt == nil || !event.PublishAt.After(at))
+}
+
+// validateSalesWindow checks that ticket sales of an event close before it starts
+func validateSalesWindow(event *entity.Event) error {
+	if event.SalesStart != nil && event.SalesEnd != nil && !event.SalesEnd.After(*event.SalesStart) {
+		return errors.New("sales_end must be after sales_start")
+	}
+	if event.SalesEnd != nil && event.SalesEnd.After(event.StartDate) {
+		return errors.New("sales_end cannot be after the event starts")
+	}
+	if event.SalesStart != nil && !event.SalesStart.Before(event.StartDate) {
+		return errors.New("sales_start must be before the event starts")
+	}
+	return nil
+}
+
+// validatePurchaseRules checks the per-buyer limits of an event
+func validatePurchaseRules(event *entity.Event) error {
+	if event.MaxTicketsPerUser < 0 || event.MaxTicketsPerOrder < 0 {
+		return errors.New("ticket limits cannot be negative")
+	}
+	if event.MaxTicketsPerUser > 0 && event.MaxTicketsPerOrder > event.MaxTicketsPerUser {
+		return errors.New("max_tickets_per_order cannot be higher than max_tickets_per_user")
+	}
+	if event.PurchaseCooldownSeconds < 0 {
+		return errors.New("purchase_cooldown_seconds cannot be negative")
+	}
+	return nil
+}
+
+// pendingIndex holds back the index updates of a transaction until it is
+// committed. Searches go to the index as it is.
+type pendingIndex struct {
+	index   search.Index
+	updates []func(index search.Index)
+}
+
+func (p *pendingIndex) Index(event *entity.Event) {
+	indexed := *event
+	p.updates = append(p.updates, func(index search.Index) { index.Index(&indexed) })
+}
+
+func (p *pendingIndex) Remove(eventID uint) {
+	p.updates = append(p.updates, func(index search.Index) { index.Remove(eventID) })
+}
+
+func (p *pendingIndex) Search(query string) []search.Hit {
+	return p.index.Search(query)
+}
+
+// apply passes the held back updates on to the index in order
+func (p *pendingIndex) apply() {
+	for _, update := range p.updates {
+		update(p.index)
+	}
+	p.updates = nil
+}
@@ -4,41 +4,33 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/storage"
 )
 
 // FileService handles file upload and download operations
 type FileService interface {
 	UploadFile(file *multipart.FileHeader, fileType string) (string, error)
-	GetFilePath(filename string, fileType string) (string, error)
+	OpenFile(filename string, fileType string) (io.ReadCloser, error)
 	DeleteFile(filename string, fileType string) error
+	FileURL(filename string, fileType string) (string, error)
 }
 
 type fileService struct {
-	baseStoragePath string
+	driver storage.Driver
 }
 
-func NewFileService() FileService {
-	// Create uploads directory if it doesn't exist
-	uploadDir := "uploads"
-	if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
-		os.MkdirAll(uploadDir, 0755)
-	}
-
-	// Create subdirectories for different file types
-	for _, dir := range []string{"events", "tickets", "profiles"} {
-		fullPath := filepath.Join(uploadDir, dir)
-		if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-			os.MkdirAll(fullPath, 0755)
-		}
-	}
-
+// NewFileService stores files with the given driver, local disk or an S3
+// compatible bucket as selected by STORAGE_DRIVER
+func NewFileService(driver storage.Driver) FileService {
 	return &fileService{
-		baseStoragePath: uploadDir,
+		driver: driver,
 	}
 }
 
@@ -51,51 +43,30 @@ func (s *fileService) UploadFile(file *multipart.FileHeader, fileType string) (s
 	// Generate unique file name to prevent collisions
 	extension := filepath.Ext(file.Filename)
 	newFilename := fmt.Sprintf("%d%s", time.Now().UnixNano(), extension)
-	
-	// Create the full path for storing the file
-	storagePath := filepath.Join(s.baseStoragePath, fileType)
-	filePath := filepath.Join(storagePath, newFilename)
-	
+
 	// Open the uploaded file
 	src, err := file.Open()
 	if err != nil {
 		return "", err
 	}
 	defer src.Close()
-	
-	// Create the destination file
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer dst.Close()
-	
-	// Copy the uploaded file to the destination file
-	if _, err = io.Copy(dst, src); err != nil {
+
+	// Store the uploaded file under its type
+	if err := s.driver.Put(fileKey(newFilename, fileType), src, file.Size, mime.TypeByExtension(extension)); err != nil {
 		return "", err
 	}
-	
+
 	return newFilename, nil
 }
 
-func (s *fileService) GetFilePath(filename string, fileType string) (string, error) {
+// OpenFile opens a stored file for reading, the caller closes it
+func (s *fileService) OpenFile(filename string, fileType string) (io.ReadCloser, error) {
 	// Validate file type
 	if !isValidFileType(fileType) {
-		return "", errors.New("invalid file type")
-	}
-	
-	// Prevent directory traversal attacks
-	filename = filepath.Base(filename)
-	
-	// Create the full path
-	fullPath := filepath.Join(s.baseStoragePath, fileType, filename)
-	
-	// Check if file exists
-	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-		return "", errors.New("file not found")
+		return nil, errors.New("invalid file type")
 	}
-	
-	return fullPath, nil
+
+	return s.driver.Get(fileKey(filename, fileType))
 }
 
 func (s *fileService) DeleteFile(filename string, fileType string) error {
@@ -103,20 +74,27 @@ func (s *fileService) DeleteFile(filename string, fileType string) error {
 	if !isValidFileType(fileType) {
 		return errors.New("invalid file type")
 	}
-	
-	// Prevent directory traversal attacks
-	filename = filepath.Base(filename)
-	
-	// Create the full path
-	fullPath := filepath.Join(s.baseStoragePath, fileType, filename)
-	
-	// Check if file exists
-	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-		return errors.New("file not found")
-	}
-	
-	// Delete the file
-	return os.Remove(fullPath)
+
+	return s.driver.Delete(fileKey(filename, fileType))
+}
+
+// FileURL is the address a stored file is downloaded from. Files in a bucket
+// get a presigned URL that expires after STORAGE_URL_EXPIRY.
+func (s *fileService) FileURL(filename string, fileType string) (string, error) {
+	return s.driver.PresignGet(fileKey(filename, fileType), config.AppConfig.StorageURLExpiry)
+}
+
+// fileKey is the storage key of a file. Only the base name is used to prevent
+// directory traversal attacks.
+func fileKey(filename string, fileType string) string {
+	return fileType + "/" + filepath.Base(filename)
+}
+
+// IsPublicFileType reports whether files of the type can be downloaded without
+// signing in. Only event images are public, ticket files and profile pictures
+// belong to users.
+func IsPublicFileType(fileType string) bool {
+	return fileType == MediaFileType
 }
 
 // isValidFileType checks if the file type is allowed
@@ -140,4 +118,4 @@ func isImageFile(filename string) bool {
 func isPdfFile(filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
 	return ext == ".pdf"
-} 
\ No newline at end of file
+}
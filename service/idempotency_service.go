@@ -0,0 +1,101 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+)
+
+// idempotencyLockDuration is how long a request in progress holds its key. A
+// key left behind by a request that never finished can be reused after this.
+const idempotencyLockDuration = time.Minute
+
+var (
+	// ErrIdempotencyKeyReused is returned when a key is sent again with a different request
+	ErrIdempotencyKeyReused = errors.New("Idempotency-Key has already been used for a different request")
+
+	// ErrIdempotencyKeyInProgress is returned when a retry arrives while the
+	// original request is still being processed
+	ErrIdempotencyKeyInProgress = errors.New("a request with this Idempotency-Key is still being processed")
+)
+
+type IdempotencyService interface {
+	Begin(userID uint, key string, requestHash string) (*entity.IdempotencyKey, error)
+	Complete(record *entity.IdempotencyKey, status int, body []byte) error
+	Release(record *entity.IdempotencyKey) error
+	PurgeExpired() error
+}
+
+type idempotencyService struct {
+	idempotencyRepo repository.IdempotencyRepository
+}
+
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyRepository) IdempotencyService {
+	return &idempotencyService{
+		idempotencyRepo: idempotencyRepo,
+	}
+}
+
+// Begin claims the key for a request. It returns a completed record when the
+// same request was already answered, in which case the stored response should
+// be replayed, or a new processing record otherwise.
+func (s *idempotencyService) Begin(userID uint, key string, requestHash string) (*entity.IdempotencyKey, error) {
+	if len(key) > 255 {
+		return nil, errors.New("Idempotency-Key cannot be longer than 255 characters")
+	}
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		existing, findErr := s.idempotencyRepo.FindByUserAndKey(userID, key)
+		if findErr == nil {
+			if existing.ExpiresAt.After(now) {
+				if existing.RequestHash != requestHash {
+					return nil, ErrIdempotencyKeyReused
+				}
+				if existing.Status != entity.IdempotencyStatusCompleted {
+					return nil, ErrIdempotencyKeyInProgress
+				}
+				return existing, nil
+			}
+
+			// The key has expired and is free to be used again
+			if err := s.idempotencyRepo.Delete(existing.ID); err != nil {
+				return nil, err
+			}
+		}
+
+		record := &entity.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+			Status:      entity.IdempotencyStatusProcessing,
+			ExpiresAt:   now.Add(idempotencyLockDuration),
+		}
+		if err = s.idempotencyRepo.Create(record); err == nil {
+			return record, nil
+		}
+		// A concurrent request with the same key got there first, look at its record
+	}
+
+	return nil, err
+}
+
+// Complete stores the response so retries within the TTL are answered with it
+func (s *idempotencyService) Complete(record *entity.IdempotencyKey, status int, body []byte) error {
+	return s.idempotencyRepo.Complete(record.ID, status, string(body), time.Now().Add(config.AppConfig.IdempotencyKeyTTL))
+}
+
+// Release frees the key of a request that failed on the server so it can be retried
+func (s *idempotencyService) Release(record *entity.IdempotencyKey) error {
+	return s.idempotencyRepo.Delete(record.ID)
+}
+
+// PurgeExpired removes keys past their TTL. It is run periodically by a background job.
+func (s *idempotencyService) PurgeExpired() error {
+	_, err := s.idempotencyRepo.DeleteExpired(time.Now())
+	return err
+}
@@ -1,23 +1,60 @@
 package service
 
-import "github.com/taufikmulyawan/ticketing-system/repository"
+import (
+	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/search"
+	"github.com/taufikmulyawan/ticketing-system/storage"
+)
 
 // Services holds all service instances
 type Services struct {
-	UserService   UserService
-	EventService  EventService
-	TicketService TicketService
-	ReportService ReportService
-	AuditService  AuditService
+	UserService        UserService
+	EventService       EventService
+	TicketService      TicketService
+	ReportService      ReportService
+	AuditService       AuditService
+	OrderService       OrderService
+	TierService        TicketTierService
+	PaymentService     PaymentService
+	RefundService      RefundService
+	WaitlistService    WaitlistService
+	PromoCodeService   PromoCodeService
+	IdempotencyService IdempotencyService
+	VenueService       VenueService
+	SeatMapService     SeatMapService
+	EventSeriesService EventSeriesService
+	CategoryService    CategoryService
+	FileService        FileService
+	MediaService       MediaService
 }
 
-// InitServices initializes all services with their required repositories
-func InitServices(repos *repository.Repositories) *Services {
+// InitServices initializes all services with their required repositories,
+// the payment gateway and the storage uploaded files are kept in
+func InitServices(repos *repository.Repositories, gateway PaymentGateway, fileStorage storage.Driver) *Services {
+	paymentService := NewPaymentService(repos.PaymentRepository, gateway, repos.Transactor)
+	auditService := NewAuditService(repos.AuditRepository)
+	refundService := NewRefundService(repos.RefundRepository, repos.PolicyRepository, repos.PaymentRepository, repos.TicketRepository, repos.EventRepository, gateway, repos.Transactor)
+	fileService := NewFileService(fileStorage)
+	eventService := NewEventService(repos.EventRepository, repos.VenueRepository, repos.SeatMapRepository, refundService, auditService, search.NewMemoryIndex(), fileService, repos.Transactor)
+
 	return &Services{
-		UserService:   NewUserService(repos.UserRepository),
-		EventService:  NewEventService(repos.EventRepository),
-		TicketService: NewTicketService(repos.TicketRepository, repos.EventRepository),
-		ReportService: NewReportService(repos.TicketRepository, repos.EventRepository),
-		AuditService:  NewAuditService(repos.AuditRepository),
+		UserService:        NewUserService(repos.UserRepository),
+		EventService:       eventService,
+		TicketService:      NewTicketService(repos.TicketRepository, repos.EventRepository, repos.TierRepository, repos.SeatMapRepository, repos.UserRepository, repos.TransferRepository, paymentService, refundService, repos.Transactor),
+		ReportService:      NewReportService(repos.TicketRepository, repos.EventRepository, repos.TierRepository, repos.RefundRepository, repos.CategoryRepository),
+		AuditService:       auditService,
+		OrderService:       NewOrderService(repos.OrderRepository, repos.EventRepository, repos.TierRepository, repos.SeatMapRepository, paymentService, repos.Transactor),
+		TierService:        NewTicketTierService(repos.TierRepository, repos.EventRepository, repos.Transactor),
+		PaymentService:     paymentService,
+		RefundService:      refundService,
+		WaitlistService:    NewWaitlistService(repos.WaitlistRepository, repos.EventRepository, repos.TierRepository, paymentService, repos.Transactor),
+		PromoCodeService:   NewPromoCodeService(repos.PromoCodeRepository, repos.EventRepository),
+		IdempotencyService: NewIdempotencyService(repos.IdempotencyRepository),
+		VenueService:       NewVenueService(repos.VenueRepository, repos.EventRepository),
+		SeatMapService:     NewSeatMapService(repos.SeatMapRepository, repos.VenueRepository, repos.EventRepository),
+		EventSeriesService: NewEventSeriesService(repos.EventSeriesRepository, repos.EventRepository, repos.VenueRepository, eventService),
+		CategoryService:    NewCategoryService(repos.CategoryRepository, repos.TagRepository, repos.EventRepository),
+		FileService:        fileService,
+		MediaService:       NewMediaService(repos.MediaRepository, repos.EventRepository, fileService, repos.Transactor),
 	}
-} 
\ No newline at end of file
+}
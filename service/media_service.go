@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"mime/multipart"
+	"strings"
+
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/storage"
+)
+
+// MediaFileType is the upload type event images are stored under
+const MediaFileType = "events"
+
+// MediaService manages the image galleries of events
+type MediaService interface {
+	GetMediaByID(eventID uint, mediaID uint) (*entity.Media, error)
+	AddMedia(eventID uint, file *multipart.FileHeader, caption string, isCover bool) (*entity.Media, error)
+	UpdateMedia(eventID uint, mediaID uint, media *entity.Media) (*entity.Media, error)
+	ReorderMedia(eventID uint, mediaIDs []uint) ([]entity.Media, error)
+	DeleteMedia(eventID uint, mediaID uint) error
+}
+
+type mediaService struct {
+	mediaRepo   repository.MediaRepository
+	eventRepo   repository.EventRepository
+	fileService FileService
+	transactor  repository.Transactor
+}
+
+func NewMediaService(mediaRepo repository.MediaRepository, eventRepo repository.EventRepository, fileService FileService, transactor repository.Transactor) MediaService {
+	return &mediaService{
+		mediaRepo:   mediaRepo,
+		eventRepo:   eventRepo,
+		fileService: fileService,
+		transactor:  transactor,
+	}
+}
+
+// GetMediaByID gets an image from the gallery of the given event
+func (s *mediaService) GetMediaByID(eventID uint, mediaID uint) (*entity.Media, error) {
+	media, err := s.mediaRepo.FindByID(mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.EventID != eventID {
+		return nil, errors.New("media not found")
+	}
+
+	if err := s.fillURL(media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// AddMedia stores an image and appends it to the event's gallery. The first
+// image of a gallery becomes its cover.
+func (s *mediaService) AddMedia(eventID uint, file *multipart.FileHeader, caption string, isCover bool) (*entity.Media, error) {
+	// Check if event exists
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, err
+	}
+
+	if !isImageFile(file.Filename) {
+		return nil, errors.New("only JPG, PNG and GIF images can be added to an event")
+	}
+
+	existing, err := s.mediaRepo.FindByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	filename, err := s.fileService.UploadFile(file, MediaFileType)
+	if err != nil {
+		return nil, err
+	}
+
+	media := &entity.Media{
+		EventID:  eventID,
+		Filename: filename,
+		Caption:  strings.TrimSpace(caption),
+		Position: nextMediaPosition(existing),
+	}
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.MediaRepository.Save(media); err != nil {
+			return err
+		}
+		if isCover || len(existing) == 0 {
+			return repos.MediaRepository.SetCover(eventID, media.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		// Do not leave the stored file behind without its gallery entry
+		s.fileService.DeleteFile(filename, MediaFileType)
+		return nil, err
+	}
+	media.IsCover = isCover || len(existing) == 0
+
+	if err := s.fillURL(media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// UpdateMedia changes the caption of an image and whether it is the cover.
+// Making an image the cover takes the flag off the previous one.
+func (s *mediaService) UpdateMedia(eventID uint, mediaID uint, media *entity.Media) (*entity.Media, error) {
+	existingMedia, err := s.GetMediaByID(eventID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	wasCover := existingMedia.IsCover
+	existingMedia.Caption = strings.TrimSpace(media.Caption)
+	existingMedia.IsCover = wasCover && media.IsCover
+	if err := s.mediaRepo.Save(existingMedia); err != nil {
+		return nil, err
+	}
+
+	if media.IsCover && !wasCover {
+		if err := s.mediaRepo.SetCover(eventID, mediaID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetMediaByID(eventID, mediaID)
+}
+
+// ReorderMedia puts the gallery in the given order, which has to list every
+// image of the event exactly once
+func (s *mediaService) ReorderMedia(eventID uint, mediaIDs []uint) ([]entity.Media, error) {
+	existing, err := s.eventMedia(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	inGallery := make(map[uint]bool, len(existing))
+	for _, media := range existing {
+		inGallery[media.ID] = true
+	}
+	if len(mediaIDs) != len(existing) || countDistinct(mediaIDs) != len(mediaIDs) {
+		return nil, errors.New("the new order has to list every image of the event exactly once")
+	}
+	for _, id := range mediaIDs {
+		if !inGallery[id] {
+			return nil, errors.New("media not found")
+		}
+	}
+
+	if err := s.mediaRepo.Reorder(eventID, mediaIDs); err != nil {
+		return nil, err
+	}
+
+	return s.eventMedia(eventID)
+}
+
+// DeleteMedia removes an image from the gallery and deletes its file. When it
+// was the cover, the next image in the gallery takes its place.
+func (s *mediaService) DeleteMedia(eventID uint, mediaID uint) error {
+	media, err := s.GetMediaByID(eventID, mediaID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mediaRepo.Delete(mediaID); err != nil {
+		return err
+	}
+
+	// The gallery entry is gone either way, a file missing from storage is not an error
+	if err := s.fileService.DeleteFile(media.Filename, MediaFileType); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+
+	if media.IsCover {
+		remaining, err := s.mediaRepo.FindByEventID(eventID)
+		if err != nil {
+			return err
+		}
+		if len(remaining) > 0 {
+			return s.mediaRepo.SetCover(eventID, remaining[0].ID)
+		}
+	}
+
+	return nil
+}
+
+// eventMedia lists the gallery of an event in display order
+func (s *mediaService) eventMedia(eventID uint) ([]entity.Media, error) {
+	// Check if event exists
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, err
+	}
+
+	media, err := s.mediaRepo.FindByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range media {
+		if err := s.fillURL(&media[i]); err != nil {
+			return nil, err
+		}
+	}
+	return media, nil
+}
+
+// fillURL points the media at where its image is served from
+func (s *mediaService) fillURL(media *entity.Media) error {
+	url, err := s.fileService.FileURL(media.Filename, MediaFileType)
+	if err != nil {
+		return err
+	}
+	media.URL = url
+	return nil
+}
+
+// nextMediaPosition is the position after the last image of a gallery
+func nextMediaPosition(media []entity.Media) int {
+	next := 0
+	for _, m := range media {
+		if m.Position >= next {
+			next = m.Position + 1
+		}
+	}
+	return next
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+)
+
+type OrderService interface {
+	CreateOrder(order *entity.Order) (*entity.Payment, error)
+	GetOrderByID(id uint) (*entity.Order, error)
+	GetUserOrders(page, limit int, userID uint) ([]entity.Order, int64, error)
+}
+
+type orderService struct {
+	orderRepo      repository.OrderRepository
+	eventRepo      repository.EventRepository
+	tierRepo       repository.TicketTierRepository
+	seatMapRepo    repository.SeatMapRepository
+	paymentService PaymentService
+	transactor     repository.Transactor
+}
+
+func NewOrderService(orderRepo repository.OrderRepository, eventRepo repository.EventRepository, tierRepo repository.TicketTierRepository, seatMapRepo repository.SeatMapRepository, paymentService PaymentService, transactor repository.Transactor) OrderService {
+	return &orderService{
+		orderRepo:      orderRepo,
+		eventRepo:      eventRepo,
+		tierRepo:       tierRepo,
+		seatMapRepo:    seatMapRepo,
+		paymentService: paymentService,
+		transactor:     transactor,
+	}
+}
+
+// CreateOrder holds every ticket in the order or none of them and starts a
+// single payment for the total. The order completes when the payment succeeds.
+func (s *orderService) CreateOrder(order *entity.Order) (*entity.Payment, error) {
+	if len(order.Items) == 0 {
+		return nil, errors.New("order must contain at least one item")
+	}
+
+	// Price each line with the same checks as a single ticket purchase. The
+	// total also covers the service fee of every ticket.
+	var total float64
+	tiers := make([]*entity.TicketTier, len(order.Items))
+	events := make(map[uint]*entity.Event)
+	quantities := make(map[uint]int)
+	for i := range order.Items {
+		item := &order.Items[i]
+
+		// A seat is a line of its own
+		if item.SeatID != nil && item.Quantity == 0 {
+			item.Quantity = 1
+		}
+		if item.Quantity <= 0 {
+			return nil, errors.New("order item quantity must be positive")
+		}
+		if item.SeatID != nil && item.Quantity != 1 {
+			return nil, errors.New("order items with a seat_id must have a quantity of 1")
+		}
+
+		event, err := s.eventRepo.FindByID(item.EventID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkEventPurchasable(event); err != nil {
+			return nil, err
+		}
+
+		seat, tier, err := resolveSeatOrTier(s.seatMapRepo, s.tierRepo, event, item.SeatID, item.TierID)
+		if err != nil {
+			return nil, err
+		}
+		tiers[i] = tier
+		events[event.ID] = event
+		quantities[event.ID] += item.Quantity
+
+		item.ID = 0
+		item.Tier = nil
+		item.Seat = nil
+		if tier == nil {
+			item.TierID = nil
+		}
+		if seat == nil {
+			item.SeatID = nil
+		}
+		item.UnitPrice = ticketPrice(event, tier, seat)
+		item.Subtotal = item.UnitPrice * float64(item.Quantity)
+		total += item.Subtotal + config.AppConfig.TicketServiceFee*float64(item.Quantity)
+	}
+
+	order.ID = 0
+	order.Status = entity.OrderStatusPending
+	order.TotalAmount = total
+	order.Tickets = nil
+
+	purchasedAt := time.Now()
+	err := s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		// Reserve seats for every line first so a sold out event aborts the whole order
+		for i, item := range order.Items {
+			if err := reserveSeats(repos, item.EventID, tiers[i], item.Quantity); err != nil {
+				return err
+			}
+		}
+
+		// Lines of the same event count together against its purchase limits
+		checked := make(map[uint]bool)
+		for _, item := range order.Items {
+			if checked[item.EventID] {
+				continue
+			}
+			checked[item.EventID] = true
+			if err := checkPurchaseLimits(repos, events[item.EventID], order.UserID, quantities[item.EventID], purchasedAt); err != nil {
+				return err
+			}
+		}
+
+		if err := repos.OrderRepository.Save(order); err != nil {
+			return err
+		}
+
+		expiresAt := purchasedAt.Add(config.AppConfig.TicketHoldDuration)
+		for _, item := range order.Items {
+			for i := 0; i < item.Quantity; i++ {
+				ticket := entity.Ticket{
+					UserID:      order.UserID,
+					EventID:     item.EventID,
+					OrderID:     &order.ID,
+					TierID:      item.TierID,
+					SeatID:      item.SeatID,
+					Status:      entity.TicketStatusReserved,
+					PurchasedAt: purchasedAt,
+					ExpiresAt:   &expiresAt,
+				}
+				priceTicket(&ticket, item.UnitPrice, 0)
+				if err := repos.TicketRepository.Save(&ticket); err != nil {
+					return err
+				}
+				if err := takeSeat(repos, &ticket); err != nil {
+					return err
+				}
+				order.Tickets = append(order.Tickets, ticket)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := s.paymentService.StartOrderPayment(order)
+	if err != nil {
+		// Give the seats back straight away rather than waiting for the holds to expire
+		s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+			for i := range order.Tickets {
+				if _, err := releaseHeldTicket(repos, &order.Tickets[i], entity.TicketStatusCancelled); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (s *orderService) GetOrderByID(id uint) (*entity.Order, error) {
+	return s.orderRepo.FindByID(id)
+}
+
+func (s *orderService) GetUserOrders(page, limit int, userID uint) ([]entity.Order, int64, error) {
+	// Default pagination values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	return s.orderRepo.FindByUserID(page, limit, userID)
+}
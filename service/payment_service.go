@@ -0,0 +1,215 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+)
+
+// errHoldExpired aborts a payment whose tickets are no longer held for the buyer
+var errHoldExpired = errors.New("ticket hold has expired")
+
+type PaymentService interface {
+	StartTicketPayment(ticket *entity.Ticket, amount float64) (*entity.Payment, error)
+	StartOrderPayment(order *entity.Order) (*entity.Payment, error)
+	HandleWebhook(payload []byte, signature string) error
+	GetPaymentByID(id uint) (*entity.Payment, error)
+}
+
+type paymentService struct {
+	paymentRepo repository.PaymentRepository
+	gateway     PaymentGateway
+	transactor  repository.Transactor
+}
+
+func NewPaymentService(paymentRepo repository.PaymentRepository, gateway PaymentGateway, transactor repository.Transactor) PaymentService {
+	return &paymentService{
+		paymentRepo: paymentRepo,
+		gateway:     gateway,
+		transactor:  transactor,
+	}
+}
+
+// StartTicketPayment opens a charge for a held ticket
+func (s *paymentService) StartTicketPayment(ticket *entity.Ticket, amount float64) (*entity.Payment, error) {
+	return s.startPayment(&entity.Payment{
+		UserID:   ticket.UserID,
+		TicketID: &ticket.ID,
+		Amount:   amount,
+	})
+}
+
+// StartOrderPayment opens a single charge covering every ticket of an order
+func (s *paymentService) StartOrderPayment(order *entity.Order) (*entity.Payment, error) {
+	return s.startPayment(&entity.Payment{
+		UserID:  order.UserID,
+		OrderID: &order.ID,
+		Amount:  order.TotalAmount,
+	})
+}
+
+// startPayment stores a pending payment and registers its intent with the gateway
+func (s *paymentService) startPayment(payment *entity.Payment) (*entity.Payment, error) {
+	payment.Currency = config.AppConfig.Currency
+	payment.Provider = s.gateway.Name()
+	payment.Status = entity.PaymentStatusPending
+
+	if err := s.paymentRepo.Save(payment); err != nil {
+		return nil, err
+	}
+
+	intent, err := s.gateway.CreateIntent(fmt.Sprintf("payment-%d", payment.ID), payment.Amount, payment.Currency)
+	if err != nil {
+		s.paymentRepo.UpdateStatus(payment.ID, entity.PaymentStatusPending, entity.PaymentStatusFailed)
+		return nil, err
+	}
+
+	payment.IntentID = intent.ID
+	if err := s.paymentRepo.Save(payment); err != nil {
+		return nil, err
+	}
+
+	payment.ClientSecret = intent.ClientSecret
+	return payment, nil
+}
+
+// HandleWebhook applies a verified gateway notification. Tickets only become
+// purchased here, once the provider reports that the buyer has paid.
+func (s *paymentService) HandleWebhook(payload []byte, signature string) error {
+	event, err := s.gateway.VerifyWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	payment, err := s.paymentRepo.FindByIntentID(event.IntentID)
+	if err != nil {
+		return err
+	}
+
+	switch event.Status {
+	case PaymentEventSucceeded:
+		if math.Abs(event.Amount-payment.Amount) > 0.005 {
+			return errors.New("payment amount does not match")
+		}
+		return s.completePayment(payment)
+	case PaymentEventFailed:
+		return s.failPayment(payment)
+	default:
+		return errors.New("unsupported payment status")
+	}
+}
+
+// completePayment confirms the held tickets and captures the charge. When the
+// holds ran out before the money arrived the charge is refunded instead.
+func (s *paymentService) completePayment(payment *entity.Payment) error {
+	claimed := false
+	err := s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		updated, err := repos.PaymentRepository.UpdateStatus(payment.ID, entity.PaymentStatusPending, entity.PaymentStatusSucceeded)
+		if err != nil || !updated {
+			return err
+		}
+		claimed = true
+
+		tickets, err := paymentTickets(repos, payment)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, ticket := range tickets {
+			confirmed, err := repos.TicketRepository.ConfirmHold(ticket.ID, now)
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				return errHoldExpired
+			}
+		}
+
+		if payment.OrderID != nil {
+			if _, err := repos.OrderRepository.UpdateStatus(*payment.OrderID, entity.OrderStatusPending, entity.OrderStatusCompleted); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, errHoldExpired) {
+		return s.refundExpiredPayment(payment)
+	}
+	if err != nil || !claimed {
+		// Repeated notifications for a processed payment are ignored
+		return err
+	}
+
+	return s.gateway.Capture(payment.IntentID, payment.Amount)
+}
+
+// refundExpiredPayment gives the money back for tickets that are no longer held
+func (s *paymentService) refundExpiredPayment(payment *entity.Payment) error {
+	claimed, err := s.closePayment(payment, entity.PaymentStatusRefunded)
+	if err != nil || !claimed {
+		return err
+	}
+
+	_, err = s.gateway.Refund(payment.IntentID, payment.Amount)
+	return err
+}
+
+// failPayment gives up on a declined charge and frees its seats
+func (s *paymentService) failPayment(payment *entity.Payment) error {
+	_, err := s.closePayment(payment, entity.PaymentStatusFailed)
+	return err
+}
+
+// closePayment moves a pending payment to a final status and releases any
+// tickets it was still holding
+func (s *paymentService) closePayment(payment *entity.Payment, status entity.PaymentStatus) (bool, error) {
+	claimed := false
+	err := s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		updated, err := repos.PaymentRepository.UpdateStatus(payment.ID, entity.PaymentStatusPending, status)
+		if err != nil || !updated {
+			return err
+		}
+		claimed = true
+
+		tickets, err := paymentTickets(repos, payment)
+		if err != nil {
+			return err
+		}
+
+		for i := range tickets {
+			if _, err := releaseHeldTicket(repos, &tickets[i], entity.TicketStatusCancelled); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	return claimed, err
+}
+
+func (s *paymentService) GetPaymentByID(id uint) (*entity.Payment, error) {
+	return s.paymentRepo.FindByID(id)
+}
+
+// paymentTickets loads the tickets a payment pays for
+func paymentTickets(repos *repository.Repositories, payment *entity.Payment) ([]entity.Ticket, error) {
+	if payment.OrderID != nil {
+		return repos.TicketRepository.FindByOrderID(*payment.OrderID)
+	}
+	if payment.TicketID != nil {
+		ticket, err := repos.TicketRepository.FindByID(*payment.TicketID)
+		if err != nil {
+			return nil, err
+		}
+		return []entity.Ticket{*ticket}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+)
+
+type PromoCodeService interface {
+	GetAllPromoCodes(page, limit int) ([]entity.PromoCode, int64, error)
+	GetPromoCodeByID(id uint) (*entity.PromoCode, error)
+	CreatePromoCode(promoCode *entity.PromoCode) error
+	UpdatePromoCode(id uint, promoCode *entity.PromoCode) error
+	DeletePromoCode(id uint) error
+}
+
+type promoCodeService struct {
+	promoCodeRepo repository.PromoCodeRepository
+	eventRepo     repository.EventRepository
+}
+
+func NewPromoCodeService(promoCodeRepo repository.PromoCodeRepository, eventRepo repository.EventRepository) PromoCodeService {
+	return &promoCodeService{
+		promoCodeRepo: promoCodeRepo,
+		eventRepo:     eventRepo,
+	}
+}
+
+func (s *promoCodeService) GetAllPromoCodes(page, limit int) ([]entity.PromoCode, int64, error) {
+	// Default pagination values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	return s.promoCodeRepo.FindAll(page, limit)
+}
+
+func (s *promoCodeService) GetPromoCodeByID(id uint) (*entity.PromoCode, error) {
+	return s.promoCodeRepo.FindByID(id)
+}
+
+func (s *promoCodeService) CreatePromoCode(promoCode *entity.PromoCode) error {
+	if err := s.validatePromoCode(0, promoCode); err != nil {
+		return err
+	}
+
+	promoCode.ID = 0
+	promoCode.UsedCount = 0
+	return s.promoCodeRepo.Save(promoCode)
+}
+
+func (s *promoCodeService) UpdatePromoCode(id uint, promoCode *entity.PromoCode) error {
+	// Get existing promo code
+	existingPromoCode, err := s.promoCodeRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validatePromoCode(id, promoCode); err != nil {
+		return err
+	}
+
+	// Update promo code fields; the usage count is kept
+	existingPromoCode.Code = promoCode.Code
+	existingPromoCode.Description = promoCode.Description
+	existingPromoCode.DiscountType = promoCode.DiscountType
+	existingPromoCode.DiscountValue = promoCode.DiscountValue
+	existingPromoCode.EventID = promoCode.EventID
+	existingPromoCode.MaxUses = promoCode.MaxUses
+	existingPromoCode.MaxUsesPerUser = promoCode.MaxUsesPerUser
+	existingPromoCode.ValidFrom = promoCode.ValidFrom
+	existingPromoCode.ValidUntil = promoCode.ValidUntil
+
+	return s.promoCodeRepo.Save(existingPromoCode)
+}
+
+func (s *promoCodeService) DeletePromoCode(id uint) error {
+	return s.promoCodeRepo.Delete(id)
+}
+
+// validatePromoCode normalizes the code and checks its fields. promoCodeID is
+// the code being replaced, if any.
+func (s *promoCodeService) validatePromoCode(promoCodeID uint, promoCode *entity.PromoCode) error {
+	promoCode.Code = normalizePromoCode(promoCode.Code)
+	if promoCode.Code == "" {
+		return errors.New("code is required")
+	}
+
+	switch promoCode.DiscountType {
+	case entity.DiscountTypePercentage:
+		if promoCode.DiscountValue <= 0 || promoCode.DiscountValue > 100 {
+			return errors.New("percentage discount must be between 0 and 100")
+		}
+	case entity.DiscountTypeFixed:
+		if promoCode.DiscountValue <= 0 {
+			return errors.New("fixed discount must be positive")
+		}
+	default:
+		return errors.New("discount_type must be percentage or fixed")
+	}
+
+	if promoCode.MaxUses < 0 || promoCode.MaxUsesPerUser < 0 {
+		return errors.New("usage caps cannot be negative")
+	}
+	if promoCode.ValidFrom != nil && promoCode.ValidUntil != nil && promoCode.ValidUntil.Before(*promoCode.ValidFrom) {
+		return errors.New("valid_until must be after valid_from")
+	}
+
+	if promoCode.EventID != nil {
+		if _, err := s.eventRepo.FindByID(*promoCode.EventID); err != nil {
+			return err
+		}
+	}
+
+	if existing, err := s.promoCodeRepo.FindByCode(promoCode.Code); err == nil && existing.ID != promoCodeID {
+		return errors.New("promo code already exists")
+	}
+
+	return nil
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// applyPromoCode redeems the promo code the buyer entered for a new ticket, if
+// any, and returns the discount it gives on the price. It must run inside the
+// transaction that saves the ticket so usage caps hold under concurrent
+// purchases.
+func applyPromoCode(repos *repository.Repositories, ticket *entity.Ticket, price float64, at time.Time) (float64, error) {
+	ticket.PromoCodeID = nil
+
+	if strings.TrimSpace(ticket.PromoCode) == "" {
+		return 0, nil
+	}
+
+	promoCode, err := repos.PromoCodeRepository.FindByCode(normalizePromoCode(ticket.PromoCode))
+	if err != nil {
+		return 0, errors.New("invalid promo code")
+	}
+
+	if promoCode.ValidFrom != nil && at.Before(*promoCode.ValidFrom) {
+		return 0, errors.New("promo code is not valid yet")
+	}
+	if promoCode.ValidUntil != nil && at.After(*promoCode.ValidUntil) {
+		return 0, errors.New("promo code has expired")
+	}
+	if promoCode.EventID != nil && *promoCode.EventID != ticket.EventID {
+		return 0, errors.New("promo code does not apply to this event")
+	}
+
+	if promoCode.MaxUsesPerUser > 0 {
+		uses, err := repos.PromoCodeRepository.CountUsesByUser(promoCode.ID, ticket.UserID)
+		if err != nil {
+			return 0, err
+		}
+		if uses >= int64(promoCode.MaxUsesPerUser) {
+			return 0, errors.New("you have already used this promo code the maximum number of times")
+		}
+	}
+
+	redeemed, err := repos.PromoCodeRepository.Redeem(promoCode.ID)
+	if err != nil {
+		return 0, err
+	}
+	if !redeemed {
+		return 0, errors.New("promo code has reached its usage limit")
+	}
+
+	ticket.PromoCodeID = &promoCode.ID
+	return promoDiscount(promoCode, price), nil
+}
+
+// promoDiscount works out the amount taken off a price, never more than the price itself
+func promoDiscount(promoCode *entity.PromoCode, price float64) float64 {
+	discount := promoCode.DiscountValue
+	if promoCode.DiscountType == entity.DiscountTypePercentage {
+		discount = math.Round(price*promoCode.DiscountValue) / 100
+	}
+	return math.Min(discount, price)
+}
+
+// restorePromoCode gives back the promo code use of a ticket that no longer holds a seat
+func restorePromoCode(repos *repository.Repositories, ticket *entity.Ticket) error {
+	if ticket.PromoCodeID == nil {
+		return nil
+	}
+	return repos.PromoCodeRepository.Restore(*ticket.PromoCodeID)
+}
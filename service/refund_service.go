@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+)
+
+// errTicketAlreadyCancelled is returned when a concurrent request cancelled the ticket first
+var errTicketAlreadyCancelled = errors.New("ticket is already cancelled")
+
+// ErrRefundFailed is returned when the payment gateway did not pay back some
+// refunds. They are kept as failed and sent again on the next attempt.
+var ErrRefundFailed = errors.New("the payment gateway rejected some refunds")
+
+type RefundService interface {
+	GetCancellationPolicy(eventID uint) ([]entity.CancellationPolicyRule, error)
+	SetCancellationPolicy(eventID uint, rules []entity.CancellationPolicyRule) error
+	GetEventRefunds(eventID uint) ([]entity.Refund, error)
+	RefundCancelledEvent(eventID uint) ([]entity.Refund, error)
+	ProcessRefund(refund *entity.Refund) error
+}
+
+type refundService struct {
+	refundRepo  repository.RefundRepository
+	policyRepo  repository.CancellationPolicyRepository
+	paymentRepo repository.PaymentRepository
+	ticketRepo  repository.TicketRepository
+	eventRepo   repository.EventRepository
+	gateway     PaymentGateway
+	transactor  repository.Transactor
+}
+
+func NewRefundService(refundRepo repository.RefundRepository, policyRepo repository.CancellationPolicyRepository, paymentRepo repository.PaymentRepository, ticketRepo repository.TicketRepository, eventRepo repository.EventRepository, gateway PaymentGateway, transactor repository.Transactor) RefundService {
+	return &refundService{
+		refundRepo:  refundRepo,
+		policyRepo:  policyRepo,
+		paymentRepo: paymentRepo,
+		ticketRepo:  ticketRepo,
+		eventRepo:   eventRepo,
+		gateway:     gateway,
+		transactor:  transactor,
+	}
+}
+
+func (s *refundService) GetCancellationPolicy(eventID uint) ([]entity.CancellationPolicyRule, error) {
+	// Check if event exists
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, err
+	}
+
+	return s.policyRepo.FindByEventID(eventID)
+}
+
+// SetCancellationPolicy replaces the refund rules of an event. An empty policy
+// means tickets are refunded in full.
+func (s *refundService) SetCancellationPolicy(eventID uint, rules []entity.CancellationPolicyRule) error {
+	// Check if event exists
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool)
+	for i := range rules {
+		rule := &rules[i]
+		if rule.HoursBeforeStart < 0 {
+			return errors.New("hours_before_start cannot be negative")
+		}
+		if rule.RefundPercentage < 0 || rule.RefundPercentage > 100 {
+			return errors.New("refund_percentage must be between 0 and 100")
+		}
+		if seen[rule.HoursBeforeStart] {
+			return errors.New("each hours_before_start may only appear once")
+		}
+		seen[rule.HoursBeforeStart] = true
+
+		rule.ID = 0
+		rule.EventID = eventID
+	}
+
+	return s.policyRepo.ReplaceForEvent(eventID, rules)
+}
+
+func (s *refundService) GetEventRefunds(eventID uint) ([]entity.Refund, error) {
+	// Check if event exists
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, err
+	}
+
+	return s.refundRepo.FindByEventID(eventID)
+}
+
+// RefundCancelledEvent closes the waitlist of a called off event, cancels
+// every remaining ticket and refunds paid tickets in full. Refunds the gateway rejected or never got
+// are sent again, so running it again picks up whatever was missed before.
+// The refunds sent are returned even when some of them failed.
+func (s *refundService) RefundCancelledEvent(eventID uint) ([]entity.Refund, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Status != entity.EventStatusCancelled {
+		return nil, errors.New("refunds can only be issued for cancelled events")
+	}
+
+	// Nobody is offered a seat of a called off event any more
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		return closeWaitlist(repos, eventID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.ticketRepo.FindByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tickets {
+		ticket := &tickets[i]
+
+		switch ticket.Status {
+		case entity.TicketStatusReserved:
+			// Payments still in flight are refunded once they find the hold gone
+			err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+				_, err := releaseHeldTicket(repos, ticket, entity.TicketStatusCancelled)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+
+		case entity.TicketStatusPurchased:
+			err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+				if _, err := cancelPaidTicket(repos, ticket, 100, "event cancelled"); err != nil {
+					return err
+				}
+				return releaseSeats(repos, ticket.EventID, ticket.TierID, 1)
+			})
+			if err != nil && !errors.Is(err, errTicketAlreadyCancelled) {
+				return nil, err
+			}
+		}
+	}
+
+	// Send the new refunds to the gateway along with those that failed or did
+	// not get there on an earlier run
+	recorded, err := s.refundRepo.FindByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds := make([]entity.Refund, 0)
+	var failures []error
+	for i := range recorded {
+		refund := &recorded[i]
+		if refund.Status == entity.RefundStatusSucceeded {
+			continue
+		}
+
+		if err := s.ProcessRefund(refund); err != nil {
+			failures = append(failures, fmt.Errorf("refund %d of ticket %d: %w", refund.ID, refund.TicketID, err))
+		}
+		refunds = append(refunds, *refund)
+	}
+
+	if len(failures) > 0 {
+		return refunds, fmt.Errorf("%w: %w", ErrRefundFailed, errors.Join(failures...))
+	}
+
+	return refunds, nil
+}
+
+// ProcessRefund sends a recorded refund to the payment gateway and stores the
+// outcome. A refund that failed before may be sent again.
+func (s *refundService) ProcessRefund(refund *entity.Refund) error {
+	if refund.Status == entity.RefundStatusSucceeded {
+		return errors.New("refund has already been processed")
+	}
+
+	if refund.Amount > 0 {
+		payment, err := s.paymentRepo.FindByID(refund.PaymentID)
+		if err != nil {
+			return err
+		}
+
+		providerRefundID, err := s.gateway.Refund(payment.IntentID, refund.Amount)
+		if err != nil {
+			refund.Status = entity.RefundStatusFailed
+			s.refundRepo.Save(refund)
+			return err
+		}
+		refund.ProviderRefundID = providerRefundID
+	}
+
+	refund.Status = entity.RefundStatusSucceeded
+	return s.refundRepo.Save(refund)
+}
+
+// refundPercentage picks the share of the ticket price that is refunded when
+// cancelling at the given time. Rules must be ordered by HoursBeforeStart,
+// latest deadline first, and events without a policy refund in full.
+func refundPercentage(rules []entity.CancellationPolicyRule, startDate time.Time, at time.Time) float64 {
+	if len(rules) == 0 {
+		return 100
+	}
+
+	hoursLeft := startDate.Sub(at).Hours()
+	for _, rule := range rules {
+		if hoursLeft >= float64(rule.HoursBeforeStart) {
+			return rule.RefundPercentage
+		}
+	}
+
+	return 0
+}
+
+// cancelPaidTicket cancels a purchased ticket inside a transaction and records
+// the refund due for it. The refund is nil when the ticket was not paid through
+// the payment gateway. Callers decide what happens to the seat.
+func cancelPaidTicket(repos *repository.Repositories, ticket *entity.Ticket, percentage float64, reason string) (*entity.Refund, error) {
+	updated, err := repos.TicketRepository.UpdateStatus(ticket.ID, entity.TicketStatusPurchased, entity.TicketStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errTicketAlreadyCancelled
+	}
+
+	if err := releaseTicketSeat(repos, ticket); err != nil {
+		return nil, err
+	}
+
+	if err := restorePromoCode(repos, ticket); err != nil {
+		return nil, err
+	}
+
+	payment, err := repos.PaymentRepository.FindSucceededForTicket(ticket)
+	if err != nil || payment == nil {
+		return nil, err
+	}
+
+	refund := &entity.Refund{
+		PaymentID:    payment.ID,
+		TicketID:     ticket.ID,
+		EventID:      ticket.EventID,
+		UserID:       ticket.UserID,
+		TicketAmount: ticket.PricePaid,
+		Percentage:   percentage,
+		Amount:       math.Round(ticket.PricePaid*percentage) / 100,
+		Reason:       reason,
+		Status:       entity.RefundStatusPending,
+	}
+	if err := repos.RefundRepository.Save(refund); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/entity"
 	"github.com/taufikmulyawan/ticketing-system/reports"
 	"github.com/taufikmulyawan/ticketing-system/repository"
 	"github.com/taufikmulyawan/ticketing-system/types"
@@ -8,11 +11,14 @@ import (
 
 // Type aliases for backward compatibility
 type EventSalesSummary = types.EventSalesSummary
+type TierSalesSummary = types.TierSalesSummary
 type SalesSummary = types.SalesSummary
+type CategorySalesSummary = types.CategorySalesSummary
 
 type ReportService interface {
 	GetSalesSummary() (*SalesSummary, error)
 	GetEventSalesSummary(eventID uint) (*EventSalesSummary, error)
+	GetCategorySalesSummary() ([]CategorySalesSummary, error)
 	ExportSalesSummaryPDF() ([]byte, error) 
 	ExportEventSalesPDF(eventID uint) ([]byte, error)
 	ExportSalesSummaryCSV() ([]byte, error)
@@ -20,20 +26,26 @@ type ReportService interface {
 }
 
 type reportService struct {
-	ticketRepo repository.TicketRepository
-	eventRepo  repository.EventRepository
+	ticketRepo   repository.TicketRepository
+	eventRepo    repository.EventRepository
+	tierRepo     repository.TicketTierRepository
+	refundRepo   repository.RefundRepository
+	categoryRepo repository.CategoryRepository
 }
 
-func NewReportService(ticketRepo repository.TicketRepository, eventRepo repository.EventRepository) ReportService {
+func NewReportService(ticketRepo repository.TicketRepository, eventRepo repository.EventRepository, tierRepo repository.TicketTierRepository, refundRepo repository.RefundRepository, categoryRepo repository.CategoryRepository) ReportService {
 	return &reportService{
-		ticketRepo: ticketRepo,
-		eventRepo:  eventRepo,
+		ticketRepo:   ticketRepo,
+		eventRepo:    eventRepo,
+		tierRepo:     tierRepo,
+		refundRepo:   refundRepo,
+		categoryRepo: categoryRepo,
 	}
 }
 
 func (s *reportService) GetSalesSummary() (*SalesSummary, error) {
 	// Get all events for calculating summary
-	events, _, err := s.eventRepo.FindAll(1, 1000) // Using large limit to get all events
+	events, _, err := s.eventRepo.FindAll(1, 1000, repository.EventFilter{}) // Using large limit to get all events
 	if err != nil {
 		return nil, err
 	}
@@ -46,24 +58,18 @@ func (s *reportService) GetSalesSummary() (*SalesSummary, error) {
 	}
 
 	// Calculate summary for each event
-	for _, event := range events {
-		soldTickets, err := s.ticketRepo.CountSoldTicketsByEventID(event.ID)
+	for i := range events {
+		eventSummary, err := s.summarizeEvent(&events[i])
 		if err != nil {
 			return nil, err
 		}
 
-		revenue := float64(soldTickets) * event.Price
-		summary.TotalTickets += soldTickets
-		summary.TotalRevenue += revenue
-
-		eventSummary := EventSalesSummary{
-			EventID:      event.ID,
-			EventName:    event.Name,
-			TotalTickets: soldTickets,
-			TotalRevenue: revenue,
-		}
-
-		summary.EventSummary = append(summary.EventSummary, eventSummary)
+		summary.TotalTickets += eventSummary.TotalTickets
+		summary.TotalDiscounts += eventSummary.TotalDiscounts
+		summary.TotalFees += eventSummary.TotalFees
+		summary.TotalRefunds += eventSummary.TotalRefunds
+		summary.TotalRevenue += eventSummary.TotalRevenue
+		summary.EventSummary = append(summary.EventSummary, *eventSummary)
 	}
 
 	return summary, nil
@@ -76,20 +82,124 @@ func (s *reportService) GetEventSalesSummary(eventID uint) (*EventSalesSummary,
 		return nil, err
 	}
 
-	// Count sold tickets for this event
-	soldTickets, err := s.ticketRepo.CountSoldTicketsByEventID(event.ID)
+	return s.summarizeEvent(event)
+}
+
+// GetCategorySalesSummary totals sales per category, in category order, with
+// events that are in no category at the end
+func (s *reportService) GetCategorySalesSummary() ([]CategorySalesSummary, error) {
+	events, _, err := s.eventRepo.FindAll(1, 1000, repository.EventFilter{}) // Using large limit to get all events
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.FindAll(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]CategorySalesSummary, 0, len(categories)+1)
+	byCategory := make(map[uint]int, len(categories))
+	for _, category := range categories {
+		byCategory[category.ID] = len(summaries)
+		summaries = append(summaries, CategorySalesSummary{
+			CategoryID:   category.ID,
+			CategoryName: category.Name,
+		})
+	}
+	uncategorized := CategorySalesSummary{CategoryName: "Uncategorized"}
+
+	for i := range events {
+		eventSummary, err := s.summarizeEvent(&events[i])
+		if err != nil {
+			return nil, err
+		}
+
+		totals := make([]*CategorySalesSummary, 0, len(events[i].Categories))
+		for _, category := range events[i].Categories {
+			if index, ok := byCategory[category.ID]; ok {
+				totals = append(totals, &summaries[index])
+			}
+		}
+		if len(totals) == 0 {
+			totals = append(totals, &uncategorized)
+		}
+
+		for _, total := range totals {
+			total.TotalEvents++
+			total.TotalTickets += eventSummary.TotalTickets
+			total.TotalDiscounts += eventSummary.TotalDiscounts
+			total.TotalFees += eventSummary.TotalFees
+			total.TotalRefunds += eventSummary.TotalRefunds
+			total.TotalRevenue += eventSummary.TotalRevenue
+		}
+	}
+
+	if uncategorized.TotalEvents > 0 {
+		summaries = append(summaries, uncategorized)
+	}
+
+	return summaries, nil
+}
+
+// summarizeEvent breaks an event's sales down by tier. Tickets are valued at
+// the price recorded when they were bought, so editing the event or tier price
+// does not change past sales, and refunds are netted out of revenue.
+func (s *reportService) summarizeEvent(event *entity.Event) (*EventSalesSummary, error) {
+	sales, err := s.ticketRepo.SumSalesByEventID(event.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tiers, err := s.tierRepo.FindByEventID(event.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	revenue := float64(soldTickets) * event.Price
 	eventSummary := &EventSalesSummary{
-		EventID:      event.ID,
-		EventName:    event.Name,
-		TotalTickets: soldTickets,
-		TotalRevenue: revenue,
+		EventID:        event.ID,
+		EventName:      event.Name,
+		TotalTickets:   sales.Tickets,
+		TotalDiscounts: sales.Discounts,
+		TotalFees:      sales.Fees,
+		TotalRevenue:   sales.Revenue,
+	}
+
+	for _, tier := range tiers {
+		tierSales, err := s.ticketRepo.SumSalesByTierID(tier.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Netted the same way as the event so the tiers add up to it
+		refundedTickets, refunded, err := s.refundRepo.SumByTierID(tier.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		eventSummary.TierSummary = append(eventSummary.TierSummary, TierSalesSummary{
+			TierID:         tier.ID,
+			TierName:       tier.Name,
+			Price:          tier.Price,
+			TotalTickets:   tierSales.Tickets,
+			TotalDiscounts: tierSales.Discounts,
+			TotalFees:      tierSales.Fees,
+			TotalRefunds:   refunded,
+			TotalRevenue:   tierSales.Revenue + refundedTickets - refunded,
+		})
+	}
+
+	// Cancelled tickets still count towards gross sales; only the money given
+	// back is deducted
+	refundedTickets, refunded, err := s.refundRepo.SumByEventID(event.ID)
+	if err != nil {
+		return nil, err
 	}
 
+	eventSummary.GrossRevenue = eventSummary.TotalRevenue + refundedTickets
+	eventSummary.TotalRefunds = refunded
+	eventSummary.TotalRevenue = eventSummary.GrossRevenue - refunded
+
 	return eventSummary, nil
 }
 
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/dto"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+)
+
+// maxSeatsPerRow keeps a mistyped seat_count from creating a huge map
+const maxSeatsPerRow = 500
+
+type SeatMapService interface {
+	GetAllSeatMaps(page, limit int, venueID *uint) ([]entity.SeatMap, int64, error)
+	GetSeatMapByID(id uint) (*entity.SeatMap, error)
+	CreateSeatMap(request dto.SeatMapCreateRequest) (*entity.SeatMap, error)
+	DeleteSeatMap(id uint) error
+	GetEventSeats(eventID uint, includeUnpublished bool) ([]entity.Seat, error)
+}
+
+type seatMapService struct {
+	seatMapRepo repository.SeatMapRepository
+	venueRepo   repository.VenueRepository
+	eventRepo   repository.EventRepository
+}
+
+func NewSeatMapService(seatMapRepo repository.SeatMapRepository, venueRepo repository.VenueRepository, eventRepo repository.EventRepository) SeatMapService {
+	return &seatMapService{
+		seatMapRepo: seatMapRepo,
+		venueRepo:   venueRepo,
+		eventRepo:   eventRepo,
+	}
+}
+
+func (s *seatMapService) GetAllSeatMaps(page, limit int, venueID *uint) ([]entity.SeatMap, int64, error) {
+	// Default pagination values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	return s.seatMapRepo.FindAll(page, limit, venueID)
+}
+
+// GetSeatMapByID returns a seat map with its seats
+func (s *seatMapService) GetSeatMapByID(id uint) (*entity.SeatMap, error) {
+	seatMap, err := s.seatMapRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	seatMap.Seats, err = s.seatMapRepo.FindSeats(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return seatMap, nil
+}
+
+// CreateSeatMap lays out the sections, rows and seats of a new seat map. Each
+// seat is stored with the category and price it is sold at.
+func (s *seatMapService) CreateSeatMap(request dto.SeatMapCreateRequest) (*entity.SeatMap, error) {
+	seatMap := &entity.SeatMap{
+		Name:    strings.TrimSpace(request.Name),
+		VenueID: request.VenueID,
+	}
+	if seatMap.Name == "" {
+		return nil, errors.New("seat map name is required")
+	}
+
+	if seatMap.VenueID != nil {
+		if _, err := s.venueRepo.FindByID(*seatMap.VenueID); err != nil {
+			return nil, err
+		}
+	}
+
+	positions := make(map[string]bool)
+	for _, section := range request.Sections {
+		sectionName := strings.TrimSpace(section.Name)
+		if sectionName == "" {
+			return nil, errors.New("section name is required")
+		}
+		if section.Price < 0 {
+			return nil, fmt.Errorf("price of section %s cannot be negative", sectionName)
+		}
+
+		for _, row := range section.Rows {
+			rowLabel := strings.TrimSpace(row.Label)
+			if rowLabel == "" {
+				return nil, fmt.Errorf("every row of section %s needs a label", sectionName)
+			}
+
+			seats := row.Seats
+			if len(seats) == 0 {
+				if row.SeatCount <= 0 || row.SeatCount > maxSeatsPerRow {
+					return nil, fmt.Errorf("row %s of section %s needs between 1 and %d seats", rowLabel, sectionName, maxSeatsPerRow)
+				}
+				for number := 1; number <= row.SeatCount; number++ {
+					seats = append(seats, dto.SeatRequest{Number: strconv.Itoa(number)})
+				}
+			}
+
+			for _, seatRequest := range seats {
+				seat := entity.Seat{
+					Section:  sectionName,
+					Row:      rowLabel,
+					Number:   strings.TrimSpace(seatRequest.Number),
+					Category: section.Category,
+					Price:    section.Price,
+				}
+				if seat.Number == "" {
+					return nil, fmt.Errorf("every seat of row %s in section %s needs a number", rowLabel, sectionName)
+				}
+				if seatRequest.Category != "" {
+					seat.Category = seatRequest.Category
+				}
+				if seatRequest.Price != nil {
+					if *seatRequest.Price < 0 {
+						return nil, errors.New("seat price cannot be negative")
+					}
+					seat.Price = *seatRequest.Price
+				}
+
+				position := seat.Section + "/" + seat.Row + "/" + seat.Number
+				if positions[position] {
+					return nil, fmt.Errorf("seat %s appears more than once", position)
+				}
+				positions[position] = true
+
+				seatMap.Seats = append(seatMap.Seats, seat)
+			}
+		}
+	}
+
+	if len(seatMap.Seats) == 0 {
+		return nil, errors.New("seat map must have at least one seat")
+	}
+
+	if err := s.seatMapRepo.Create(seatMap); err != nil {
+		return nil, err
+	}
+
+	return seatMap, nil
+}
+
+func (s *seatMapService) DeleteSeatMap(id uint) error {
+	return s.seatMapRepo.Delete(id)
+}
+
+// GetEventSeats returns the seats of an event with reserved seating, marking
+// those that can still be bought. No seat is available once the event has
+// sold its capacity. Seats of drafts and events that are not public yet are
+// only listed when includeUnpublished is set.
+func (s *seatMapService) GetEventSeats(eventID uint, includeUnpublished bool) ([]entity.Seat, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !includeUnpublished && !IsEventPublished(event, time.Now()) {
+		return nil, errors.New("event not found")
+	}
+
+	if event.SeatMapID == nil {
+		return nil, errors.New("event does not have reserved seating")
+	}
+
+	seats, err := s.seatMapRepo.FindSeats(*event.SeatMapID)
+	if err != nil {
+		return nil, err
+	}
+
+	takenIDs, err := s.seatMapRepo.FindTakenSeatIDs(eventID)
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[uint]bool, len(takenIDs))
+	for _, id := range takenIDs {
+		taken[id] = true
+	}
+
+	onSale := event.SoldCount < event.Capacity
+	for i := range seats {
+		available := onSale && !taken[seats[i].ID]
+		seats[i].Available = &available
+	}
+
+	return seats, nil
+}
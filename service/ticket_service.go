@@ -2,28 +2,77 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
+	"github.com/taufikmulyawan/ticketing-system/config"
 	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/reports"
 	"github.com/taufikmulyawan/ticketing-system/repository"
 )
 
+var (
+	// ErrTicketAlreadyCheckedIn is returned when a ticket that was already used is scanned again
+	ErrTicketAlreadyCheckedIn = errors.New("ticket has already been checked in")
+
+	// ErrOrderLimitExceeded is returned when one purchase asks for more seats
+	// of an event than the event allows per order
+	ErrOrderLimitExceeded = errors.New("too many tickets in one purchase")
+
+	// ErrUserLimitReached is returned when a purchase would leave the buyer
+	// holding more seats of an event than the event allows per user
+	ErrUserLimitReached = errors.New("ticket limit per user reached")
+
+	// ErrPurchaseCooldown is returned when the buyer purchases again before the
+	// event's cooldown has passed
+	ErrPurchaseCooldown = errors.New("purchasing too soon after your last purchase")
+
+	// ErrSeatTaken is returned when the seat a buyer picked is held or sold to someone else
+	ErrSeatTaken = errors.New("seat is already taken")
+)
+
 type TicketService interface {
 	GetAllTickets(page, limit int, userID uint) ([]entity.Ticket, int64, error)
 	GetTicketByID(id uint) (*entity.Ticket, error)
-	PurchaseTicket(ticket *entity.Ticket) error
-	CancelTicket(id uint, userID uint) error
+	PurchaseTicket(ticket *entity.Ticket) (*entity.Payment, error)
+	CancelTicket(id uint, userID uint) (*entity.Refund, error)
+	HoldTicket(ticket *entity.Ticket) error
+	ConfirmHold(id uint, userID uint) (*entity.Payment, error)
+	ReleaseHold(id uint, userID uint) error
+	ReleaseExpiredHolds() error
+	GetTicketQRCode(id uint) ([]byte, error)
+	GetTicketPDF(id uint) ([]byte, error)
+	CheckIn(token string, staffID uint) (*entity.Ticket, error)
+	StartTransfer(id uint, userID uint, recipientEmail string) (*entity.TicketTransfer, error)
+	AcceptTransfer(transferID uint, userID uint) (*entity.TicketTransfer, error)
+	CancelTransfer(transferID uint, userID uint) (*entity.TicketTransfer, error)
+	GetMyTransfers(userID uint) ([]entity.TicketTransfer, error)
 }
 
 type ticketService struct {
-	ticketRepo repository.TicketRepository
-	eventRepo  repository.EventRepository
+	ticketRepo     repository.TicketRepository
+	eventRepo      repository.EventRepository
+	tierRepo       repository.TicketTierRepository
+	seatMapRepo    repository.SeatMapRepository
+	userRepo       repository.UserRepository
+	transferRepo   repository.TransferRepository
+	paymentService PaymentService
+	refundService  RefundService
+	transactor     repository.Transactor
 }
 
-func NewTicketService(ticketRepo repository.TicketRepository, eventRepo repository.EventRepository) TicketService {
+func NewTicketService(ticketRepo repository.TicketRepository, eventRepo repository.EventRepository, tierRepo repository.TicketTierRepository, seatMapRepo repository.SeatMapRepository, userRepo repository.UserRepository, transferRepo repository.TransferRepository, paymentService PaymentService, refundService RefundService, transactor repository.Transactor) TicketService {
 	return &ticketService{
-		ticketRepo: ticketRepo,
-		eventRepo:  eventRepo,
+		ticketRepo:     ticketRepo,
+		eventRepo:      eventRepo,
+		tierRepo:       tierRepo,
+		seatMapRepo:    seatMapRepo,
+		userRepo:       userRepo,
+		transferRepo:   transferRepo,
+		paymentService: paymentService,
+		refundService:  refundService,
+		transactor:     transactor,
 	}
 }
 
@@ -43,64 +92,748 @@ func (s *ticketService) GetTicketByID(id uint) (*entity.Ticket, error) {
 	return s.ticketRepo.FindByID(id)
 }
 
-func (s *ticketService) PurchaseTicket(ticket *entity.Ticket) error {
+// PurchaseTicket holds a seat and starts the payment for it. The ticket only
+// becomes purchased once the gateway reports a successful payment.
+func (s *ticketService) PurchaseTicket(ticket *entity.Ticket) (*entity.Payment, error) {
+	price, err := s.issueTicket(ticket, entity.TicketStatusReserved)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.startPayment(ticket, price)
+}
+
+// HoldTicket reserves a seat for the buyer while they complete checkout.
+// The hold counts against capacity until it is confirmed, released or expires.
+func (s *ticketService) HoldTicket(ticket *entity.Ticket) error {
+	_, err := s.issueTicket(ticket, entity.TicketStatusReserved)
+	return err
+}
+
+// issueTicket validates the purchase, creates the ticket in the given status
+// and returns the amount the buyer is charged for it
+func (s *ticketService) issueTicket(ticket *entity.Ticket, status entity.TicketStatus) (float64, error) {
 	// Check if event exists
 	event, err := s.eventRepo.FindByID(ticket.EventID)
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	if err := checkEventPurchasable(event); err != nil {
+		return 0, err
+	}
+
+	seat, tier, err := resolveSeatOrTier(s.seatMapRepo, s.tierRepo, event, ticket.SeatID, ticket.TierID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Always create a fresh ticket outside of any order
+	ticket.ID = 0
+	ticket.OrderID = nil
+	ticket.Tier = nil
+	ticket.Seat = nil
+	if tier == nil {
+		ticket.TierID = nil
+	}
+	if seat == nil {
+		ticket.SeatID = nil
+	}
+
+	// Set ticket details
+	price := ticketPrice(event, tier, seat)
+	ticket.Status = status
+	ticket.PurchasedAt = time.Now()
+	ticket.ExpiresAt = nil
+	if status == entity.TicketStatusReserved {
+		expiresAt := ticket.PurchasedAt.Add(config.AppConfig.TicketHoldDuration)
+		ticket.ExpiresAt = &expiresAt
 	}
 
-	// Check if event is active
-	if event.Status != entity.EventStatusActive {
+	// Take the seat, check the buyer's limits, redeem the promo code and save
+	// the ticket in one transaction so concurrent buyers cannot both take the
+	// last seat, the same reserved seat or the last use of a code
+	if err := s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := reserveSeats(repos, event.ID, tier, 1); err != nil {
+			return err
+		}
+		if err := checkPurchaseLimits(repos, event, ticket.UserID, 1, ticket.PurchasedAt); err != nil {
+			return err
+		}
+		discount, err := applyPromoCode(repos, ticket, price, ticket.PurchasedAt)
+		if err != nil {
+			return err
+		}
+		priceTicket(ticket, price, discount)
+		if err := repos.TicketRepository.Save(ticket); err != nil {
+			return err
+		}
+		return takeSeat(repos, ticket)
+	}); err != nil {
+		return 0, err
+	}
+
+	return ticket.PricePaid, nil
+}
+
+// startPayment opens the payment for a held ticket, giving the seat back if
+// the gateway cannot be reached
+func (s *ticketService) startPayment(ticket *entity.Ticket, price float64) (*entity.Payment, error) {
+	payment, err := s.paymentService.StartTicketPayment(ticket, price)
+	if err != nil {
+		s.releaseHold(ticket, entity.TicketStatusCancelled)
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// checkEventPurchasable verifies that tickets can currently be bought for the event
+func checkEventPurchasable(event *entity.Event) error {
+	// Check if event is active, postponed events keep selling for their new dates
+	if !slices.Contains(entity.OpenEventStatuses, event.Status) {
 		return errors.New("tickets can only be purchased for active events")
 	}
 
+	now := time.Now()
+	if !IsEventPublished(event, now) {
+		return errors.New("event is not published")
+	}
+
+	// Tickets only sell inside the event's sales window
+	if event.SalesStart != nil && now.Before(*event.SalesStart) {
+		return errors.New("ticket sales for this event have not started")
+	}
+	if event.SalesEnd != nil && !now.Before(*event.SalesEnd) {
+		return errors.New("ticket sales for this event have ended")
+	}
+
 	// Check if event date is in the future
-	if event.StartDate.Before(time.Now()) {
+	if event.StartDate.Before(now) {
 		return errors.New("cannot purchase tickets for past events")
 	}
 
-	// Check if event has available capacity
-	soldTickets, err := s.ticketRepo.CountSoldTicketsByEventID(event.ID)
+	return nil
+}
+
+// checkPurchaseLimits enforces the event's per-buyer rules for a purchase of
+// quantity seats. It must run inside the purchase transaction after the seats
+// were reserved: the write lock on the event row then keeps a buyer's
+// concurrent purchases from both passing the check.
+func checkPurchaseLimits(repos *repository.Repositories, event *entity.Event, userID uint, quantity int, at time.Time) error {
+	if event.MaxTicketsPerOrder > 0 && quantity > event.MaxTicketsPerOrder {
+		return fmt.Errorf("%w: at most %d tickets per purchase for this event", ErrOrderLimitExceeded, event.MaxTicketsPerOrder)
+	}
+
+	if event.PurchaseCooldownSeconds > 0 {
+		last, err := repos.TicketRepository.FindLastPurchaseTime(event.ID, userID)
+		if err != nil {
+			return err
+		}
+		if last != nil {
+			wait := last.Add(time.Duration(event.PurchaseCooldownSeconds) * time.Second).Sub(at)
+			if wait > 0 {
+				return fmt.Errorf("%w: try again in %s", ErrPurchaseCooldown, (wait + time.Second - 1).Truncate(time.Second))
+			}
+		}
+	}
+
+	if event.MaxTicketsPerUser > 0 {
+		held, err := repos.TicketRepository.CountUserTicketsByEventID(event.ID, userID)
+		if err != nil {
+			return err
+		}
+		if held+int64(quantity) > int64(event.MaxTicketsPerUser) {
+			return fmt.Errorf("%w: at most %d tickets per user for this event, you already have %d", ErrUserLimitReached, event.MaxTicketsPerUser, held)
+		}
+	}
+
+	return nil
+}
+
+// resolveTier loads the tier a buyer asked for and checks that it is on sale.
+// Events without tiers are sold at the event price and resolve to a nil tier.
+func resolveTier(tierRepo repository.TicketTierRepository, event *entity.Event, tierID *uint) (*entity.TicketTier, error) {
+	if tierID == nil || *tierID == 0 {
+		count, err := tierRepo.CountByEventID(event.ID)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, errors.New("tier_id is required for events with ticket tiers")
+		}
+		return nil, nil
+	}
+
+	tier, err := tierRepo.FindByID(*tierID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tier.EventID != event.ID {
+		return nil, errors.New("ticket tier does not belong to this event")
+	}
+
+	if err := checkTierOnSale(tier, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return tier, nil
+}
+
+// checkTierOnSale checks that the tier's sales window is open at the given time
+func checkTierOnSale(tier *entity.TicketTier, at time.Time) error {
+	if tier.SalesStart != nil && at.Before(*tier.SalesStart) {
+		return errors.New("ticket tier sales have not started")
+	}
+	if tier.SalesEnd != nil && at.After(*tier.SalesEnd) {
+		return errors.New("ticket tier sales have ended")
+	}
+	return nil
+}
+
+// resolveSeatOrTier loads what a buyer picked for an event: a seat at events
+// with reserved seating, or a tier at events sold in tiers. Both are nil for
+// general admission at the event price.
+func resolveSeatOrTier(seatMapRepo repository.SeatMapRepository, tierRepo repository.TicketTierRepository, event *entity.Event, seatID *uint, tierID *uint) (*entity.Seat, *entity.TicketTier, error) {
+	if event.SeatMapID == nil {
+		if seatID != nil && *seatID != 0 {
+			return nil, nil, errors.New("event does not have reserved seating")
+		}
+		tier, err := resolveTier(tierRepo, event, tierID)
+		return nil, tier, err
+	}
+
+	if tierID != nil && *tierID != 0 {
+		return nil, nil, errors.New("events with reserved seating are priced by seat, not by tier")
+	}
+	if seatID == nil || *seatID == 0 {
+		return nil, nil, errors.New("seat_id is required for events with reserved seating")
+	}
+
+	seat, err := seatMapRepo.FindSeatByID(*seatID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if seat.SeatMapID != *event.SeatMapID {
+		return nil, nil, errors.New("seat is not part of this event's seat map")
+	}
+
+	return seat, nil, nil
+}
+
+// takeSeat claims the reserved seat of a saved ticket, if it has one. It must
+// run inside the transaction that saves the ticket.
+func takeSeat(repos *repository.Repositories, ticket *entity.Ticket) error {
+	if ticket.SeatID == nil {
+		return nil
+	}
+
+	reserved, err := repos.SeatMapRepository.ReserveSeat(ticket.EventID, *ticket.SeatID, ticket.ID)
 	if err != nil {
 		return err
 	}
+	if !reserved {
+		return ErrSeatTaken
+	}
+	return nil
+}
 
-	if int(soldTickets) >= event.Capacity {
-		return errors.New("event is sold out")
+// ticketPrice is the list price of a ticket for the event and optional tier or seat
+func ticketPrice(event *entity.Event, tier *entity.TicketTier, seat *entity.Seat) float64 {
+	if seat != nil {
+		return seat.Price
 	}
+	if tier != nil {
+		return tier.Price
+	}
+	return event.Price
+}
 
-	// Set ticket details
-	ticket.Status = entity.TicketStatusPurchased
-	ticket.PurchasedAt = time.Now()
+// priceTicket records what a ticket is sold for at the moment of purchase so
+// later price changes do not revalue past sales
+func priceTicket(ticket *entity.Ticket, unitPrice float64, discount float64) {
+	ticket.UnitPrice = unitPrice
+	ticket.Discount = discount
+	ticket.Fee = config.AppConfig.TicketServiceFee
+	ticket.PricePaid = unitPrice - discount + ticket.Fee
+	ticket.Currency = config.AppConfig.Currency
+}
 
-	// Save the ticket
-	return s.ticketRepo.Save(ticket)
+// reserveSeats takes seats from the event and, when given, from its tier.
+// It must run inside a transaction so both counters move together.
+func reserveSeats(repos *repository.Repositories, eventID uint, tier *entity.TicketTier, quantity int) error {
+	if err := repos.EventRepository.ReserveSeats(eventID, quantity); err != nil {
+		return err
+	}
+	if tier != nil {
+		return repos.TierRepository.ReserveSeats(tier.ID, quantity)
+	}
+	return nil
+}
+
+// releaseSeats gives seats back to the event and, when given, to its tier
+func releaseSeats(repos *repository.Repositories, eventID uint, tierID *uint, quantity int) error {
+	if err := repos.EventRepository.ReleaseSeats(eventID, quantity); err != nil {
+		return err
+	}
+	if tierID != nil {
+		return repos.TierRepository.ReleaseSeats(*tierID, quantity)
+	}
+	return nil
 }
 
-func (s *ticketService) CancelTicket(id uint, userID uint) error {
+// CancelTicket cancels a purchased ticket and refunds it according to the
+// event's cancellation policy. Tickets bought before their event was postponed
+// may opt out with a full refund instead, tickets bought for the new dates
+// follow the policy.
+func (s *ticketService) CancelTicket(id uint, userID uint) (*entity.Refund, error) {
 	// Get the ticket
 	ticket, err := s.ticketRepo.FindByID(id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Check if the ticket belongs to the user (unless admin)
 	if ticket.UserID != userID {
-		return errors.New("unauthorized to cancel this ticket")
+		return nil, errors.New("unauthorized to cancel this ticket")
 	}
 
 	// Check if the ticket is already cancelled
 	if ticket.Status == entity.TicketStatusCancelled {
-		return errors.New("ticket is already cancelled")
+		return nil, errTicketAlreadyCancelled
+	}
+
+	// Holds are abandoned through ReleaseHold instead
+	if ticket.Status != entity.TicketStatusPurchased {
+		return nil, errors.New("only purchased tickets can be cancelled")
+	}
+
+	// Used tickets cannot be returned
+	if ticket.CheckedInAt != nil {
+		return nil, errors.New("cannot cancel a ticket that has been checked in")
 	}
 
 	// Check if the event has already started
-	if ticket.Event.StartDate.Before(time.Now()) {
-		return errors.New("cannot cancel tickets for events that have already started")
+	now := time.Now()
+	if ticket.Event.StartDate.Before(now) {
+		return nil, errors.New("cannot cancel tickets for events that have already started")
+	}
+
+	percentage := 100.0
+	reason := "opted out of postponed event"
+	postponedAfterPurchase := ticket.Event.Status == entity.EventStatusPostponed &&
+		ticket.Event.PostponedAt != nil && ticket.PurchasedAt.Before(*ticket.Event.PostponedAt)
+	if !postponedAfterPurchase {
+		rules, err := s.refundService.GetCancellationPolicy(ticket.EventID)
+		if err != nil {
+			return nil, err
+		}
+		percentage = refundPercentage(rules, ticket.Event.StartDate, now)
+		reason = "cancelled by ticket holder"
+	}
+
+	// Update the ticket status, record the refund and offer the seat to the
+	// waitlist or give it back to the event
+	var refund *entity.Refund
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		var err error
+		refund, err = cancelPaidTicket(repos, ticket, percentage, reason)
+		if err != nil {
+			return err
+		}
+		return passOnSeat(repos, ticket.EventID, ticket.TierID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The ticket stays cancelled even if the gateway rejects the refund; the
+	// failed refund is kept on record so it can be followed up
+	if refund != nil {
+		s.refundService.ProcessRefund(refund)
+	}
+
+	return refund, nil
+}
+
+// ConfirmHold starts the payment for a ticket the user is holding. The hold is
+// turned into a purchase when the payment succeeds before it expires.
+func (s *ticketService) ConfirmHold(id uint, userID uint) (*entity.Payment, error) {
+	ticket, err := s.findUserHold(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ticket.ExpiresAt != nil && !ticket.ExpiresAt.After(time.Now()) {
+		return nil, errHoldExpired
+	}
+
+	payment, err := s.paymentService.StartTicketPayment(ticket, ticket.PricePaid)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// ReleaseHold abandons a hold and gives its seat back straight away
+func (s *ticketService) ReleaseHold(id uint, userID uint) error {
+	ticket, err := s.findUserHold(id, userID)
+	if err != nil {
+		return err
+	}
+
+	released, err := s.releaseHold(ticket, entity.TicketStatusCancelled)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return errors.New("ticket is no longer on hold")
+	}
+
+	return nil
+}
+
+// ReleaseExpiredHolds expires holds whose time ran out and frees their seats.
+// It is run periodically by a background job.
+func (s *ticketService) ReleaseExpiredHolds() error {
+	for {
+		tickets, err := s.ticketRepo.FindExpiredHolds(time.Now(), 100)
+		if err != nil {
+			return err
+		}
+
+		for i := range tickets {
+			if _, err := s.releaseHold(&tickets[i], entity.TicketStatusExpired); err != nil {
+				return err
+			}
+		}
+
+		if len(tickets) < 100 {
+			return nil
+		}
+	}
+}
+
+// findUserHold loads a ticket and checks that it is a hold owned by the user
+func (s *ticketService) findUserHold(id uint, userID uint) (*entity.Ticket, error) {
+	ticket, err := s.ticketRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if ticket.UserID != userID {
+		return nil, errors.New("unauthorized to manage this ticket")
+	}
+
+	if ticket.Status != entity.TicketStatusReserved {
+		return nil, errors.New("ticket is not on hold")
+	}
+
+	// Order tickets are paid and released together with their order
+	if ticket.OrderID != nil {
+		return nil, errors.New("ticket is part of an order")
+	}
+
+	return ticket, nil
+}
+
+// releaseHold moves a reserved ticket to the given status and frees its seat.
+// It reports false when the ticket was confirmed or released concurrently.
+func (s *ticketService) releaseHold(ticket *entity.Ticket, status entity.TicketStatus) (bool, error) {
+	released := false
+	err := s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		var err error
+		released, err = releaseHeldTicket(repos, ticket, status)
+		return err
+	})
+	return released, err
+}
+
+// releaseHeldTicket moves a reserved ticket to the given status inside a
+// transaction, offers its seat to the waitlist or frees it and abandons the
+// pending order it belongs to
+func releaseHeldTicket(repos *repository.Repositories, ticket *entity.Ticket, status entity.TicketStatus) (bool, error) {
+	updated, err := repos.TicketRepository.UpdateStatus(ticket.ID, entity.TicketStatusReserved, status)
+	if err != nil || !updated {
+		return false, err
+	}
+
+	if err := passOnSeat(repos, ticket.EventID, ticket.TierID); err != nil {
+		return false, err
+	}
+
+	if err := releaseTicketSeat(repos, ticket); err != nil {
+		return false, err
+	}
+
+	if err := restorePromoCode(repos, ticket); err != nil {
+		return false, err
+	}
+
+	if ticket.OrderID != nil {
+		if _, err := repos.OrderRepository.UpdateStatus(*ticket.OrderID, entity.OrderStatusPending, entity.OrderStatusCancelled); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// releaseTicketSeat frees the reserved seat of a ticket that no longer holds one
+func releaseTicketSeat(repos *repository.Repositories, ticket *entity.Ticket) error {
+	if ticket.SeatID == nil {
+		return nil
+	}
+	return repos.SeatMapRepository.ReleaseTicketSeat(ticket.ID)
+}
+
+// GetTicketQRCode renders the signed credential of a purchased ticket as a PNG QR code
+func (s *ticketService) GetTicketQRCode(id uint) ([]byte, error) {
+	ticket, err := s.findPurchasedTicket(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports.GenerateTicketQRCode(SignTicketToken(ticket))
+}
+
+// GetTicketPDF renders a purchased ticket as a printable e-ticket
+func (s *ticketService) GetTicketPDF(id uint) ([]byte, error) {
+	ticket, err := s.findPurchasedTicket(id)
+	if err != nil {
+		return nil, err
+	}
+
+	qrCode, err := reports.GenerateTicketQRCode(SignTicketToken(ticket))
+	if err != nil {
+		return nil, err
+	}
+
+	return reports.GenerateTicketPDF(ticket, qrCode)
+}
+
+// findPurchasedTicket loads a ticket that is valid for entry
+func (s *ticketService) findPurchasedTicket(id uint) (*entity.Ticket, error) {
+	ticket, err := s.ticketRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if ticket.Status != entity.TicketStatusPurchased {
+		return nil, errors.New("only purchased tickets can be downloaded")
+	}
+
+	return ticket, nil
+}
+
+// CheckIn admits the holder of a scanned ticket credential at the door
+func (s *ticketService) CheckIn(token string, staffID uint) (*entity.Ticket, error) {
+	ticketID, eventID, holderID, err := parseTicketToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err := s.ticketRepo.FindByID(ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ticket.EventID != eventID {
+		return nil, errInvalidTicketToken
+	}
+
+	if ticket.Status == entity.TicketStatusCancelled {
+		return nil, errors.New("ticket has been cancelled")
+	}
+
+	if ticket.Status != entity.TicketStatusPurchased {
+		return nil, errors.New("ticket is not valid for entry")
+	}
+
+	if ticket.CheckedInAt != nil {
+		return nil, ErrTicketAlreadyCheckedIn
+	}
+
+	// Credentials issued to a previous holder stop working once the ticket is transferred
+	if ticket.UserID != holderID {
+		return nil, errors.New("ticket has been transferred to another holder")
 	}
 
-	// Update the ticket status
-	ticket.Status = entity.TicketStatusCancelled
-	return s.ticketRepo.Save(ticket)
-} 
\ No newline at end of file
+	// Two scanners may submit the same ticket at once; only one update wins
+	checkedInAt := time.Now()
+	checkedIn, err := s.ticketRepo.CheckIn(ticket.ID, staffID, checkedInAt)
+	if err != nil {
+		return nil, err
+	}
+	if !checkedIn {
+		return nil, ErrTicketAlreadyCheckedIn
+	}
+
+	ticket.CheckedInAt = &checkedInAt
+	ticket.CheckedInBy = &staffID
+	return ticket, nil
+}
+
+// StartTransfer offers a purchased ticket to the user registered under the
+// recipient's email. The ticket stays with its owner until the recipient accepts.
+func (s *ticketService) StartTransfer(id uint, userID uint, recipientEmail string) (*entity.TicketTransfer, error) {
+	ticket, err := s.ticketRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if ticket.UserID != userID {
+		return nil, errors.New("unauthorized to transfer this ticket")
+	}
+
+	if err := checkTicketTransferable(ticket); err != nil {
+		return nil, err
+	}
+
+	recipient, err := s.userRepo.FindByEmail(recipientEmail)
+	if err != nil {
+		return nil, errors.New("recipient not found")
+	}
+
+	if recipient.ID == userID {
+		return nil, errors.New("cannot transfer a ticket to yourself")
+	}
+
+	pending, err := s.transferRepo.FindPendingByTicketID(ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, errors.New("ticket already has a pending transfer")
+	}
+
+	transfer := &entity.TicketTransfer{
+		TicketID:   ticket.ID,
+		FromUserID: userID,
+		ToUserID:   recipient.ID,
+		Status:     entity.TransferStatusPending,
+	}
+	if err := s.transferRepo.Save(transfer); err != nil {
+		return nil, err
+	}
+
+	return s.transferRepo.FindByID(transfer.ID)
+}
+
+// AcceptTransfer hands the ticket of a pending transfer to the recipient
+func (s *ticketService) AcceptTransfer(transferID uint, userID uint) (*entity.TicketTransfer, error) {
+	transfer, err := s.transferRepo.FindByID(transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.ToUserID != userID {
+		return nil, errors.New("unauthorized to accept this transfer")
+	}
+
+	if transfer.Status != entity.TransferStatusPending {
+		return nil, errors.New("transfer is no longer pending")
+	}
+
+	if err := checkTicketTransferable(&transfer.Ticket); err != nil {
+		return nil, err
+	}
+
+	// The sender may cancel or use the ticket while the transfer is open, so
+	// both updates only apply if nothing changed in the meantime
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		// Transfers count towards the recipient's limit like purchases do, so
+		// tickets bought on several accounts cannot be gathered on one
+		event := transfer.Ticket.Event
+		if event.MaxTicketsPerUser > 0 {
+			held, err := repos.TicketRepository.CountUserTicketsByEventID(event.ID, userID)
+			if err != nil {
+				return err
+			}
+			if held+1 > int64(event.MaxTicketsPerUser) {
+				return fmt.Errorf("%w: at most %d tickets per user for this event, you already have %d", ErrUserLimitReached, event.MaxTicketsPerUser, held)
+			}
+		}
+
+		accepted, err := repos.TransferRepository.Accept(transfer.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return errors.New("transfer is no longer pending")
+		}
+
+		transferred, err := repos.TicketRepository.Transfer(transfer.TicketID, transfer.FromUserID, transfer.ToUserID)
+		if err != nil {
+			return err
+		}
+		if !transferred {
+			return errors.New("ticket can no longer be transferred")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.transferRepo.FindByID(transfer.ID)
+}
+
+// CancelTransfer closes a pending transfer. The sender withdraws it and the
+// recipient declines it.
+func (s *ticketService) CancelTransfer(transferID uint, userID uint) (*entity.TicketTransfer, error) {
+	transfer, err := s.transferRepo.FindByID(transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	var status entity.TransferStatus
+	switch userID {
+	case transfer.FromUserID:
+		status = entity.TransferStatusCancelled
+	case transfer.ToUserID:
+		status = entity.TransferStatusDeclined
+	default:
+		return nil, errors.New("unauthorized to manage this transfer")
+	}
+
+	updated, err := s.transferRepo.UpdateStatus(transfer.ID, entity.TransferStatusPending, status)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, errors.New("transfer is no longer pending")
+	}
+
+	return s.transferRepo.FindByID(transfer.ID)
+}
+
+// GetMyTransfers returns the transfers the user sent or received
+func (s *ticketService) GetMyTransfers(userID uint) ([]entity.TicketTransfer, error) {
+	return s.transferRepo.FindByUserID(userID)
+}
+
+// checkTicketTransferable rejects tickets that cannot change hands. Transfers
+// close when the event starts.
+func checkTicketTransferable(ticket *entity.Ticket) error {
+	if ticket.Status != entity.TicketStatusPurchased {
+		return errors.New("only purchased tickets can be transferred")
+	}
+
+	if ticket.CheckedInAt != nil {
+		return errors.New("cannot transfer a ticket that has been checked in")
+	}
+
+	if ticket.Event.Status == entity.EventStatusCancelled {
+		return errors.New("cannot transfer tickets for a cancelled event")
+	}
+
+	if !ticket.Event.StartDate.After(time.Now()) {
+		return errors.New("cannot transfer tickets for events that have already started")
+	}
+
+	return nil
+}
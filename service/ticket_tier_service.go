@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+)
+
+type TicketTierService interface {
+	GetEventTiers(eventID uint, includeUnpublished bool) ([]entity.TicketTier, error)
+	GetTierByID(id uint) (*entity.TicketTier, error)
+	CreateTier(tier *entity.TicketTier) error
+	UpdateTier(id uint, tier *entity.TicketTier) error
+	DeleteTier(id uint) error
+}
+
+type ticketTierService struct {
+	tierRepo   repository.TicketTierRepository
+	eventRepo  repository.EventRepository
+	transactor repository.Transactor
+}
+
+func NewTicketTierService(tierRepo repository.TicketTierRepository, eventRepo repository.EventRepository, transactor repository.Transactor) TicketTierService {
+	return &ticketTierService{
+		tierRepo:   tierRepo,
+		eventRepo:  eventRepo,
+		transactor: transactor,
+	}
+}
+
+// GetEventTiers lists the tiers of an event. Tiers of drafts and events that
+// are not public yet are only listed when includeUnpublished is set.
+func (s *ticketTierService) GetEventTiers(eventID uint, includeUnpublished bool) ([]entity.TicketTier, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !includeUnpublished && !IsEventPublished(event, time.Now()) {
+		return nil, errors.New("event not found")
+	}
+	return s.tierRepo.FindByEventID(eventID)
+}
+
+func (s *ticketTierService) GetTierByID(id uint) (*entity.TicketTier, error) {
+	return s.tierRepo.FindByID(id)
+}
+
+func (s *ticketTierService) CreateTier(tier *entity.TicketTier) error {
+	event, err := s.eventRepo.FindByID(tier.EventID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateTier(event, 0, tier); err != nil {
+		return err
+	}
+
+	tier.ID = 0
+	return s.saveTier(tier, tier.Capacity)
+}
+
+func (s *ticketTierService) UpdateTier(id uint, tier *entity.TicketTier) error {
+	// Get existing tier
+	existingTier, err := s.tierRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	event, err := s.eventRepo.FindByID(existingTier.EventID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateTier(event, id, tier); err != nil {
+		return err
+	}
+
+	// Capacity cannot drop below the seats that are already taken
+	if tier.Capacity < existingTier.SoldCount {
+		return errors.New("tier capacity cannot be lower than tickets already sold")
+	}
+
+	addedSeats := tier.Capacity - existingTier.Capacity
+
+	// Update tier fields
+	existingTier.Name = tier.Name
+	existingTier.Price = tier.Price
+	existingTier.Capacity = tier.Capacity
+	existingTier.SalesStart = tier.SalesStart
+	existingTier.SalesEnd = tier.SalesEnd
+
+	return s.saveTier(existingTier, addedSeats)
+}
+
+func (s *ticketTierService) DeleteTier(id uint) error {
+	return s.tierRepo.Delete(id)
+}
+
+// saveTier stores a tier and offers the seats it gained to the event's waitlist
+func (s *ticketTierService) saveTier(tier *entity.TicketTier, addedSeats int) error {
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := repos.TierRepository.Save(tier); err != nil {
+			return err
+		}
+		return offerNewSeats(repos, tier.EventID, tier, addedSeats)
+	})
+}
+
+// validateTier checks the tier's own fields and that all tiers of the event
+// still fit in the event capacity. tierID is the tier being replaced, if any.
+func (s *ticketTierService) validateTier(event *entity.Event, tierID uint, tier *entity.TicketTier) error {
+	if event.SeatMapID != nil {
+		return errors.New("events with reserved seating are priced by seat, not by tier")
+	}
+	if tier.Name == "" {
+		return errors.New("tier name is required")
+	}
+	if tier.Capacity <= 0 {
+		return errors.New("tier capacity must be positive")
+	}
+	if tier.Price < 0 {
+		return errors.New("tier price cannot be negative")
+	}
+	if tier.SalesStart != nil && tier.SalesEnd != nil && tier.SalesEnd.Before(*tier.SalesStart) {
+		return errors.New("tier sales end must be after sales start")
+	}
+
+	tiers, err := s.tierRepo.FindByEventID(event.ID)
+	if err != nil {
+		return err
+	}
+
+	allocated := tier.Capacity
+	for _, other := range tiers {
+		if other.ID != tierID {
+			allocated += other.Capacity
+		}
+	}
+
+	if allocated > event.Capacity {
+		return errors.New("total tier capacity cannot exceed event capacity")
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+)
+
+type VenueService interface {
+	GetAllVenues(page, limit int, city string) ([]entity.Venue, int64, error)
+	GetVenueByID(id uint) (*entity.Venue, error)
+	GetVenueEvents(id uint, page, limit int) ([]entity.Event, int64, error)
+	CreateVenue(venue *entity.Venue) error
+	UpdateVenue(id uint, venue *entity.Venue) error
+	DeleteVenue(id uint) error
+}
+
+type venueService struct {
+	venueRepo repository.VenueRepository
+	eventRepo repository.EventRepository
+}
+
+func NewVenueService(venueRepo repository.VenueRepository, eventRepo repository.EventRepository) VenueService {
+	return &venueService{
+		venueRepo: venueRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+func (s *venueService) GetAllVenues(page, limit int, city string) ([]entity.Venue, int64, error) {
+	// Default pagination values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	return s.venueRepo.FindAll(page, limit, strings.TrimSpace(city))
+}
+
+func (s *venueService) GetVenueByID(id uint) (*entity.Venue, error) {
+	return s.venueRepo.FindByID(id)
+}
+
+// GetVenueEvents lists the events held at a venue, soonest first
+func (s *venueService) GetVenueEvents(id uint, page, limit int) ([]entity.Event, int64, error) {
+	// Check if venue exists
+	if _, err := s.venueRepo.FindByID(id); err != nil {
+		return nil, 0, err
+	}
+
+	// Default pagination values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	now := time.Now()
+	return s.eventRepo.FindAll(page, limit, repository.EventFilter{VenueID: &id, Published: &now})
+}
+
+func (s *venueService) CreateVenue(venue *entity.Venue) error {
+	if err := s.validateVenue(0, venue); err != nil {
+		return err
+	}
+
+	venue.ID = 0
+	return s.venueRepo.Save(venue)
+}
+
+func (s *venueService) UpdateVenue(id uint, venue *entity.Venue) error {
+	// Get existing venue
+	existingVenue, err := s.venueRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateVenue(id, venue); err != nil {
+		return err
+	}
+
+	// Upcoming events must still fit in the venue
+	largest, err := s.venueRepo.LargestEventCapacity(id)
+	if err != nil {
+		return err
+	}
+	if venue.MaxCapacity < largest {
+		return fmt.Errorf("max_capacity cannot be lower than the capacity of an upcoming event (%d)", largest)
+	}
+
+	// Update venue fields
+	existingVenue.Name = venue.Name
+	existingVenue.Address = venue.Address
+	existingVenue.City = venue.City
+	existingVenue.Latitude = venue.Latitude
+	existingVenue.Longitude = venue.Longitude
+	existingVenue.Timezone = venue.Timezone
+	existingVenue.MaxCapacity = venue.MaxCapacity
+
+	return s.venueRepo.Save(existingVenue)
+}
+
+func (s *venueService) DeleteVenue(id uint) error {
+	return s.venueRepo.Delete(id)
+}
+
+// validateVenue trims and checks the fields of a venue. venueID is the venue
+// being replaced, if any.
+func (s *venueService) validateVenue(venueID uint, venue *entity.Venue) error {
+	venue.Name = strings.TrimSpace(venue.Name)
+	venue.Address = strings.TrimSpace(venue.Address)
+	venue.City = strings.TrimSpace(venue.City)
+	venue.Timezone = strings.TrimSpace(venue.Timezone)
+
+	if venue.Name == "" {
+		return errors.New("venue name is required")
+	}
+	if venue.Address == "" {
+		return errors.New("venue address is required")
+	}
+	if venue.City == "" {
+		return errors.New("venue city is required")
+	}
+	if venue.MaxCapacity <= 0 {
+		return errors.New("venue max_capacity must be positive")
+	}
+
+	if venue.Timezone == "" {
+		return errors.New("venue timezone is required")
+	}
+	if _, err := time.LoadLocation(venue.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", venue.Timezone)
+	}
+
+	if (venue.Latitude == nil) != (venue.Longitude == nil) {
+		return errors.New("latitude and longitude must be given together")
+	}
+	if venue.Latitude != nil && (*venue.Latitude < -90 || *venue.Latitude > 90) {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if venue.Longitude != nil && (*venue.Longitude < -180 || *venue.Longitude > 180) {
+		return errors.New("longitude must be between -180 and 180")
+	}
+
+	if existing, err := s.venueRepo.FindByName(venue.Name); err == nil && existing.ID != venueID {
+		return errors.New("venue already exists")
+	}
+
+	return nil
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+)
+
+type WaitlistService interface {
+	JoinWaitlist(eventID uint, userID uint) (*entity.WaitlistEntry, error)
+	LeaveWaitlist(eventID uint, userID uint) error
+	GetMyEntry(eventID uint, userID uint) (*entity.WaitlistEntry, error)
+	GetEventWaitlist(eventID uint) ([]entity.WaitlistEntry, error)
+	ClaimOffer(eventID uint, userID uint) (*entity.Ticket, *entity.Payment, error)
+	ExpireOffers() error
+}
+
+type waitlistService struct {
+	waitlistRepo   repository.WaitlistRepository
+	eventRepo      repository.EventRepository
+	tierRepo       repository.TicketTierRepository
+	paymentService PaymentService
+	transactor     repository.Transactor
+}
+
+func NewWaitlistService(waitlistRepo repository.WaitlistRepository, eventRepo repository.EventRepository, tierRepo repository.TicketTierRepository, paymentService PaymentService, transactor repository.Transactor) WaitlistService {
+	return &waitlistService{
+		waitlistRepo:   waitlistRepo,
+		eventRepo:      eventRepo,
+		tierRepo:       tierRepo,
+		paymentService: paymentService,
+		transactor:     transactor,
+	}
+}
+
+// JoinWaitlist puts the user at the back of the line for a sold out event
+func (s *waitlistService) JoinWaitlist(eventID uint, userID uint) (*entity.WaitlistEntry, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkEventPurchasable(event); err != nil {
+		return nil, err
+	}
+
+	if event.SoldCount < event.Capacity {
+		return nil, errors.New("event still has seats available")
+	}
+
+	// Freed seats go back on sale so buyers can pick them on the seat map
+	if event.SeatMapID != nil {
+		return nil, errors.New("events with reserved seating do not have a waitlist")
+	}
+
+	if _, err := s.waitlistRepo.FindActiveByUser(eventID, userID); err == nil {
+		return nil, errors.New("already on the waitlist for this event")
+	}
+
+	entry := &entity.WaitlistEntry{
+		EventID: eventID,
+		UserID:  userID,
+		Status:  entity.WaitlistStatusWaiting,
+	}
+	if err := s.waitlistRepo.Save(entry); err != nil {
+		return nil, err
+	}
+
+	return s.withPosition(entry)
+}
+
+// LeaveWaitlist removes the user from the line, passing on any seat they were offered
+func (s *waitlistService) LeaveWaitlist(eventID uint, userID uint) error {
+	entry, err := s.waitlistRepo.FindActiveByUser(eventID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		left, err := repos.WaitlistRepository.UpdateStatus(entry.ID, entry.Status, entity.WaitlistStatusLeft)
+		if err != nil {
+			return err
+		}
+		if !left {
+			return errors.New("waitlist entry has changed, please try again")
+		}
+
+		if entry.Status == entity.WaitlistStatusOffered {
+			return passOnSeat(repos, entry.EventID, entry.OfferTierID)
+		}
+		return nil
+	})
+}
+
+func (s *waitlistService) GetMyEntry(eventID uint, userID uint) (*entity.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.FindActiveByUser(eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withPosition(entry)
+}
+
+// GetEventWaitlist returns the queue of an event with each waiting user's position
+func (s *waitlistService) GetEventWaitlist(eventID uint) ([]entity.WaitlistEntry, error) {
+	// Check if event exists
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.waitlistRepo.FindActiveByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var position int64
+	for i := range entries {
+		if entries[i].Status == entity.WaitlistStatusWaiting {
+			position++
+			entries[i].Position = position
+		}
+	}
+
+	return entries, nil
+}
+
+// ClaimOffer turns the seat offered to the user into a hold and starts its
+// payment. The seat was already taken for the offer, so capacity is not
+// checked again.
+func (s *waitlistService) ClaimOffer(eventID uint, userID uint) (*entity.Ticket, *entity.Payment, error) {
+	entry, err := s.waitlistRepo.FindActiveByUser(eventID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if entry.Status != entity.WaitlistStatusOffered {
+		return nil, nil, errors.New("no seat has been offered to you yet")
+	}
+
+	now := time.Now()
+	if !entry.OfferExpiresAt.After(now) {
+		return nil, nil, errors.New("waitlist offer has expired")
+	}
+
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkEventPurchasable(event); err != nil {
+		return nil, nil, err
+	}
+
+	// The offered seat is sold like any other seat of its tier
+	var tier *entity.TicketTier
+	if entry.OfferTierID != nil {
+		if tier, err = s.tierRepo.FindByID(*entry.OfferTierID); err != nil {
+			return nil, nil, err
+		}
+		if err := checkTierOnSale(tier, now); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	expiresAt := now.Add(config.AppConfig.TicketHoldDuration)
+	ticket := &entity.Ticket{
+		UserID:      userID,
+		EventID:     eventID,
+		TierID:      entry.OfferTierID,
+		Status:      entity.TicketStatusReserved,
+		PurchasedAt: now,
+		ExpiresAt:   &expiresAt,
+	}
+	priceTicket(ticket, ticketPrice(event, tier, nil), 0)
+
+	err = s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+		if err := checkPurchaseLimits(repos, event, userID, 1, now); err != nil {
+			return err
+		}
+		if err := repos.TicketRepository.Save(ticket); err != nil {
+			return err
+		}
+
+		claimed, err := repos.WaitlistRepository.Claim(entry.ID, ticket.ID, now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return errors.New("waitlist offer has expired")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payment, err := s.paymentService.StartTicketPayment(ticket, ticket.PricePaid)
+	if err != nil {
+		s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+			_, err := releaseHeldTicket(repos, ticket, entity.TicketStatusCancelled)
+			return err
+		})
+		return nil, nil, err
+	}
+
+	return ticket, payment, nil
+}
+
+// ExpireOffers rolls offers that were not claimed in time over to the next
+// person in line. It is run periodically by a background job.
+func (s *waitlistService) ExpireOffers() error {
+	for {
+		entries, err := s.waitlistRepo.FindExpiredOffers(time.Now(), 100)
+		if err != nil {
+			return err
+		}
+
+		for i := range entries {
+			entry := &entries[i]
+			err := s.transactor.WithinTransaction(func(repos *repository.Repositories) error {
+				expired, err := repos.WaitlistRepository.UpdateStatus(entry.ID, entity.WaitlistStatusOffered, entity.WaitlistStatusExpired)
+				if err != nil || !expired {
+					return err
+				}
+				return passOnSeat(repos, entry.EventID, entry.OfferTierID)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(entries) < 100 {
+			return nil
+		}
+	}
+}
+
+// withPosition fills in how many people are ahead of a waiting entry
+func (s *waitlistService) withPosition(entry *entity.WaitlistEntry) (*entity.WaitlistEntry, error) {
+	if entry.Status != entity.WaitlistStatusWaiting {
+		return entry, nil
+	}
+
+	ahead, err := s.waitlistRepo.CountWaitingBefore(entry.EventID, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Position = ahead + 1
+	return entry, nil
+}
+
+// passOnSeat hands a seat that is being given up to the next person on the
+// waitlist, keeping it taken, or frees it when nobody is waiting. It must run
+// inside a transaction.
+func passOnSeat(repos *repository.Repositories, eventID uint, tierID *uint) error {
+	event, err := repos.EventRepository.FindByID(eventID)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(entity.OpenEventStatuses, event.Status) {
+		for {
+			entry, err := repos.WaitlistRepository.FindNextWaiting(eventID)
+			if err != nil {
+				return err
+			}
+			if entry == nil {
+				break
+			}
+
+			offered, err := repos.WaitlistRepository.Offer(entry.ID, tierID, time.Now().Add(config.AppConfig.WaitlistClaimDuration))
+			if err != nil {
+				return err
+			}
+			if offered {
+				return nil
+			}
+		}
+	}
+
+	return releaseSeats(repos, eventID, tierID, 1)
+}
+
+// offerNewSeats hands seats added to an event or one of its tiers to the
+// people waiting for it, taking each seat for its offer. Tiered events gain
+// seats through their tiers, and tiers that are not on sale keep theirs. It
+// must run inside a transaction.
+func offerNewSeats(repos *repository.Repositories, eventID uint, tier *entity.TicketTier, added int) error {
+	var tierID *uint
+	if tier != nil {
+		if checkTierOnSale(tier, time.Now()) != nil {
+			return nil
+		}
+		tierID = &tier.ID
+	} else {
+		tiers, err := repos.TierRepository.CountByEventID(eventID)
+		if err != nil || tiers > 0 {
+			return err
+		}
+	}
+
+	for i := 0; i < added; i++ {
+		entry, err := repos.WaitlistRepository.FindNextWaiting(eventID)
+		if err != nil || entry == nil {
+			return err
+		}
+
+		if err := reserveSeats(repos, eventID, tier, 1); err != nil {
+			return err
+		}
+		if err := passOnSeat(repos, eventID, tierID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// closeWaitlist ends the waitlist of a cancelled event. Seats held for open
+// offers are given back. It must run inside a transaction.
+func closeWaitlist(repos *repository.Repositories, eventID uint) error {
+	entries, err := repos.WaitlistRepository.FindActiveByEventID(eventID)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		closed, err := repos.WaitlistRepository.UpdateStatus(entry.ID, entry.Status, entity.WaitlistStatusClosed)
+		if err != nil {
+			return err
+		}
+		if closed && entry.Status == entity.WaitlistStatusOffered {
+			if err := releaseSeats(repos, eventID, entry.OfferTierID, 1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/taufikmulyawan/ticketing-system/config"
+)
+
+// ErrNotFound is returned for keys that hold no object
+var ErrNotFound = errors.New("file not found")
+
+// Driver keeps uploaded files as objects addressed by slash separated keys
+// such as "events/1700000000.jpg". Implementations must be safe for
+// concurrent use.
+type Driver interface {
+	// Put stores the object, replacing any object already under the key
+	Put(key string, body io.Reader, size int64, contentType string) error
+	// Get opens the object for reading, the caller closes it
+	Get(key string) (io.ReadCloser, error)
+	// Delete removes the object
+	Delete(key string) error
+	// PresignGet returns a URL the object can be downloaded from without
+	// credentials until the expiry has passed
+	PresignGet(key string, expiry time.Duration) (string, error)
+}
+
+// NewDriver creates the driver selected by the configuration
+func NewDriver(cfg config.Config) (Driver, error) {
+	switch cfg.StorageDriver {
+	case "", "local":
+		return NewLocalDriver(cfg.StorageLocalDir), nil
+	case "s3":
+		return NewS3Driver(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q, use local or s3", cfg.StorageDriver)
+	}
+}
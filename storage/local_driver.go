@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// localDriver keeps objects as files under a directory. It only suits a
+// single instance, deployments with several instances need a shared bucket.
+type localDriver struct {
+	root string
+}
+
+func NewLocalDriver(root string) Driver {
+	if root == "" {
+		root = "uploads"
+	}
+	return &localDriver{
+		root: root,
+	}
+}
+
+func (d *localDriver) Put(key string, body io.Reader, size int64, contentType string) error {
+	fullPath := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see a partial object
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), fullPath)
+}
+
+func (d *localDriver) Get(key string) (io.ReadCloser, error) {
+	file, err := os.Open(d.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (d *localDriver) Delete(key string) error {
+	err := os.Remove(d.path(key))
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// PresignGet returns the path the API serves the file from. Local files can
+// only be reached through the API, which needs no signature to serve them, so
+// the URL does not expire.
+func (d *localDriver) PresignGet(key string, expiry time.Duration) (string, error) {
+	return "/files/" + cleanKey(key), nil
+}
+
+// path maps a key to a file under the root, keys cannot climb out of it
+func (d *localDriver) path(key string) string {
+	return filepath.Join(d.root, filepath.FromSlash(cleanKey(key)))
+}
+
+// cleanKey resolves dot segments of a key so it stays within the storage root
+func cleanKey(key string) string {
+	return path.Clean("/" + key)[1:]
+}
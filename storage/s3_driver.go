@@ -0,0 +1,305 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Presigned URLs are valid for at least a second and at most the seven days S3 allows
+const (
+	minPresignExpiry = time.Second
+	maxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// unsignedPayload stands in for the body hash so uploads can be streamed
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Options configures the S3 compatible driver
+type S3Options struct {
+	Endpoint   string // Scheme and host of the service, e.g. http://localhost:9000
+	Region     string // us-east-1 when empty, which MinIO accepts by default
+	Bucket     string
+	AccessKey  string
+	SecretKey  string
+	HTTPClient *http.Client // http.DefaultClient when nil
+}
+
+// s3Driver keeps objects in a bucket of an S3 compatible service such as AWS
+// S3 or MinIO. Requests use path style addressing and are signed with AWS
+// Signature Version 4.
+type s3Driver struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+	now       func() time.Time
+}
+
+func NewS3Driver(options S3Options) (Driver, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(options.Endpoint, "/"))
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q, use a URL such as https://s3.amazonaws.com", options.Endpoint)
+	}
+	if options.Bucket == "" {
+		return nil, errors.New("the S3 bucket is required")
+	}
+	if options.AccessKey == "" || options.SecretKey == "" {
+		return nil, errors.New("the S3 access key and secret key are required")
+	}
+
+	region := options.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	client := options.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &s3Driver{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    options.Bucket,
+		accessKey: options.AccessKey,
+		secretKey: options.SecretKey,
+		client:    client,
+		now:       time.Now,
+	}, nil
+}
+
+func (d *s3Driver) Put(key string, body io.Reader, size int64, contentType string) error {
+	req, err := d.newRequest(http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := d.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(req, resp)
+	}
+	return nil
+}
+
+func (d *s3Driver) Get(key string) (io.ReadCloser, error) {
+	req, err := d.newRequest(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, responseError(req, resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete removes the object. S3 reports success for keys that do not exist,
+// so the object is looked up first to report ErrNotFound like the local driver.
+func (d *s3Driver) Delete(key string) error {
+	head, err := d.newRequest(http.MethodHead, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := d.do(head)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(head, resp)
+	}
+
+	req, err := d.newRequest(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err = d.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return responseError(req, resp)
+	}
+	return nil
+}
+
+// PresignGet signs a download URL for the object in its query string, which
+// the service honours until the expiry has passed
+func (d *s3Driver) PresignGet(key string, expiry time.Duration) (string, error) {
+	if expiry < minPresignExpiry {
+		expiry = minPresignExpiry
+	}
+	if expiry > maxPresignExpiry {
+		expiry = maxPresignExpiry
+	}
+
+	now := d.now().UTC()
+	objectPath := d.objectPath(key)
+	query := url.Values{
+		"X-Amz-Algorithm":     {"AWS4-HMAC-SHA256"},
+		"X-Amz-Credential":    {d.accessKey + "/" + d.scope(now)},
+		"X-Amz-Date":          {now.Format(amzDateFormat)},
+		"X-Amz-Expires":       {strconv.Itoa(int(expiry / time.Second))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	headers := map[string]string{"host": d.endpoint.Host}
+
+	canonical, _ := canonicalRequest(http.MethodGet, objectPath, query, headers, unsignedPayload)
+	query.Set("X-Amz-Signature", d.signature(now, canonical))
+
+	return d.endpoint.Scheme + "://" + d.endpoint.Host + uriEncode(objectPath, false) + "?" + canonicalQuery(query), nil
+}
+
+// newRequest builds a request for the object at the given key
+func (d *s3Driver) newRequest(method string, key string, body io.Reader) (*http.Request, error) {
+	objectURL := d.endpoint.Scheme + "://" + d.endpoint.Host + uriEncode(d.objectPath(key), false)
+	return http.NewRequest(method, objectURL, body)
+}
+
+// do signs the request and sends it
+func (d *s3Driver) do(req *http.Request) (*http.Response, error) {
+	now := d.now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ",")
+	}
+
+	canonical, signedHeaders := canonicalRequest(req.Method, req.URL.Path, req.URL.Query(), headers, unsignedPayload)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		d.accessKey, d.scope(now), signedHeaders, d.signature(now, canonical)))
+
+	return d.client.Do(req)
+}
+
+// objectPath is the path of the object under its bucket
+func (d *s3Driver) objectPath(key string) string {
+	return strings.TrimSuffix(d.endpoint.Path, "/") + "/" + d.bucket + "/" + cleanKey(key)
+}
+
+const amzDateFormat = "20060102T150405Z"
+
+// scope limits a signature to a day, region and service
+func (d *s3Driver) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + d.region + "/s3/aws4_request"
+}
+
+// signature signs a canonical request with a key derived from the secret key
+func (d *s3Driver) signature(now time.Time, canonical string) string {
+	hashed := sha256.Sum256([]byte(canonical))
+	stringToSign := "AWS4-HMAC-SHA256\n" + now.Format(amzDateFormat) + "\n" + d.scope(now) + "\n" + hex.EncodeToString(hashed[:])
+
+	key := hmacSHA256([]byte("AWS4"+d.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, d.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalRequest lays out a request the way Signature Version 4 hashes it
+// and lists the headers it covers. Header names must be lower case.
+func canonicalRequest(method string, path string, query url.Values, headers map[string]string, payloadHash string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	return strings.Join([]string{
+		method,
+		uriEncode(path, false),
+		canonicalQuery(query),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n"), signedHeaders
+}
+
+// canonicalQuery encodes the query sorted by name and value
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent encodes everything but unreserved characters, and slashes
+// unless encodeSlash is set, as Signature Version 4 requires
+func uriEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			encoded.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			encoded.WriteByte(c)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// responseError turns a failed response into an error, ErrNotFound for
+// missing objects
+func responseError(req *http.Request, resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+
+	var body struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body); err == nil && body.Code != "" {
+		return fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, body.Code, body.Message)
+	}
+	return fmt.Errorf("s3 %s %s: %s", req.Method, req.URL.Path, resp.Status)
+}
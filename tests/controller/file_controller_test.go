@@ -2,6 +2,7 @@ package tests
 
 import (
 	"bytes"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -25,9 +26,10 @@ func (m *MockFileService) UploadFile(file *multipart.FileHeader, fileType string
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockFileService) GetFilePath(filename string, fileType string) (string, error) {
+func (m *MockFileService) OpenFile(filename string, fileType string) (io.ReadCloser, error) {
 	args := m.Called(filename, fileType)
-	return args.String(0), args.Error(1)
+	file, _ := args.Get(0).(io.ReadCloser)
+	return file, args.Error(1)
 }
 
 func (m *MockFileService) DeleteFile(filename string, fileType string) error {
@@ -35,6 +37,11 @@ func (m *MockFileService) DeleteFile(filename string, fileType string) error {
 	return args.Error(0)
 }
 
+func (m *MockFileService) FileURL(filename string, fileType string) (string, error) {
+	args := m.Called(filename, fileType)
+	return args.String(0), args.Error(1)
+}
+
 func TestUploadFile(t *testing.T) {
 	// Setup
 	gin.SetMode(gin.TestMode)
@@ -88,8 +95,11 @@ func TestDownloadFile(t *testing.T) {
 	err = os.WriteFile(testFilePath, []byte("test content"), 0644)
 	assert.NoError(t, err)
 
-	// Set expectations - return the path to our test file
-	mockService.On("GetFilePath", "test.txt", "events").Return(testFilePath, nil)
+	testFile, err := os.Open(testFilePath)
+	assert.NoError(t, err)
+
+	// Set expectations - return our test file
+	mockService.On("OpenFile", "test.txt", "events").Return(testFile, nil)
 
 	// Create test request
 	req, _ := http.NewRequest("GET", "/files/events/test.txt", nil)
@@ -127,4 +137,40 @@ func TestDeleteFile(t *testing.T) {
 	// Assertions
 	assert.Equal(t, http.StatusOK, resp.Code)
 	mockService.AssertExpectations(t)
-} 
\ No newline at end of file
+} 
+func TestDownloadFile_PrivateTypeRequiresSignIn(t *testing.T) {
+	// Setup
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockFileService)
+	fileController := controller.NewFileController(mockService)
+
+	// Create a test router, signing in is simulated by setting the user
+	router := gin.Default()
+	router.GET("/files/:type/:filename", func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Set("user_id", float64(1))
+		}
+	}, fileController.DownloadFile)
+
+	mockService.On("OpenFile", "ticket.pdf", "tickets").Return(io.NopCloser(bytes.NewBufferString("ticket")), nil)
+
+	// Test: anonymous download of a ticket file
+	req, _ := http.NewRequest("GET", "/files/tickets/ticket.pdf", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	mockService.AssertNotCalled(t, "OpenFile", "ticket.pdf", "tickets")
+
+	// Test: the same download when signed in
+	req, _ = http.NewRequest("GET", "/files/tickets/ticket.pdf", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "ticket", resp.Body.String())
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/dto"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+func newTestCategoryService(repos *repository.Repositories) service.CategoryService {
+	return service.NewCategoryService(repos.CategoryRepository, repos.TagRepository, repos.EventRepository)
+}
+
+func TestCreateCategory_DerivesUniqueSlug(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	categoryService := newTestCategoryService(repos)
+
+	// Test
+	category := &entity.Category{Name: "  Live Music & Jazz ", Description: "Concerts"}
+	err := categoryService.CreateCategory(category)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "Live Music & Jazz", category.Name)
+	assert.Equal(t, "live-music-jazz", category.Slug)
+
+	assert.EqualError(t, categoryService.CreateCategory(&entity.Category{Name: "live music, jazz"}), "category already exists")
+	assert.EqualError(t, categoryService.CreateCategory(&entity.Category{Name: " & "}), "category name must contain letters or digits")
+	assert.EqualError(t, categoryService.CreateCategory(&entity.Category{Name: ""}), "category name is required")
+
+	// Renaming keeps the category's own slug free
+	assert.NoError(t, categoryService.UpdateCategory(category.ID, &entity.Category{Name: "Live Music & Jazz!"}))
+	updated, _ := categoryService.GetCategoryByID(category.ID)
+	assert.Equal(t, "live-music-jazz", updated.Slug)
+
+	tag := &entity.Tag{Name: "Family Friendly"}
+	assert.NoError(t, categoryService.CreateTag(tag))
+	assert.Equal(t, "family-friendly", tag.Slug)
+	assert.EqualError(t, categoryService.CreateTag(&entity.Tag{Name: "family-friendly"}), "tag already exists")
+}
+
+func TestGetAllEvents_FiltersByCategoryAndTag(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	categoryService := newTestCategoryService(repos)
+	eventService := newTestEventService(repos)
+
+	music := &entity.Category{Name: "Music"}
+	sports := &entity.Category{Name: "Sports"}
+	outdoor := &entity.Tag{Name: "Outdoor"}
+	for _, category := range []*entity.Category{music, sports} {
+		assert.NoError(t, categoryService.CreateCategory(category))
+	}
+	assert.NoError(t, categoryService.CreateTag(outdoor))
+
+	concert := createTestEvent(t, 100)
+	festival := createTestEvent(t, 100)
+	marathon := createTestEvent(t, 100)
+	draft := createTestEvent(t, 100)
+	cancelled := createTestEvent(t, 100)
+	config.DB.Model(draft).Update("draft", true)
+	config.DB.Model(cancelled).Update("status", entity.EventStatusCancelled)
+
+	for _, event := range []*entity.Event{concert, festival, draft, cancelled} {
+		_, err := categoryService.SetEventCategories(event.ID, []uint{music.ID})
+		assert.NoError(t, err)
+	}
+	linked, err := categoryService.SetEventCategories(marathon.ID, []uint{sports.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, "Sports", linked.Categories[0].Name)
+	for _, event := range []*entity.Event{festival, marathon} {
+		_, err := categoryService.SetEventTags(event.ID, []uint{outdoor.ID})
+		assert.NoError(t, err)
+	}
+
+	names := func(filter dto.EventFilterRequest) []string {
+		events, _, err := eventService.GetAllEvents(1, 10, filter)
+		assert.NoError(t, err)
+		result := make([]string, 0, len(events))
+		for _, event := range events {
+			result = append(result, event.Name)
+		}
+		return result
+	}
+
+	// Test and assertions
+	assert.ElementsMatch(t, []string{concert.Name, festival.Name, cancelled.Name}, names(dto.EventFilterRequest{Category: "music"}))
+	assert.ElementsMatch(t, []string{festival.Name, marathon.Name}, names(dto.EventFilterRequest{Tag: "Outdoor"}))
+	assert.Equal(t, []string{festival.Name}, names(dto.EventFilterRequest{Category: "Music", Tag: "outdoor"}))
+	assert.Empty(t, names(dto.EventFilterRequest{Category: "theatre"}))
+
+	// Counts only include upcoming public events
+	categories, err := categoryService.GetAllCategories()
+	assert.NoError(t, err)
+	assert.Equal(t, "Music", categories[0].Name)
+	assert.Equal(t, int64(2), categories[0].EventCount)
+	assert.Equal(t, int64(1), categories[1].EventCount)
+	tags, _ := categoryService.GetAllTags()
+	assert.Equal(t, int64(2), tags[0].EventCount)
+
+	// Unknown IDs are rejected and an empty list clears the links
+	_, err = categoryService.SetEventCategories(concert.ID, []uint{music.ID, 999})
+	assert.EqualError(t, err, "category not found")
+	cleared, err := categoryService.SetEventTags(festival.ID, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, cleared.Tags)
+
+	// Deleting a category takes it off its events
+	assert.NoError(t, categoryService.DeleteCategory(sports.ID))
+	assert.Empty(t, names(dto.EventFilterRequest{Category: "sports"}))
+	event, _ := repos.EventRepository.FindByID(marathon.ID)
+	assert.Empty(t, event.Categories)
+}
+
+func TestCategorySalesReport_GroupsByCategory(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	categoryService := newTestCategoryService(repos)
+	ticketService, paymentService := newTestTicketService(repos)
+	reportService := service.NewReportService(repos.TicketRepository, repos.EventRepository, repos.TierRepository, repos.RefundRepository, repos.CategoryRepository)
+
+	music := &entity.Category{Name: "Music"}
+	festivals := &entity.Category{Name: "Festivals"}
+	assert.NoError(t, categoryService.CreateCategory(music))
+	assert.NoError(t, categoryService.CreateCategory(festivals))
+
+	concert := createTestEvent(t, 10)
+	festival := createTestEvent(t, 10)
+	talk := createTestEvent(t, 10)
+	_, err := categoryService.SetEventCategories(concert.ID, []uint{music.ID})
+	assert.NoError(t, err)
+	_, err = categoryService.SetEventCategories(festival.ID, []uint{music.ID, festivals.ID})
+	assert.NoError(t, err)
+
+	for _, event := range []*entity.Event{concert, festival, festival, talk} {
+		payment, err := ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: event.ID})
+		assert.NoError(t, err)
+		assert.NoError(t, sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded))
+	}
+
+	// Test
+	summaries, err := reportService.GetCategorySalesSummary()
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 3)
+
+	// Events in several categories count towards each of them
+	assert.Equal(t, "Festivals", summaries[0].CategoryName)
+	assert.Equal(t, int64(1), summaries[0].TotalEvents)
+	assert.Equal(t, int64(2), summaries[0].TotalTickets)
+	assert.Equal(t, "Music", summaries[1].CategoryName)
+	assert.Equal(t, int64(2), summaries[1].TotalEvents)
+	assert.Equal(t, int64(3), summaries[1].TotalTickets)
+
+	assert.Equal(t, uint(0), summaries[2].CategoryID)
+	assert.Equal(t, "Uncategorized", summaries[2].CategoryName)
+	assert.Equal(t, int64(1), summaries[2].TotalTickets)
+	assert.Equal(t, summaries[1].TotalRevenue/3, summaries[2].TotalRevenue)
+}
@@ -0,0 +1,270 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+func newTestSeriesService(repos *repository.Repositories, eventService service.EventService) service.EventSeriesService {
+	return service.NewEventSeriesService(repos.EventSeriesRepository, repos.EventRepository, repos.VenueRepository, eventService)
+}
+
+// occurrenceDates lists the days of a series' occurrences, soonest first
+func occurrenceDates(t *testing.T, repos *repository.Repositories, seriesID uint) []string {
+	events, err := repos.EventRepository.FindBySeriesID(seriesID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dates := make([]string, len(events))
+	for i, event := range events {
+		dates[i] = event.SeriesDate
+	}
+	return dates
+}
+
+func TestCreateSeries_WeeklyWithException(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	eventService := newTestEventService(repos)
+	seriesService := newTestSeriesService(repos, eventService)
+	ticketService, _ := newTestTicketService(repos)
+
+	start := time.Now().UTC().Add(72 * time.Hour).Truncate(time.Hour)
+	day := func(weeks int) string { return start.AddDate(0, 0, 7*weeks).Format("2006-01-02") }
+
+	series := &entity.EventSeries{
+		Name:            "Weekly Workshop",
+		Location:        "Jakarta",
+		Capacity:        2,
+		Price:           50000,
+		Frequency:       entity.RecurrenceWeekly,
+		StartDate:       start,
+		DurationMinutes: 90,
+		Count:           4,
+		Exceptions:      []string{day(1)},
+	}
+
+	// Test
+	err := seriesService.CreateSeries(series)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 1, series.Interval)
+
+	// The exception counts towards the four occurrences
+	assert.Equal(t, []string{day(0), day(2), day(3)}, occurrenceDates(t, repos, series.ID))
+
+	events, _ := repos.EventRepository.FindBySeriesID(series.ID)
+	assert.Equal(t, "Weekly Workshop", events[1].Name)
+	assert.True(t, events[1].StartDate.Equal(start.AddDate(0, 0, 14)))
+	assert.True(t, events[1].EndDate.Equal(start.AddDate(0, 0, 14).Add(90*time.Minute)))
+
+	// Every occurrence has its own capacity
+	for _, userID := range []uint{1, 2} {
+		_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: userID, EventID: events[0].ID})
+		assert.NoError(t, err)
+	}
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 3, EventID: events[0].ID})
+	assert.EqualError(t, err, "event is sold out")
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 3, EventID: events[1].ID})
+	assert.NoError(t, err)
+
+	// Occurrences share their name, standalone events do not
+	standalone := &entity.Event{Name: "Weekly Workshop", Location: "Bandung", StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10}
+	assert.NoError(t, eventService.CreateEvent(standalone))
+	duplicate := &entity.Event{Name: "weekly workshop", Location: "Bandung", StartDate: start, EndDate: start.Add(time.Hour), Capacity: 10}
+	assert.EqualError(t, eventService.CreateEvent(duplicate), "event name already exists")
+}
+
+func TestCreateSeries_MonthlySkipsShortMonths(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	seriesService := newTestSeriesService(repos, newTestEventService(repos))
+	venue := createTestVenue(t, repos, "Istora Senayan", "Jakarta", 500)
+
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	year := time.Now().Year() + 1
+	until := time.Date(year, time.June, 30, 0, 0, 0, 0, jakarta)
+
+	series := &entity.EventSeries{
+		Name:            "Month End Jazz",
+		VenueID:         &venue.ID,
+		Capacity:        100,
+		Price:           75000,
+		Frequency:       entity.RecurrenceMonthly,
+		StartDate:       time.Date(year, time.January, 31, 19, 30, 0, 0, jakarta),
+		DurationMinutes: 120,
+		Until:           &until,
+	}
+
+	// Test
+	err := seriesService.CreateSeries(series)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		time.Date(year, time.January, 31, 0, 0, 0, 0, time.UTC).Format("2006-01-02"),
+		time.Date(year, time.March, 31, 0, 0, 0, 0, time.UTC).Format("2006-01-02"),
+		time.Date(year, time.May, 31, 0, 0, 0, 0, time.UTC).Format("2006-01-02"),
+	}, occurrenceDates(t, repos, series.ID))
+
+	events, _ := repos.EventRepository.FindBySeriesID(series.ID)
+	assert.Equal(t, "Istora Senayan, Jakarta", events[0].Location)
+	assert.True(t, events[2].StartDate.Equal(time.Date(year, time.May, 31, 19, 30, 0, 0, jakarta)))
+
+	// Invalid rules are rejected before anything is generated
+	invalid := *series
+	invalid.ID = 0
+	invalid.Name = "Broken Jazz"
+	invalid.Count = 3
+	assert.EqualError(t, seriesService.CreateSeries(&invalid), "exactly one of until and count is required")
+	invalid.Until = nil
+	invalid.Exceptions = []string{"31-01-2030"}
+	assert.EqualError(t, seriesService.CreateSeries(&invalid), `invalid exception "31-01-2030", use YYYY-MM-DD`)
+	invalid.Exceptions = nil
+	invalid.Capacity = 501
+	assert.EqualError(t, seriesService.CreateSeries(&invalid), "series capacity cannot be higher than the venue's max capacity of 500")
+}
+
+func TestUpdateSeries_EditsOccurrencesAndWholeSeries(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	eventService := newTestEventService(repos)
+	seriesService := newTestSeriesService(repos, eventService)
+	ticketService, _ := newTestTicketService(repos)
+
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Hour)
+	day := func(days int) string { return start.AddDate(0, 0, days).Format("2006-01-02") }
+
+	series := &entity.EventSeries{
+		Name:            "Morning Yoga",
+		Location:        "Jakarta",
+		Capacity:        20,
+		Price:           40000,
+		Frequency:       entity.RecurrenceDaily,
+		StartDate:       start,
+		DurationMinutes: 60,
+		Count:           3,
+	}
+	assert.NoError(t, seriesService.CreateSeries(series))
+	events, _ := repos.EventRepository.FindBySeriesID(series.ID)
+
+	// Editing one occurrence leaves the others alone
+	single := events[1]
+	single.Price = 30000
+	assert.NoError(t, eventService.UpdateEvent(single.ID, &single))
+	edited, _ := repos.EventRepository.FindByID(events[1].ID)
+	assert.Equal(t, 30000.0, edited.Price)
+	assert.Equal(t, series.ID, *edited.SeriesID)
+	untouched, _ := repos.EventRepository.FindByID(events[0].ID)
+	assert.Equal(t, 40000.0, untouched.Price)
+
+	_, err := ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: events[0].ID})
+	assert.NoError(t, err)
+
+	// Test: the series gets a new price, drops its third day and runs a day longer
+	update := *series
+	update.Price = 45000
+	update.Count = 4
+	update.Exceptions = []string{day(2)}
+	err = seriesService.UpdateSeries(series.ID, &update)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, []string{day(0), day(1), day(3)}, occurrenceDates(t, repos, series.ID))
+	updated, _ := repos.EventRepository.FindBySeriesID(series.ID)
+	for _, event := range updated {
+		assert.Equal(t, 45000.0, event.Price)
+	}
+	assert.Equal(t, events[0].ID, updated[0].ID)
+	assert.Equal(t, 1, updated[0].SoldCount)
+
+	// Occurrences with tickets sold cannot be dropped or shrunk below their sales
+	update.Exceptions = []string{day(0)}
+	assert.EqualError(t, seriesService.UpdateSeries(series.ID, &update), "the occurrence on "+day(0)+" has tickets sold, cancel it instead of removing it from the series")
+	update.Exceptions = []string{day(2)}
+	update.Frequency = entity.RecurrenceWeekly
+	assert.EqualError(t, seriesService.UpdateSeries(series.ID, &update), "the recurrence of a series cannot be changed, create a new series instead")
+
+	assert.EqualError(t, seriesService.DeleteSeries(series.ID), "cannot delete a series with tickets sold, cancel its occurrences instead")
+}
+
+func TestUpdateSeries_FailedOccurrenceLeavesSeriesUnchanged(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	eventService := newTestEventService(repos)
+	seriesService := newTestSeriesService(repos, eventService)
+
+	start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Hour)
+	series := &entity.EventSeries{
+		Name:            "Evening Choir",
+		Location:        "Jakarta",
+		Capacity:        20,
+		Price:           40000,
+		Frequency:       entity.RecurrenceDaily,
+		StartDate:       start,
+		DurationMinutes: 60,
+		Count:           3,
+	}
+	assert.NoError(t, seriesService.CreateSeries(series))
+	events, _ := repos.EventRepository.FindBySeriesID(series.ID)
+
+	// The last occurrence can no longer be saved
+	config.DB.Model(&events[2]).Update("max_tickets_per_user", -1)
+
+	// Test: the series is renamed, runs a day longer and changes its price
+	update := *series
+	update.Name = "Late Choir"
+	update.Price = 45000
+	update.Count = 4
+	err := seriesService.UpdateSeries(series.ID, &update)
+
+	// Assertions
+	assert.EqualError(t, err, "updating the occurrence on "+events[2].SeriesDate+": ticket limits cannot be negative")
+	saved, _ := repos.EventSeriesRepository.FindByID(series.ID)
+	assert.Equal(t, "Evening Choir", saved.Name)
+	assert.Equal(t, 3, saved.Count)
+	assert.Len(t, occurrenceDates(t, repos, series.ID), 3)
+	first, _ := repos.EventRepository.FindByID(events[0].ID)
+	assert.Equal(t, "Evening Choir", first.Name)
+	assert.Equal(t, 40000.0, first.Price)
+
+	// Nothing of the failed edit reached the search index
+	found, _, err := eventService.SearchEvents("late", 1, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestDeleteSeries_RemovesOccurrences(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	seriesService := newTestSeriesService(repos, newTestEventService(repos))
+
+	series := &entity.EventSeries{
+		Name:            "Open Mic",
+		Location:        "Bandung",
+		Capacity:        50,
+		Frequency:       entity.RecurrenceDaily,
+		Interval:        2,
+		StartDate:       time.Now().Add(24 * time.Hour),
+		DurationMinutes: 180,
+		Count:           5,
+	}
+	assert.NoError(t, seriesService.CreateSeries(series))
+	assert.Len(t, occurrenceDates(t, repos, series.ID), 5)
+
+	// Test
+	err := seriesService.DeleteSeries(series.ID)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Empty(t, occurrenceDates(t, repos, series.ID))
+	_, err = seriesService.GetSeriesByID(series.ID)
+	assert.EqualError(t, err, "event series not found")
+}
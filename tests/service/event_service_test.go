@@ -0,0 +1,439 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/dto"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/search"
+	"github.com/taufikmulyawan/ticketing-system/service"
+	"github.com/taufikmulyawan/ticketing-system/storage"
+)
+
+// newTestEventService wires an event service with its own search index
+func newTestEventService(repos *repository.Repositories) service.EventService {
+	return service.NewEventService(repos.EventRepository, repos.VenueRepository, repos.SeatMapRepository, newTestRefundService(repos), service.NewAuditService(repos.AuditRepository), search.NewMemoryIndex(), service.NewFileService(storage.NewLocalDriver("uploads")), repos.Transactor)
+}
+
+func TestAdvanceLifecycle_MovesEventsThroughStages(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	auditService := service.NewAuditService(repos.AuditRepository)
+	eventService := newTestEventService(repos)
+
+	upcoming := createTestEvent(t, 10)
+	started := createTestEvent(t, 10)
+	config.DB.Model(started).Updates(map[string]interface{}{"start_date": time.Now().Add(-time.Hour), "end_date": time.Now().Add(time.Hour)})
+	ended := createTestEvent(t, 10)
+	config.DB.Model(ended).Updates(map[string]interface{}{"start_date": time.Now().Add(-2 * time.Hour), "end_date": time.Now().Add(-time.Hour)})
+	cancelled := createTestEvent(t, 10)
+	config.DB.Model(cancelled).Updates(map[string]interface{}{"status": entity.EventStatusCancelled, "start_date": time.Now().Add(-2 * time.Hour), "end_date": time.Now().Add(-time.Hour)})
+
+	// Test
+	transitions, err := eventService.AdvanceLifecycle(0)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Len(t, transitions, 3)
+
+	statuses := map[uint]entity.EventStatus{}
+	for _, event := range []*entity.Event{upcoming, started, ended, cancelled} {
+		saved, _ := repos.EventRepository.FindByID(event.ID)
+		statuses[event.ID] = saved.Status
+	}
+	assert.Equal(t, entity.EventStatusActive, statuses[upcoming.ID])
+	assert.Equal(t, entity.EventStatusOngoing, statuses[started.ID])
+	assert.Equal(t, entity.EventStatusFinished, statuses[ended.ID])
+	assert.Equal(t, entity.EventStatusCancelled, statuses[cancelled.ID])
+
+	// Both steps of the event that ended are on record
+	logs, err := auditService.GetAuditLogsByEntity("event", ended.ID)
+	assert.NoError(t, err)
+	assert.Len(t, logs, 2)
+
+	// Running again has nothing left to do, and finished events stay locked
+	transitions, err = eventService.AdvanceLifecycle(0)
+	assert.NoError(t, err)
+	assert.Empty(t, transitions)
+	assert.EqualError(t, eventService.UpdateEvent(ended.ID, ended), "cannot update a finished event")
+}
+
+func TestCancelEvent_RefundsTickets(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	eventService := newTestEventService(repos)
+	event := createTestEvent(t, 10)
+
+	paid := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	held := &entity.Ticket{UserID: 2, EventID: event.ID}
+	assert.NoError(t, ticketService.HoldTicket(held))
+
+	// Test
+	cancelled, refunds, err := eventService.CancelEvent(event.ID, "Venue flooded")
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, entity.EventStatusCancelled, cancelled.Status)
+	assert.Equal(t, "Venue flooded", cancelled.StatusReason)
+	assert.Zero(t, cancelled.SoldCount)
+	assert.Len(t, refunds, 1)
+	assert.Equal(t, paid.ID, refunds[0].TicketID)
+	assert.Equal(t, event.Price, refunds[0].Amount)
+
+	savedHold, _ := repos.TicketRepository.FindByID(held.ID)
+	assert.Equal(t, entity.TicketStatusCancelled, savedHold.Status)
+
+	_, _, err = eventService.CancelEvent(event.ID, "Again")
+	assert.EqualError(t, err, "only events that have not started can be cancelled")
+	assert.EqualError(t, eventService.UpdateEvent(event.ID, cancelled), "cannot update a cancelled event")
+}
+
+func TestCancelEvent_ReturnsCancelledEventWhenRefundsFail(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	eventService := newTestEventService(repos)
+	event := createTestEvent(t, 10)
+	purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+
+	// Refunds cannot be recorded without their table
+	assert.NoError(t, config.DB.Migrator().DropTable(&entity.Refund{}))
+
+	// Test
+	cancelled, refunds, err := eventService.CancelEvent(event.ID, "Venue flooded")
+
+	// Assertions
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), fmt.Sprintf("retry with POST /events/%d/refunds", event.ID))
+	assert.Empty(t, refunds)
+	assert.NotNil(t, cancelled)
+	assert.Equal(t, entity.EventStatusCancelled, cancelled.Status)
+	saved, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Equal(t, entity.EventStatusCancelled, saved.Status)
+}
+
+func TestCancelEvent_RejectedRefundsCanBeRetried(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	gateway := &failingRefundGateway{MockPaymentGateway: service.NewMockPaymentGateway(testWebhookSecret), failing: true}
+	refundService := service.NewRefundService(repos.RefundRepository, repos.PolicyRepository, repos.PaymentRepository, repos.TicketRepository, repos.EventRepository, gateway, repos.Transactor)
+	eventService := service.NewEventService(repos.EventRepository, repos.VenueRepository, repos.SeatMapRepository, refundService, service.NewAuditService(repos.AuditRepository), search.NewMemoryIndex(), service.NewFileService(storage.NewLocalDriver("uploads")), repos.Transactor)
+	event := createTestEvent(t, 10)
+	purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+
+	// Test
+	cancelled, refunds, err := eventService.CancelEvent(event.ID, "Venue flooded")
+
+	// Assertions
+	assert.ErrorIs(t, err, service.ErrRefundFailed)
+	assert.Contains(t, err.Error(), fmt.Sprintf("retry with POST /events/%d/refunds", event.ID))
+	assert.Equal(t, entity.EventStatusCancelled, cancelled.Status)
+	assert.Len(t, refunds, 1)
+	assert.Equal(t, entity.RefundStatusFailed, refunds[0].Status)
+
+	// Retrying once the gateway is back pays the ticket back
+	gateway.failing = false
+	refunds, err = refundService.RefundCancelledEvent(event.ID)
+	assert.NoError(t, err)
+	assert.Len(t, refunds, 1)
+	assert.Equal(t, entity.RefundStatusSucceeded, refunds[0].Status)
+}
+
+func TestPostponeEvent_KeepsTicketsAndAllowsOptOut(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	refundService := newTestRefundService(repos)
+	eventService := newTestEventService(repos)
+	event := createTestEvent(t, 10)
+
+	assert.NoError(t, refundService.SetCancellationPolicy(event.ID, []entity.CancellationPolicyRule{
+		{HoursBeforeStart: 0, RefundPercentage: 0},
+	}))
+	keeper := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	leaver := purchasePaidTicket(t, ticketService, paymentService, 2, event.ID)
+
+	newStart := event.StartDate.Add(7 * 24 * time.Hour)
+	_, err := eventService.PostponeEvent(event.ID, event.StartDate.Add(-time.Hour), event.EndDate, "Artist ill")
+	assert.EqualError(t, err, "the new start date must be after the current start date")
+
+	// Test
+	postponed, err := eventService.PostponeEvent(event.ID, newStart, newStart.Add(2*time.Hour), "Artist ill")
+	assert.NoError(t, err)
+	refund, errOptOut := ticketService.CancelTicket(leaver.ID, 2)
+
+	// Assertions
+	assert.Equal(t, entity.EventStatusPostponed, postponed.Status)
+	assert.True(t, postponed.StartDate.Equal(newStart))
+	assert.True(t, postponed.OriginalStartDate.Equal(event.StartDate))
+
+	assert.NoError(t, errOptOut)
+	assert.Equal(t, 100.0, refund.Percentage)
+	assert.Equal(t, event.Price, refund.Amount)
+
+	savedTicket, _ := repos.TicketRepository.FindByID(keeper.ID)
+	assert.Equal(t, entity.TicketStatusPurchased, savedTicket.Status)
+
+	// Postponed events keep selling for the new dates, under the usual policy
+	latecomer := purchasePaidTicket(t, ticketService, paymentService, 3, event.ID)
+	refund, err = ticketService.CancelTicket(latecomer.ID, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, refund.Percentage)
+	assert.Equal(t, 0.0, refund.Amount)
+}
+
+func TestUpdateEvent_CannotChangeStatus(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	eventService := newTestEventService(repos)
+	event := createTestEvent(t, 10)
+	postponed := createTestEvent(t, 10)
+	config.DB.Model(postponed).Update("status", entity.EventStatusPostponed)
+
+	// Test and assertions
+	finished := *event
+	finished.Status = entity.EventStatusFinished
+	assert.EqualError(t, eventService.UpdateEvent(event.ID, &finished), "the event status cannot be updated, use the cancel or postpone endpoint instead")
+
+	reopened := *postponed
+	reopened.Status = entity.EventStatusActive
+	assert.EqualError(t, eventService.UpdateEvent(postponed.ID, &reopened), "the event status cannot be updated, use the cancel or postpone endpoint instead")
+
+	// Leaving the status out keeps it
+	renamed := *postponed
+	renamed.Name = "Renamed Show"
+	renamed.Status = ""
+	assert.NoError(t, eventService.UpdateEvent(postponed.ID, &renamed))
+	saved, _ := repos.EventRepository.FindByID(postponed.ID)
+	assert.Equal(t, entity.EventStatusPostponed, saved.Status)
+	assert.Equal(t, "Renamed Show", saved.Name)
+}
+
+func TestGetAllEvents_FiltersAndSorts(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	eventService := newTestEventService(repos)
+
+	newEvent := func(name, location string, days int, price float64, capacity, sold int) *entity.Event {
+		event := &entity.Event{
+			Name:      name,
+			Location:  location,
+			StartDate: time.Now().Add(time.Duration(days) * 24 * time.Hour),
+			EndDate:   time.Now().Add(time.Duration(days)*24*time.Hour + 2*time.Hour),
+			Capacity:  capacity,
+			Price:     price,
+			SoldCount: sold,
+			Status:    entity.EventStatusActive,
+		}
+		if err := config.DB.Create(event).Error; err != nil {
+			t.Fatal(err)
+		}
+		return event
+	}
+	jazz := newEvent("Jazz Night", "Jakarta Convention Center", 10, 150000, 100, 40)
+	rock := newEvent("Rock Festival", "Bandung", 20, 300000, 50, 50)
+	indie := newEvent("Indie_Jazz 100%", "Jakarta", 5, 75000, 100, 90)
+
+	names := func(filter dto.EventFilterRequest) []string {
+		events, _, err := eventService.GetAllEvents(1, 10, filter)
+		assert.NoError(t, err)
+		result := make([]string, 0, len(events))
+		for _, event := range events {
+			result = append(result, event.Name)
+		}
+		return result
+	}
+	maxPrice := 200000.0
+
+	// Test and assertions
+	assert.Equal(t, []string{indie.Name, jazz.Name, rock.Name}, names(dto.EventFilterRequest{}))
+	assert.Equal(t, []string{indie.Name, jazz.Name}, names(dto.EventFilterRequest{Name: "jazz"}))
+	assert.Equal(t, []string{indie.Name}, names(dto.EventFilterRequest{Name: "_Jazz 100%"}))
+	assert.Equal(t, []string{indie.Name, jazz.Name}, names(dto.EventFilterRequest{Location: "Jakarta"}))
+	assert.Equal(t, []string{indie.Name, jazz.Name}, names(dto.EventFilterRequest{MaxPrice: &maxPrice}))
+	assert.Equal(t, []string{indie.Name, jazz.Name}, names(dto.EventFilterRequest{Available: true}))
+	assert.Equal(t, []string{jazz.Name}, names(dto.EventFilterRequest{
+		StartDate: time.Now().Add(7 * 24 * time.Hour).Format("2006-01-02"),
+		EndDate:   time.Now().Add(15 * 24 * time.Hour).Format("2006-01-02"),
+	}))
+	assert.Equal(t, []string{rock.Name, jazz.Name, indie.Name}, names(dto.EventFilterRequest{Sort: "-price"}))
+	assert.Equal(t, []string{indie.Name, rock.Name, jazz.Name}, names(dto.EventFilterRequest{Sort: "popularity"}))
+	assert.Empty(t, names(dto.EventFilterRequest{Status: string(entity.EventStatusCancelled)}))
+
+	_, _, err := eventService.GetAllEvents(1, 10, dto.EventFilterRequest{Sort: "name"})
+	assert.ErrorIs(t, err, service.ErrInvalidEventFilter)
+	_, _, err = eventService.GetAllEvents(1, 10, dto.EventFilterRequest{StartDate: "next week"})
+	assert.ErrorIs(t, err, service.ErrInvalidEventFilter)
+}
+
+func TestSearchEvents_RanksAndToleratesTypos(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	eventService := newTestEventService(repos)
+
+	newEvent := func(name, location, description string) *entity.Event {
+		event := &entity.Event{
+			Name:        name,
+			Location:    location,
+			Description: description,
+			StartDate:   time.Now().Add(48 * time.Hour),
+			EndDate:     time.Now().Add(50 * time.Hour),
+			Capacity:    100,
+			Price:       100000,
+		}
+		assert.NoError(t, eventService.CreateEvent(event))
+		return event
+	}
+	jazz := newEvent("Jakarta Jazz Weekend", "JIExpo Kemayoran, Jakarta", "Three days of jazz")
+	blues := newEvent("Blues Night", "Bandung", "Jazz and blues classics")
+	rock := newEvent("Rock Festival", "Jakarta", "Loud guitars")
+
+	names := func(query string) []string {
+		events, _, err := eventService.SearchEvents(query, 1, 10)
+		assert.NoError(t, err)
+		result := make([]string, 0, len(events))
+		for _, event := range events {
+			result = append(result, event.Name)
+		}
+		return result
+	}
+
+	// Test and assertions - a location match outweighs a description match
+	assert.Equal(t, []string{jazz.Name, rock.Name, blues.Name}, names("jazz jakarta weekend"))
+	assert.Equal(t, []string{jazz.Name, blues.Name}, names("jaz"))
+	assert.Equal(t, []string{rock.Name}, names("festvial"))
+	assert.Empty(t, names("opera"))
+
+	// The index follows updates and deletes
+	rock.Name = "Opera Gala"
+	assert.NoError(t, eventService.UpdateEvent(rock.ID, rock))
+	assert.Equal(t, []string{"Opera Gala"}, names("opera"))
+	assert.Empty(t, names("festival"))
+
+	assert.NoError(t, eventService.DeleteEvent(blues.ID))
+	assert.Equal(t, []string{jazz.Name}, names("jazz"))
+
+	// A fresh index is filled from the database
+	rebuilt := newTestEventService(repos)
+	assert.NoError(t, rebuilt.RebuildSearchIndex())
+	events, total, err := rebuilt.SearchEvents("gala", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, rock.ID, events[0].ID)
+}
+
+func TestPublishEvent_DraftsStayHiddenAndOffSale(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	eventService := newTestEventService(repos)
+	ticketService, _ := newTestTicketService(repos)
+
+	later := time.Now().Add(24 * time.Hour)
+	newEvent := func(name string, change func(event *entity.Event)) *entity.Event {
+		event := &entity.Event{
+			Name:      name,
+			Location:  "Jakarta",
+			StartDate: time.Now().Add(48 * time.Hour),
+			EndDate:   time.Now().Add(50 * time.Hour),
+			Capacity:  10,
+			Price:     100000,
+		}
+		change(event)
+		assert.NoError(t, eventService.CreateEvent(event))
+		return event
+	}
+	draft := newEvent("Secret Show", func(event *entity.Event) { event.Draft = true })
+	scheduled := newEvent("Announced Tomorrow", func(event *entity.Event) { event.PublishAt = &later })
+	public := newEvent("Open Show", func(event *entity.Event) {})
+
+	names := func(filter dto.EventFilterRequest) []string {
+		events, _, err := eventService.GetAllEvents(1, 10, filter)
+		assert.NoError(t, err)
+		result := make([]string, 0, len(events))
+		for _, event := range events {
+			result = append(result, event.Name)
+		}
+		return result
+	}
+
+	// Test and assertions - only admins see drafts and scheduled events
+	assert.Equal(t, []string{public.Name}, names(dto.EventFilterRequest{}))
+	assert.ElementsMatch(t, []string{draft.Name, scheduled.Name, public.Name}, names(dto.EventFilterRequest{Drafts: true}))
+	found, _, err := eventService.SearchEvents("show", 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: draft.ID})
+	assert.EqualError(t, err, "event is not published")
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: scheduled.ID})
+	assert.EqualError(t, err, "event is not published")
+
+	// Their tiers are hidden from the public as well
+	tierService := service.NewTicketTierService(repos.TierRepository, repos.EventRepository, repos.Transactor)
+	_, err = tierService.GetEventTiers(scheduled.ID, false)
+	assert.EqualError(t, err, "event not found")
+	_, err = tierService.GetEventTiers(scheduled.ID, true)
+	assert.NoError(t, err)
+
+	// Publishing puts the draft on sale right away
+	published, err := eventService.PublishEvent(draft.ID, nil)
+	assert.NoError(t, err)
+	assert.False(t, published.Draft)
+	assert.ElementsMatch(t, []string{draft.Name, public.Name}, names(dto.EventFilterRequest{}))
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: draft.ID})
+	assert.NoError(t, err)
+
+	// Buyers must keep finding an event they hold tickets for
+	_, err = eventService.PublishEvent(draft.ID, &later)
+	assert.EqualError(t, err, "cannot unpublish an event with tickets sold")
+	published.Draft = true
+	assert.EqualError(t, eventService.UpdateEvent(draft.ID, published), "cannot unpublish an event with tickets sold")
+}
+
+func TestPurchaseTicket_EnforcesSalesWindow(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	eventService := newTestEventService(repos)
+	ticketService, _ := newTestTicketService(repos)
+
+	at := func(hours int) *time.Time {
+		moment := time.Now().Add(time.Duration(hours) * time.Hour)
+		return &moment
+	}
+	newEvent := func(name string, salesStart, salesEnd *time.Time) (*entity.Event, error) {
+		event := &entity.Event{
+			Name:       name,
+			Location:   "Jakarta",
+			StartDate:  *at(48),
+			EndDate:    *at(50),
+			Capacity:   10,
+			Price:      100000,
+			SalesStart: salesStart,
+			SalesEnd:   salesEnd,
+		}
+		return event, eventService.CreateEvent(event)
+	}
+	notYet, _ := newEvent("Presale Next Week", at(24), nil)
+	closed, _ := newEvent("Sales Closed", at(-48), at(-1))
+	open, _ := newEvent("On Sale", at(-1), at(24))
+
+	// Test and assertions
+	_, err := ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: notYet.ID})
+	assert.EqualError(t, err, "ticket sales for this event have not started")
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: closed.ID})
+	assert.EqualError(t, err, "ticket sales for this event have ended")
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: open.ID})
+	assert.NoError(t, err)
+
+	_, err = newEvent("Backwards", at(24), at(12))
+	assert.EqualError(t, err, "sales_end must be after sales_start")
+	_, err = newEvent("Too Late", nil, at(49))
+	assert.EqualError(t, err, "sales_end cannot be after the event starts")
+}
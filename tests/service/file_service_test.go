@@ -2,14 +2,24 @@ package tests
 
 import (
 	"bytes"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/config"
 	"github.com/taufikmulyawan/ticketing-system/service"
+	"github.com/taufikmulyawan/ticketing-system/storage"
 )
 
 func TestFileService_UploadFile(t *testing.T) {
@@ -26,12 +36,8 @@ func TestFileService_UploadFile(t *testing.T) {
 		os.MkdirAll(fullPath, 0755)
 	}
 
-	// Override the upload directory for testing
-	os.Setenv("UPLOAD_DIR", tempDir)
-	defer os.Unsetenv("UPLOAD_DIR")
-
-	// Create the file service
-	fileService := service.NewFileService()
+	// Create the file service with the upload directory for testing
+	fileService := service.NewFileService(storage.NewLocalDriver(tempDir))
 
 	// Create a test file
 	fileContents := []byte("test file content")
@@ -63,22 +69,22 @@ func TestFileService_UploadFile(t *testing.T) {
 	assert.Empty(t, filename)
 }
 
-func TestFileService_GetFilePath(t *testing.T) {
+func TestFileService_OpenFile(t *testing.T) {
 	// Create the file service
-	fileService := service.NewFileService()
+	fileService := service.NewFileService(storage.NewLocalDriver(t.TempDir()))
 
-	// Test getting file path
-	filePath, err := fileService.GetFilePath("non-existent-file.txt", "events")
+	// Test opening a file
+	file, err := fileService.OpenFile("non-existent-file.txt", "events")
 	
 	// Assertions
 	// In a real environment this would fail because the file doesn't exist
 	assert.Error(t, err)
-	assert.Empty(t, filePath)
+	assert.Nil(t, file)
 }
 
 func TestFileService_DeleteFile(t *testing.T) {
 	// Create the file service
-	fileService := service.NewFileService()
+	fileService := service.NewFileService(storage.NewLocalDriver(t.TempDir()))
 
 	// Test deleting a file
 	err := fileService.DeleteFile("non-existent-file.txt", "events")
@@ -86,4 +92,149 @@ func TestFileService_DeleteFile(t *testing.T) {
 	// Assertions
 	// In a real environment this would fail because the file doesn't exist
 	assert.Error(t, err)
-} 
\ No newline at end of file
+} 
+func TestFileService_LocalStorageRoundTrip(t *testing.T) {
+	// Setup
+	uploadDir := t.TempDir()
+	fileService := service.NewFileService(storage.NewLocalDriver(uploadDir))
+
+	// Test
+	filename, err := fileService.UploadFile(imageUpload(t, "poster.png"), "events")
+
+	// Assertions
+	assert.NoError(t, err)
+	stored, err := os.ReadFile(filepath.Join(uploadDir, "events", filename))
+	assert.NoError(t, err)
+	assert.Equal(t, "image content", string(stored))
+
+	file, err := fileService.OpenFile(filename, "events")
+	assert.NoError(t, err)
+	content, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, "image content", string(content))
+
+	url, err := fileService.FileURL(filename, "events")
+	assert.NoError(t, err)
+	assert.Equal(t, "/files/events/"+filename, url)
+
+	// Names cannot reach outside their type
+	_, err = fileService.OpenFile("../events/"+filename, "profiles")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	assert.NoError(t, fileService.DeleteFile(filename, "events"))
+	assert.ErrorIs(t, fileService.DeleteFile(filename, "events"), storage.ErrNotFound)
+}
+
+// s3StandIn is a minimal in-memory stand-in for a MinIO style S3 service. It
+// checks that requests carry credentials for the expected access key but does
+// not verify signatures.
+type s3StandIn struct {
+	mu           sync.Mutex
+	accessKey    string
+	objects      map[string][]byte
+	contentTypes map[string]string
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	credential := "Credential=" + s.accessKey + "/"
+	switch {
+	case query.Get("X-Amz-Signature") != "":
+		signedAt, _ := time.Parse("20060102T150405Z", query.Get("X-Amz-Date"))
+		expires, _ := strconv.Atoi(query.Get("X-Amz-Expires"))
+		if r.Method != http.MethodGet || !strings.HasPrefix("Credential="+query.Get("X-Amz-Credential"), credential) || time.Now().After(signedAt.Add(time.Duration(expires)*time.Second)) {
+			s.fail(w, http.StatusForbidden, "AccessDenied")
+			return
+		}
+	case !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 "+credential) || r.Header.Get("X-Amz-Content-Sha256") == "":
+		s.fail(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	object, exists := s.objects[r.URL.Path]
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		s.objects[r.URL.Path] = body
+		s.contentTypes[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet, http.MethodHead:
+		if !exists {
+			s.fail(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Write(object)
+	case http.MethodDelete:
+		delete(s.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *s3StandIn) fail(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>request refused</Message></Error>", code)
+}
+
+func TestFileService_S3StorageAgainstStandIn(t *testing.T) {
+	// Setup
+	standIn := &s3StandIn{accessKey: "minio-access", objects: map[string][]byte{}, contentTypes: map[string]string{}}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	expiry := config.AppConfig.StorageURLExpiry
+	config.AppConfig.StorageURLExpiry = 15 * time.Minute
+	defer func() { config.AppConfig.StorageURLExpiry = expiry }()
+
+	driver, err := storage.NewDriver(config.Config{
+		StorageDriver: "s3",
+		S3Endpoint:    server.URL,
+		S3Bucket:      "ticketing",
+		S3AccessKey:   "minio-access",
+		S3SecretKey:   "minio-secret",
+	})
+	assert.NoError(t, err)
+	fileService := service.NewFileService(driver)
+
+	// Test
+	filename, err := fileService.UploadFile(imageUpload(t, "poster.png"), "events")
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "image content", string(standIn.objects["/ticketing/events/"+filename]))
+	assert.Equal(t, "image/png", standIn.contentTypes["/ticketing/events/"+filename])
+
+	file, err := fileService.OpenFile(filename, "events")
+	assert.NoError(t, err)
+	content, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, "image content", string(content))
+
+	// The presigned URL downloads without credentials
+	url, err := fileService.FileURL(filename, "events")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(url, server.URL+"/ticketing/events/"+filename+"?"))
+	assert.Contains(t, url, "X-Amz-Expires=900")
+	resp, err := http.Get(url)
+	assert.NoError(t, err)
+	content, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "image content", string(content))
+
+	assert.NoError(t, fileService.DeleteFile(filename, "events"))
+	assert.ErrorIs(t, fileService.DeleteFile(filename, "events"), storage.ErrNotFound)
+	_, err = fileService.OpenFile(filename, "events")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Refusals from the service are reported with their code
+	intruder, _ := storage.NewS3Driver(storage.S3Options{Endpoint: server.URL, Bucket: "ticketing", AccessKey: "intruder", SecretKey: "guess"})
+	_, err = service.NewFileService(intruder).UploadFile(imageUpload(t, "poster.png"), "events")
+	assert.ErrorContains(t, err, "AccessDenied")
+
+	// Incomplete configuration is rejected up front
+	_, err = storage.NewDriver(config.Config{StorageDriver: "s3", S3Endpoint: server.URL})
+	assert.EqualError(t, err, "the S3 bucket is required")
+	_, err = storage.NewDriver(config.Config{StorageDriver: "ftp"})
+	assert.EqualError(t, err, `unknown storage driver "ftp", use local or s3`)
+}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/middleware"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+func TestIdempotencyMiddleware_ReplaysRetries(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	idempotencyService := service.NewIdempotencyService(repos.IdempotencyRepository)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	calls := 0
+	router.POST("/tickets", func(c *gin.Context) {
+		c.Set("user_id", float64(1))
+	}, middleware.IdempotencyMiddleware(idempotencyService), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"ticket_id": calls})
+	})
+
+	send := func(key string, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/tickets", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Test
+	first := send("retry-1", `{"event_id":1}`)
+	retry := send("retry-1", `{"event_id":1}`)
+	reused := send("retry-1", `{"event_id":2}`)
+	withoutKey := send("", `{"event_id":1}`)
+
+	// Assertions
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, http.StatusUnprocessableEntity, reused.Code)
+	assert.Equal(t, http.StatusCreated, withoutKey.Code)
+	assert.Equal(t, 2, calls)
+
+	// An expired key can be used again
+	config.DB.Model(&entity.IdempotencyKey{}).Where("1 = 1").Update("expires_at", time.Now().Add(-time.Second))
+	again := send("retry-1", `{"event_id":2}`)
+	assert.Equal(t, http.StatusCreated, again.Code)
+	assert.Equal(t, 3, calls)
+}
+
+func TestIdempotencyService_KeysInProgressAndPurge(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	idempotencyService := service.NewIdempotencyService(repos.IdempotencyRepository)
+
+	record, err := idempotencyService.Begin(1, "order-1", "hash")
+	assert.NoError(t, err)
+
+	// Test
+	_, errInProgress := idempotencyService.Begin(1, "order-1", "hash")
+	otherUser, errOtherUser := idempotencyService.Begin(2, "order-1", "hash")
+
+	// Assertions
+	assert.ErrorIs(t, errInProgress, service.ErrIdempotencyKeyInProgress)
+	assert.NoError(t, errOtherUser)
+	assert.NotEqual(t, record.ID, otherUser.ID)
+
+	// A released key can be retried
+	assert.NoError(t, idempotencyService.Release(record))
+	retried, err := idempotencyService.Begin(1, "order-1", "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.IdempotencyStatusProcessing, retried.Status)
+
+	// Completed keys are kept until their TTL runs out
+	assert.NoError(t, idempotencyService.Complete(retried, http.StatusCreated, []byte(`{}`)))
+	config.DB.Model(&entity.IdempotencyKey{}).Where("id = ?", otherUser.ID).Update("expires_at", time.Now().Add(-time.Second))
+	assert.NoError(t, idempotencyService.PurgeExpired())
+
+	var remaining []entity.IdempotencyKey
+	config.DB.Find(&remaining)
+	assert.Len(t, remaining, 1)
+	assert.Equal(t, entity.IdempotencyStatusCompleted, remaining[0].Status)
+}
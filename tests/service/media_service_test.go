@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"bytes"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/service"
+	"github.com/taufikmulyawan/ticketing-system/storage"
+)
+
+// newTestMediaService wires a media service whose uploads go to the returned temporary directory
+func newTestMediaService(t *testing.T, repos *repository.Repositories) (service.MediaService, string) {
+	uploadDir := t.TempDir()
+	fileService := service.NewFileService(storage.NewLocalDriver(uploadDir))
+	return service.NewMediaService(repos.MediaRepository, repos.EventRepository, fileService, repos.Transactor), uploadDir
+}
+
+// imageUpload builds the multipart file header of an uploaded image
+func imageUpload(t *testing.T, filename string) *multipart.FileHeader {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte("image content"))
+	writer.Close()
+
+	form, err := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return form.File["file"][0]
+}
+
+func TestAddMedia_BuildsEventGallery(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	mediaService, uploadDir := newTestMediaService(t, repos)
+	eventService := newTestEventService(repos)
+	event := createTestEvent(t, 100)
+
+	// Test
+	stage, err := mediaService.AddMedia(event.ID, imageUpload(t, "stage.jpg"), " Main stage ", false)
+	assert.NoError(t, err)
+	crowd, err := mediaService.AddMedia(event.ID, imageUpload(t, "crowd.png"), "", false)
+	assert.NoError(t, err)
+	poster, err := mediaService.AddMedia(event.ID, imageUpload(t, "poster.gif"), "Poster", true)
+	assert.NoError(t, err)
+
+	// Assertions
+	assert.Equal(t, "Main stage", stage.Caption)
+	assert.Equal(t, "/files/events/"+stage.Filename, stage.URL)
+	_, err = os.Stat(filepath.Join(uploadDir, "events", stage.Filename))
+	assert.NoError(t, err)
+
+	_, err = mediaService.AddMedia(event.ID, imageUpload(t, "notes.pdf"), "", false)
+	assert.EqualError(t, err, "only JPG, PNG and GIF images can be added to an event")
+	_, err = mediaService.AddMedia(999, imageUpload(t, "stage.jpg"), "", false)
+	assert.EqualError(t, err, "event not found")
+
+	// The event carries its gallery in order, with the last cover chosen
+	loaded, err := eventService.GetEventByID(event.ID)
+	assert.NoError(t, err)
+	assert.Len(t, loaded.Media, 3)
+	assert.Equal(t, []uint{stage.ID, crowd.ID, poster.ID}, []uint{loaded.Media[0].ID, loaded.Media[1].ID, loaded.Media[2].ID})
+	assert.Equal(t, []bool{false, false, true}, []bool{loaded.Media[0].IsCover, loaded.Media[1].IsCover, loaded.Media[2].IsCover})
+	assert.Equal(t, "/files/events/"+crowd.Filename, loaded.Media[1].URL)
+}
+
+func TestReorderAndDeleteMedia(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	mediaService, uploadDir := newTestMediaService(t, repos)
+	event := createTestEvent(t, 100)
+	other := createTestEvent(t, 100)
+
+	first, _ := mediaService.AddMedia(event.ID, imageUpload(t, "first.jpg"), "", false)
+	second, _ := mediaService.AddMedia(event.ID, imageUpload(t, "second.jpg"), "", false)
+	third, _ := mediaService.AddMedia(event.ID, imageUpload(t, "third.jpg"), "", false)
+	elsewhere, _ := mediaService.AddMedia(other.ID, imageUpload(t, "other.jpg"), "", false)
+	assert.True(t, first.IsCover)
+	assert.True(t, elsewhere.IsCover)
+
+	// Test: reordering
+	media, err := mediaService.ReorderMedia(event.ID, []uint{third.ID, first.ID, second.ID})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{third.ID, first.ID, second.ID}, []uint{media[0].ID, media[1].ID, media[2].ID})
+	_, err = mediaService.ReorderMedia(event.ID, []uint{third.ID, first.ID})
+	assert.EqualError(t, err, "the new order has to list every image of the event exactly once")
+	_, err = mediaService.ReorderMedia(event.ID, []uint{third.ID, first.ID, elsewhere.ID})
+	assert.EqualError(t, err, "media not found")
+
+	// Moving the cover flag leaves a single cover
+	updated, err := mediaService.UpdateMedia(event.ID, second.ID, &entity.Media{Caption: "Backstage", IsCover: true})
+	assert.NoError(t, err)
+	assert.True(t, updated.IsCover)
+	assert.Equal(t, "Backstage", updated.Caption)
+	previous, _ := mediaService.GetMediaByID(event.ID, first.ID)
+	assert.False(t, previous.IsCover)
+	_, err = mediaService.GetMediaByID(event.ID, elsewhere.ID)
+	assert.EqualError(t, err, "media not found")
+
+	// Test: deleting the cover hands it to the first remaining image
+	err = mediaService.DeleteMedia(event.ID, second.ID)
+
+	// Assertions
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(uploadDir, "events", second.Filename))
+	assert.True(t, os.IsNotExist(err))
+	newCover, _ := mediaService.GetMediaByID(event.ID, third.ID)
+	assert.True(t, newCover.IsCover)
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+func TestCreateOrder_Success(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	_, paymentService := newTestTicketService(repos)
+	orderService := service.NewOrderService(repos.OrderRepository, repos.EventRepository, repos.TierRepository, repos.SeatMapRepository, paymentService, repos.Transactor)
+	concert := createTestEvent(t, 10)
+	workshop := createTestEvent(t, 10)
+
+	order := &entity.Order{
+		UserID: 1,
+		Items: []entity.OrderItem{
+			{EventID: concert.ID, Quantity: 3},
+			{EventID: workshop.ID, Quantity: 2},
+		},
+	}
+
+	// Test
+	payment, err := orderService.CreateOrder(order)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusPending, order.Status)
+	assert.Equal(t, 500000.0, order.TotalAmount)
+	assert.Equal(t, order.TotalAmount, payment.Amount)
+	assert.Len(t, order.Tickets, 5)
+
+	assert.NoError(t, sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded))
+
+	savedOrder, err := orderService.GetOrderByID(order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusCompleted, savedOrder.Status)
+	assert.Len(t, savedOrder.Items, 2)
+	assert.Len(t, savedOrder.Tickets, 5)
+	for _, ticket := range savedOrder.Tickets {
+		assert.Equal(t, entity.TicketStatusPurchased, ticket.Status)
+	}
+}
+
+func TestCreateOrder_AllOrNothing(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	_, paymentService := newTestTicketService(repos)
+	orderService := service.NewOrderService(repos.OrderRepository, repos.EventRepository, repos.TierRepository, repos.SeatMapRepository, paymentService, repos.Transactor)
+	concert := createTestEvent(t, 10)
+	workshop := createTestEvent(t, 1)
+
+	order := &entity.Order{
+		UserID: 1,
+		Items: []entity.OrderItem{
+			{EventID: concert.ID, Quantity: 3},
+			{EventID: workshop.ID, Quantity: 2},
+		},
+	}
+
+	// Test
+	_, err := orderService.CreateOrder(order)
+
+	// Assertions
+	assert.EqualError(t, err, "event is sold out")
+
+	var ticketCount, orderCount int64
+	config.DB.Model(&entity.Ticket{}).Count(&ticketCount)
+	config.DB.Model(&entity.Order{}).Count(&orderCount)
+	assert.Zero(t, ticketCount)
+	assert.Zero(t, orderCount)
+
+	savedConcert, _ := repos.EventRepository.FindByID(concert.ID)
+	assert.Zero(t, savedConcert.SoldCount)
+}
+
+func TestCreateOrder_PurchaseLimits(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	_, paymentService := newTestTicketService(repos)
+	orderService := service.NewOrderService(repos.OrderRepository, repos.EventRepository, repos.TierRepository, repos.SeatMapRepository, paymentService, repos.Transactor)
+	event := createTestEvent(t, 10)
+	config.DB.Model(event).Updates(map[string]interface{}{"max_tickets_per_user": 4, "max_tickets_per_order": 3})
+
+	newOrder := func(quantities ...int) *entity.Order {
+		order := &entity.Order{UserID: 1}
+		for _, quantity := range quantities {
+			order.Items = append(order.Items, entity.OrderItem{EventID: event.ID, Quantity: quantity})
+		}
+		return order
+	}
+
+	// Test
+	_, errTooLarge := orderService.CreateOrder(newOrder(2, 2))
+	_, errFirst := orderService.CreateOrder(newOrder(3))
+	_, errOverLimit := orderService.CreateOrder(newOrder(2))
+	_, errLast := orderService.CreateOrder(newOrder(1))
+
+	// Assertions
+	assert.ErrorIs(t, errTooLarge, service.ErrOrderLimitExceeded)
+	assert.NoError(t, errFirst)
+	assert.ErrorIs(t, errOverLimit, service.ErrUserLimitReached)
+	assert.NoError(t, errLast)
+
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Equal(t, 4, savedEvent.SoldCount)
+}
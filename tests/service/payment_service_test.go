@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+func TestHandleWebhook_SuccessPurchasesTicket(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	event := createTestEvent(t, 1)
+
+	ticket := &entity.Ticket{UserID: 1, EventID: event.ID}
+	payment, err := ticketService.PurchaseTicket(ticket)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.PaymentStatusPending, payment.Status)
+
+	heldTicket, _ := repos.TicketRepository.FindByID(ticket.ID)
+	assert.Equal(t, entity.TicketStatusReserved, heldTicket.Status)
+
+	// Test
+	err = sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded)
+
+	// Assertions
+	assert.NoError(t, err)
+	savedTicket, _ := repos.TicketRepository.FindByID(ticket.ID)
+	assert.Equal(t, entity.TicketStatusPurchased, savedTicket.Status)
+	savedPayment, _ := paymentService.GetPaymentByID(payment.ID)
+	assert.Equal(t, entity.PaymentStatusSucceeded, savedPayment.Status)
+
+	// Repeated notifications are ignored
+	assert.NoError(t, sendPaymentWebhook(paymentService, payment, service.PaymentEventFailed))
+	savedTicket, _ = repos.TicketRepository.FindByID(ticket.ID)
+	assert.Equal(t, entity.TicketStatusPurchased, savedTicket.Status)
+}
+
+func TestHandleWebhook_RejectsInvalidSignature(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	event := createTestEvent(t, 1)
+
+	ticket := &entity.Ticket{UserID: 1, EventID: event.ID}
+	payment, err := ticketService.PurchaseTicket(ticket)
+	assert.NoError(t, err)
+
+	// Test
+	payload := []byte(`{"intent_id":"` + payment.IntentID + `","status":"succeeded","amount":100000}`)
+	err = paymentService.HandleWebhook(payload, service.NewMockPaymentGateway("wrong-secret").SignPayload(payload))
+
+	// Assertions
+	assert.EqualError(t, err, "invalid webhook signature")
+	savedTicket, _ := repos.TicketRepository.FindByID(ticket.ID)
+	assert.Equal(t, entity.TicketStatusReserved, savedTicket.Status)
+}
+
+func TestHandleWebhook_FailureReleasesSeat(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	event := createTestEvent(t, 1)
+
+	ticket := &entity.Ticket{UserID: 1, EventID: event.ID}
+	payment, err := ticketService.PurchaseTicket(ticket)
+	assert.NoError(t, err)
+
+	// Test
+	err = sendPaymentWebhook(paymentService, payment, service.PaymentEventFailed)
+
+	// Assertions
+	assert.NoError(t, err)
+	savedTicket, _ := repos.TicketRepository.FindByID(ticket.ID)
+	assert.Equal(t, entity.TicketStatusCancelled, savedTicket.Status)
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Zero(t, savedEvent.SoldCount)
+}
+
+func TestHandleWebhook_RefundsAfterHoldExpired(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	config.AppConfig.TicketHoldDuration = time.Millisecond
+	event := createTestEvent(t, 1)
+
+	ticket := &entity.Ticket{UserID: 1, EventID: event.ID}
+	payment, err := ticketService.PurchaseTicket(ticket)
+	assert.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	// Test
+	err = sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded)
+
+	// Assertions
+	assert.NoError(t, err)
+	savedPayment, _ := paymentService.GetPaymentByID(payment.ID)
+	assert.Equal(t, entity.PaymentStatusRefunded, savedPayment.Status)
+	savedTicket, _ := repos.TicketRepository.FindByID(ticket.ID)
+	assert.Equal(t, entity.TicketStatusCancelled, savedTicket.Status)
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Zero(t, savedEvent.SoldCount)
+}
+
+func TestNewPaymentGateway_RequiresGatewayAndSecret(t *testing.T) {
+	// Test and assertions
+	_, err := service.NewPaymentGateway(config.Config{PaymentGateway: "mock"})
+	assert.EqualError(t, err, "PAYMENT_WEBHOOK_SECRET is required to verify payment webhooks")
+	_, err = service.NewPaymentGateway(config.Config{PaymentWebhookSecret: testWebhookSecret})
+	assert.EqualError(t, err, "PAYMENT_GATEWAY is required, use mock for local development")
+	_, err = service.NewPaymentGateway(config.Config{PaymentGateway: "stripe", PaymentWebhookSecret: testWebhookSecret})
+	assert.EqualError(t, err, `unknown payment gateway "stripe", use mock`)
+
+	gateway, err := service.NewPaymentGateway(config.Config{PaymentGateway: "mock", PaymentWebhookSecret: testWebhookSecret})
+	assert.NoError(t, err)
+	assert.Equal(t, "mock", gateway.Name())
+
+	// A gateway without a secret rejects even correctly signed webhooks
+	unsecured := service.NewMockPaymentGateway("")
+	payload := []byte(`{"intent_id":"mock_pi_payment-1","status":"succeeded","amount":100000}`)
+	_, err = unsecured.VerifyWebhook(payload, unsecured.SignPayload(payload))
+	assert.EqualError(t, err, "webhook secret is not configured")
+}
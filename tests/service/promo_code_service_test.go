@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+func createTestPromoCode(t *testing.T, repos *repository.Repositories, promoCode *entity.PromoCode) {
+	promoCodeService := service.NewPromoCodeService(repos.PromoCodeRepository, repos.EventRepository)
+	if err := promoCodeService.CreatePromoCode(promoCode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPurchaseTicket_PromoCodeLimits(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, _ := newTestTicketService(repos)
+	event := createTestEvent(t, 10)
+	otherEvent := createTestEvent(t, 10)
+	createTestPromoCode(t, repos, &entity.PromoCode{
+		Code:           "early20",
+		DiscountType:   entity.DiscountTypePercentage,
+		DiscountValue:  20,
+		EventID:        &event.ID,
+		MaxUses:        2,
+		MaxUsesPerUser: 1,
+	})
+
+	// Test
+	ticket := &entity.Ticket{UserID: 1, EventID: event.ID, PromoCode: "EARLY20"}
+	payment, err := ticketService.PurchaseTicket(ticket)
+	_, errPerUser := ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: event.ID, PromoCode: "EARLY20"})
+	_, errOtherEvent := ticketService.PurchaseTicket(&entity.Ticket{UserID: 2, EventID: otherEvent.ID, PromoCode: "EARLY20"})
+	_, errSecond := ticketService.PurchaseTicket(&entity.Ticket{UserID: 2, EventID: event.ID, PromoCode: "EARLY20"})
+	_, errExhausted := ticketService.PurchaseTicket(&entity.Ticket{UserID: 3, EventID: event.ID, PromoCode: "EARLY20"})
+	_, errUnknown := ticketService.PurchaseTicket(&entity.Ticket{UserID: 3, EventID: event.ID, PromoCode: "NOPE"})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 80000.0, payment.Amount)
+	assert.Equal(t, 20000.0, ticket.Discount)
+	assert.Equal(t, 80000.0, ticket.PricePaid)
+	assert.EqualError(t, errPerUser, "you have already used this promo code the maximum number of times")
+	assert.EqualError(t, errOtherEvent, "promo code does not apply to this event")
+	assert.NoError(t, errSecond)
+	assert.EqualError(t, errExhausted, "promo code has reached its usage limit")
+	assert.EqualError(t, errUnknown, "invalid promo code")
+
+	// Rejected codes do not keep the seat they were trying to buy
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Equal(t, 2, savedEvent.SoldCount)
+}
+
+func TestPurchaseTicket_PromoCodeValidityAndRelease(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, _ := newTestTicketService(repos)
+	event := createTestEvent(t, 10)
+	startsTomorrow := time.Now().Add(24 * time.Hour)
+	createTestPromoCode(t, repos, &entity.PromoCode{Code: "LATER", DiscountType: entity.DiscountTypeFixed, DiscountValue: 10000, ValidFrom: &startsTomorrow})
+	createTestPromoCode(t, repos, &entity.PromoCode{Code: "ONCE", DiscountType: entity.DiscountTypeFixed, DiscountValue: 250000, MaxUses: 1})
+
+	// Test
+	_, errNotYet := ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: event.ID, PromoCode: "later"})
+	held := &entity.Ticket{UserID: 1, EventID: event.ID, PromoCode: "once"}
+	errHold := ticketService.HoldTicket(held)
+	errRelease := ticketService.ReleaseHold(held.ID, 1)
+	reused := &entity.Ticket{UserID: 2, EventID: event.ID, PromoCode: "ONCE"}
+	_, errReuse := ticketService.PurchaseTicket(reused)
+
+	// Assertions
+	assert.EqualError(t, errNotYet, "promo code is not valid yet")
+	assert.NoError(t, errHold)
+	assert.NoError(t, errRelease)
+	assert.NoError(t, errReuse)
+
+	// A fixed discount never takes the price below zero
+	assert.Equal(t, 100000.0, reused.Discount)
+	assert.Zero(t, reused.PricePaid)
+
+	promoCode, _ := repos.PromoCodeRepository.FindByCode("ONCE")
+	assert.Equal(t, 1, promoCode.UsedCount)
+}
+
+func TestSalesReport_DeductsDiscounts(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	reportService := service.NewReportService(repos.TicketRepository, repos.EventRepository, repos.TierRepository, repos.RefundRepository, repos.CategoryRepository)
+	event := createTestEvent(t, 10)
+	createTestPromoCode(t, repos, &entity.PromoCode{Code: "HALF", DiscountType: entity.DiscountTypePercentage, DiscountValue: 50})
+
+	purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	payment, err := ticketService.PurchaseTicket(&entity.Ticket{UserID: 2, EventID: event.ID, PromoCode: "HALF"})
+	assert.NoError(t, err)
+	assert.NoError(t, sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded))
+
+	// Test
+	summary, err := reportService.GetEventSalesSummary(event.ID)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), summary.TotalTickets)
+	assert.Equal(t, 50000.0, summary.TotalDiscounts)
+	assert.Equal(t, 150000.0, summary.GrossRevenue)
+	assert.Equal(t, 150000.0, summary.TotalRevenue)
+}
@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+// purchasePaidTicket buys a ticket and settles its payment through the mock gateway
+func purchasePaidTicket(t *testing.T, ticketService service.TicketService, paymentService service.PaymentService, userID uint, eventID uint) *entity.Ticket {
+	ticket := &entity.Ticket{UserID: userID, EventID: eventID}
+	payment, err := ticketService.PurchaseTicket(ticket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded); err != nil {
+		t.Fatal(err)
+	}
+	return ticket
+}
+
+// failingRefundGateway is the mock gateway with refunds that fail while failing is set
+type failingRefundGateway struct {
+	*service.MockPaymentGateway
+	failing bool
+}
+
+func (g *failingRefundGateway) Refund(intentID string, amount float64) (string, error) {
+	if g.failing {
+		return "", errors.New("gateway unavailable")
+	}
+	return g.MockPaymentGateway.Refund(intentID, amount)
+}
+
+func TestCancelTicket_AppliesCancellationPolicy(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	refundService := newTestRefundService(repos)
+	event := createTestEvent(t, 10) // Starts in 48 hours
+
+	assert.NoError(t, refundService.SetCancellationPolicy(event.ID, []entity.CancellationPolicyRule{
+		{HoursBeforeStart: 7 * 24, RefundPercentage: 100},
+		{HoursBeforeStart: 0, RefundPercentage: 50},
+	}))
+	ticket := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+
+	// Test
+	refund, err := ticketService.CancelTicket(ticket.ID, 1)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 50.0, refund.Percentage)
+	assert.Equal(t, 50000.0, refund.Amount)
+	assert.Equal(t, entity.RefundStatusSucceeded, refund.Status)
+	assert.NotEmpty(t, refund.ProviderRefundID)
+}
+
+func TestRefundCancelledEvent_RefundsEveryTicket(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	refundService := newTestRefundService(repos)
+	reportService := service.NewReportService(repos.TicketRepository, repos.EventRepository, repos.TierRepository, repos.RefundRepository, repos.CategoryRepository)
+	event := createTestEvent(t, 10)
+
+	purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	purchasePaidTicket(t, ticketService, paymentService, 2, event.ID)
+	_, err := ticketService.PurchaseTicket(&entity.Ticket{UserID: 3, EventID: event.ID})
+	assert.NoError(t, err)
+
+	_, err = refundService.RefundCancelledEvent(event.ID)
+	assert.EqualError(t, err, "refunds can only be issued for cancelled events")
+	config.DB.Model(&entity.Event{}).Where("id = ?", event.ID).Update("status", entity.EventStatusCancelled)
+
+	// Test
+	refunds, err := refundService.RefundCancelledEvent(event.ID)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Len(t, refunds, 2)
+	for _, refund := range refunds {
+		assert.Equal(t, event.Price, refund.Amount)
+		assert.Equal(t, entity.RefundStatusSucceeded, refund.Status)
+	}
+
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Zero(t, savedEvent.SoldCount)
+
+	summary, err := reportService.GetEventSalesSummary(event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*event.Price, summary.GrossRevenue)
+	assert.Equal(t, 2*event.Price, summary.TotalRefunds)
+	assert.Zero(t, summary.TotalRevenue)
+
+	// Running it again finds nothing left to refund
+	refunds, err = refundService.RefundCancelledEvent(event.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, refunds)
+}
+
+func TestRefundCancelledEvent_RetriesFailedRefunds(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	gateway := &failingRefundGateway{MockPaymentGateway: service.NewMockPaymentGateway(testWebhookSecret), failing: true}
+	refundService := service.NewRefundService(repos.RefundRepository, repos.PolicyRepository, repos.PaymentRepository, repos.TicketRepository, repos.EventRepository, gateway, repos.Transactor)
+	event := createTestEvent(t, 10)
+
+	purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	purchasePaidTicket(t, ticketService, paymentService, 2, event.ID)
+	config.DB.Model(&entity.Event{}).Where("id = ?", event.ID).Update("status", entity.EventStatusCancelled)
+
+	// Test: the gateway rejects the refunds
+	refunds, err := refundService.RefundCancelledEvent(event.ID)
+
+	// Assertions
+	assert.ErrorIs(t, err, service.ErrRefundFailed)
+	assert.Len(t, refunds, 2)
+	for _, refund := range refunds {
+		assert.Equal(t, entity.RefundStatusFailed, refund.Status)
+	}
+
+	// Test: running it again once the gateway is back
+	gateway.failing = false
+	refunds, err = refundService.RefundCancelledEvent(event.ID)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Len(t, refunds, 2)
+	for _, refund := range refunds {
+		assert.Equal(t, event.Price, refund.Amount)
+		assert.Equal(t, entity.RefundStatusSucceeded, refund.Status)
+		assert.NotEmpty(t, refund.ProviderRefundID)
+	}
+
+	refunds, err = refundService.RefundCancelledEvent(event.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, refunds)
+}
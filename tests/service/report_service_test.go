@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+func TestSalesReport_UsesPriceAtPurchase(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	config.AppConfig.TicketServiceFee = 5000
+	ticketService, paymentService := newTestTicketService(repos)
+	reportService := service.NewReportService(repos.TicketRepository, repos.EventRepository, repos.TierRepository, repos.RefundRepository, repos.CategoryRepository)
+	event := createTestEvent(t, 10)
+
+	ticket := &entity.Ticket{UserID: 1, EventID: event.ID}
+	payment, err := ticketService.PurchaseTicket(ticket)
+	assert.NoError(t, err)
+	assert.NoError(t, sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded))
+
+	// Test
+	event.Price = 250000
+	assert.NoError(t, repos.EventRepository.Save(event))
+	summary, err := reportService.GetEventSalesSummary(event.ID)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 105000.0, payment.Amount)
+
+	savedTicket, _ := repos.TicketRepository.FindByID(ticket.ID)
+	assert.Equal(t, 100000.0, savedTicket.UnitPrice)
+	assert.Equal(t, 5000.0, savedTicket.Fee)
+	assert.Equal(t, 105000.0, savedTicket.PricePaid)
+	assert.Equal(t, "IDR", savedTicket.Currency)
+
+	assert.Equal(t, int64(1), summary.TotalTickets)
+	assert.Equal(t, 5000.0, summary.TotalFees)
+	assert.Equal(t, 105000.0, summary.TotalRevenue)
+}
+
+func TestExportEventSalesCSV_RowsHaveTheSameWidth(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	tierService := service.NewTicketTierService(repos.TierRepository, repos.EventRepository, repos.Transactor)
+	reportService := service.NewReportService(repos.TicketRepository, repos.EventRepository, repos.TierRepository, repos.RefundRepository, repos.CategoryRepository)
+	event := createTestEvent(t, 10)
+
+	vip := &entity.TicketTier{EventID: event.ID, Name: "VIP", Price: 500000, Capacity: 5}
+	assert.NoError(t, tierService.CreateTier(vip))
+	payment, err := ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: event.ID, TierID: &vip.ID})
+	assert.NoError(t, err)
+	assert.NoError(t, sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded))
+
+	// Test
+	data, err := reportService.ExportEventSalesCSV(event.ID)
+
+	// Assertions - a strict reader accepts the event and its tier table
+	assert.NoError(t, err)
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 5)
+	assert.Equal(t, "Tier ID", rows[3][0])
+	assert.Equal(t, "VIP", rows[4][1])
+}
+
+func TestSalesReport_FailedRefundsKeepTheRevenue(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	gateway := &failingRefundGateway{MockPaymentGateway: service.NewMockPaymentGateway(testWebhookSecret), failing: true}
+	refundService := service.NewRefundService(repos.RefundRepository, repos.PolicyRepository, repos.PaymentRepository, repos.TicketRepository, repos.EventRepository, gateway, repos.Transactor)
+	reportService := service.NewReportService(repos.TicketRepository, repos.EventRepository, repos.TierRepository, repos.RefundRepository, repos.CategoryRepository)
+	event := createTestEvent(t, 10)
+
+	purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	purchasePaidTicket(t, ticketService, paymentService, 2, event.ID)
+	config.DB.Model(&entity.Event{}).Where("id = ?", event.ID).Update("status", entity.EventStatusCancelled)
+
+	_, err := refundService.RefundCancelledEvent(event.ID)
+	assert.ErrorIs(t, err, service.ErrRefundFailed)
+
+	// Test
+	summary, err := reportService.GetEventSalesSummary(event.ID)
+
+	// Assertions - the money was never given back
+	assert.NoError(t, err)
+	assert.Equal(t, 2*event.Price, summary.GrossRevenue)
+	assert.Zero(t, summary.TotalRefunds)
+	assert.Equal(t, 2*event.Price, summary.TotalRevenue)
+
+	// Once the refunds go through they are deducted
+	gateway.failing = false
+	_, err = refundService.RefundCancelledEvent(event.ID)
+	assert.NoError(t, err)
+
+	summary, err = reportService.GetEventSalesSummary(event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*event.Price, summary.GrossRevenue)
+	assert.Equal(t, 2*event.Price, summary.TotalRefunds)
+	assert.Zero(t, summary.TotalRevenue)
+}
+
+func TestSalesReport_TiersAddUpToTheEvent(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	tierService := service.NewTicketTierService(repos.TierRepository, repos.EventRepository, repos.Transactor)
+	reportService := service.NewReportService(repos.TicketRepository, repos.EventRepository, repos.TierRepository, repos.RefundRepository, repos.CategoryRepository)
+	event := createTestEvent(t, 10)
+
+	vip := &entity.TicketTier{EventID: event.ID, Name: "VIP", Price: 500000, Capacity: 5}
+	assert.NoError(t, tierService.CreateTier(vip))
+	regular := &entity.TicketTier{EventID: event.ID, Name: "Regular", Price: 100000, Capacity: 5}
+	assert.NoError(t, tierService.CreateTier(regular))
+
+	buy := func(userID uint, tier *entity.TicketTier) *entity.Ticket {
+		ticket := &entity.Ticket{UserID: userID, EventID: event.ID, TierID: &tier.ID}
+		payment, err := ticketService.PurchaseTicket(ticket)
+		assert.NoError(t, err)
+		assert.NoError(t, sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded))
+		return ticket
+	}
+	buy(1, vip)
+	returned := buy(2, vip)
+	buy(3, regular)
+
+	_, err := ticketService.CancelTicket(returned.ID, 2)
+	assert.NoError(t, err)
+
+	// Test
+	summary, err := reportService.GetEventSalesSummary(event.ID)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 600000.0, summary.TotalRevenue)
+	assert.Equal(t, 500000.0, summary.TotalRefunds)
+
+	var tierRevenue, tierRefunds float64
+	for _, tier := range summary.TierSummary {
+		tierRevenue += tier.TotalRevenue
+		tierRefunds += tier.TotalRefunds
+	}
+	assert.Equal(t, summary.TotalRevenue, tierRevenue)
+	assert.Equal(t, summary.TotalRefunds, tierRefunds)
+}
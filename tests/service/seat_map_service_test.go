@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/dto"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+// createSeatedTestEvent creates an event on a seat map with a premium and two
+// regular seats in row A of the stalls and two balcony seats in row B
+func createSeatedTestEvent(t *testing.T, repos *repository.Repositories, capacity int) (*entity.Event, map[string]entity.Seat) {
+	premium := 200000.0
+	seatMapService := service.NewSeatMapService(repos.SeatMapRepository, repos.VenueRepository, repos.EventRepository)
+	seatMap, err := seatMapService.CreateSeatMap(dto.SeatMapCreateRequest{
+		Name: "Theatre",
+		Sections: []dto.SeatSectionRequest{
+			{Name: "Stalls", Category: "Regular", Price: 150000, Rows: []dto.SeatRowRequest{
+				{Label: "A", Seats: []dto.SeatRequest{{Number: "1", Category: "Premium", Price: &premium}, {Number: "2"}, {Number: "3"}}},
+			}},
+			{Name: "Balcony", Category: "Regular", Price: 80000, Rows: []dto.SeatRowRequest{
+				{Label: "B", SeatCount: 2},
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seats := make(map[string]entity.Seat)
+	for _, seat := range seatMap.Seats {
+		seats[seat.Row+seat.Number] = seat
+	}
+
+	event := &entity.Event{
+		Name:      fmt.Sprintf("Seated Event %d", time.Now().UnixNano()),
+		Location:  "Jakarta",
+		StartDate: time.Now().Add(48 * time.Hour),
+		EndDate:   time.Now().Add(50 * time.Hour),
+		Capacity:  capacity,
+		Price:     100000,
+		SeatMapID: &seatMap.ID,
+	}
+	if err := newTestEventService(repos).CreateEvent(event); err != nil {
+		t.Fatal(err)
+	}
+
+	return event, seats
+}
+
+func TestPurchaseTicket_ReservedSeat(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, _ := newTestTicketService(repos)
+	seatMapService := service.NewSeatMapService(repos.SeatMapRepository, repos.VenueRepository, repos.EventRepository)
+	event, seats := createSeatedTestEvent(t, repos, 5)
+	a1, b2 := seats["A1"].ID, seats["B2"].ID
+
+	// Test
+	ticket := &entity.Ticket{UserID: 1, EventID: event.ID, SeatID: &a1}
+	payment, err := ticketService.PurchaseTicket(ticket)
+	_, errTaken := ticketService.PurchaseTicket(&entity.Ticket{UserID: 2, EventID: event.ID, SeatID: &a1})
+	_, errNoSeat := ticketService.PurchaseTicket(&entity.Ticket{UserID: 2, EventID: event.ID})
+	balcony := &entity.Ticket{UserID: 2, EventID: event.ID, SeatID: &b2}
+	errBalcony := ticketService.HoldTicket(balcony)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 200000.0, payment.Amount)
+	assert.True(t, errors.Is(errTaken, service.ErrSeatTaken))
+	assert.EqualError(t, errNoSeat, "seat_id is required for events with reserved seating")
+	assert.NoError(t, errBalcony)
+	assert.Equal(t, 80000.0, balcony.PricePaid)
+
+	available := func() map[string]bool {
+		eventSeats, err := seatMapService.GetEventSeats(event.ID, false)
+		assert.NoError(t, err)
+		result := make(map[string]bool)
+		for _, seat := range eventSeats {
+			result[seat.Row+seat.Number] = *seat.Available
+		}
+		return result
+	}
+	assert.Equal(t, map[string]bool{"A1": false, "A2": true, "A3": true, "B1": true, "B2": false}, available())
+
+	// Releasing the hold puts the seat back on sale
+	assert.NoError(t, ticketService.ReleaseHold(balcony.ID, 2))
+	assert.True(t, available()["B2"])
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 3, EventID: event.ID, SeatID: &b2})
+	assert.NoError(t, err)
+
+	// Seated events are priced by seat
+	tierService := service.NewTicketTierService(repos.TierRepository, repos.EventRepository, repos.Transactor)
+	errTier := tierService.CreateTier(&entity.TicketTier{EventID: event.ID, Name: "VIP", Price: 1, Capacity: 1})
+	assert.EqualError(t, errTier, "events with reserved seating are priced by seat, not by tier")
+}
+
+func TestPurchaseTicket_ConcurrentBuyersOfOneSeat(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, _ := newTestTicketService(repos)
+	event, seats := createSeatedTestEvent(t, repos, 5)
+	seatID := seats["A2"].ID
+	buyers := 10
+
+	// Test
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded, taken := 0, 0
+
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			_, err := ticketService.PurchaseTicket(&entity.Ticket{UserID: userID, EventID: event.ID, SeatID: &seatID})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				succeeded++
+			} else if errors.Is(err, service.ErrSeatTaken) {
+				taken++
+			} else {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(uint(i + 1))
+	}
+	wg.Wait()
+
+	// Assertions
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, buyers-1, taken)
+
+	// Rejected buyers do not keep the capacity they took
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Equal(t, 1, savedEvent.SoldCount)
+}
+
+func TestCreateOrder_ReservedSeats(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	_, paymentService := newTestTicketService(repos)
+	orderService := service.NewOrderService(repos.OrderRepository, repos.EventRepository, repos.TierRepository, repos.SeatMapRepository, paymentService, repos.Transactor)
+	event, seats := createSeatedTestEvent(t, repos, 5)
+	a2, a3 := seats["A2"].ID, seats["A3"].ID
+
+	// Test
+	order := &entity.Order{UserID: 1, Items: []entity.OrderItem{{EventID: event.ID, SeatID: &a2}, {EventID: event.ID, SeatID: &a3}}}
+	_, err := orderService.CreateOrder(order)
+	_, errTaken := orderService.CreateOrder(&entity.Order{UserID: 2, Items: []entity.OrderItem{{EventID: event.ID, SeatID: &a3}}})
+	_, errQuantity := orderService.CreateOrder(&entity.Order{UserID: 2, Items: []entity.OrderItem{{EventID: event.ID, SeatID: &a2, Quantity: 2}}})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 300000.0, order.TotalAmount)
+	assert.Len(t, order.Tickets, 2)
+	assert.Equal(t, a2, *order.Tickets[0].SeatID)
+	assert.True(t, errors.Is(errTaken, service.ErrSeatTaken))
+	assert.EqualError(t, errQuantity, "order items with a seat_id must have a quantity of 1")
+}
+
+func TestCreateEvent_SeatMapCapacity(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	_, seats := createSeatedTestEvent(t, repos, 5)
+	seatMapID := seats["A1"].SeatMapID
+
+	// Test
+	err := newTestEventService(repos).CreateEvent(&entity.Event{
+		Name:      "Overbooked",
+		Location:  "Jakarta",
+		StartDate: time.Now().Add(48 * time.Hour),
+		EndDate:   time.Now().Add(50 * time.Hour),
+		Capacity:  6,
+		SeatMapID: &seatMapID,
+	})
+
+	// Assertions
+	assert.EqualError(t, err, "event capacity cannot be higher than the 5 seats of its seat map")
+}
@@ -0,0 +1,444 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/service"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTicketTestDB points the application at a file-backed SQLite database so
+// concurrent transactions behave like they do against a real server
+func setupTicketTestDB(t *testing.T) *repository.Repositories {
+	dsn := filepath.Join(t.TempDir(), "tickets.db") + "?_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db.AutoMigrate(&entity.User{}, &entity.Event{}, &entity.Ticket{}, &entity.Order{}, &entity.OrderItem{}, &entity.TicketTier{}, &entity.Payment{}, &entity.Refund{}, &entity.CancellationPolicyRule{}, &entity.WaitlistEntry{}, &entity.TicketTransfer{}, &entity.PromoCode{}, &entity.IdempotencyKey{}, &entity.AuditLog{}, &entity.Venue{}, &entity.SeatMap{}, &entity.Seat{}, &entity.SeatReservation{}, &entity.EventSeries{}, &entity.Category{}, &entity.Tag{}, &entity.Media{})
+	config.DB = db
+	config.AppConfig.Currency = "IDR"
+	config.AppConfig.TicketServiceFee = 0
+	config.AppConfig.TicketHoldDuration = time.Minute
+	config.AppConfig.TicketSigningSecret = "test-signing-secret"
+	config.AppConfig.WaitlistClaimDuration = time.Minute
+	config.AppConfig.IdempotencyKeyTTL = time.Hour
+
+	return repository.InitRepositories()
+}
+
+const testWebhookSecret = "test-webhook-secret"
+
+// newTestTicketService wires a ticket service to the mock payment gateway
+func newTestTicketService(repos *repository.Repositories) (service.TicketService, service.PaymentService) {
+	paymentService := service.NewPaymentService(repos.PaymentRepository, service.NewMockPaymentGateway(testWebhookSecret), repos.Transactor)
+	return service.NewTicketService(repos.TicketRepository, repos.EventRepository, repos.TierRepository, repos.SeatMapRepository, repos.UserRepository, repos.TransferRepository, paymentService, newTestRefundService(repos), repos.Transactor), paymentService
+}
+
+func newTestRefundService(repos *repository.Repositories) service.RefundService {
+	return service.NewRefundService(repos.RefundRepository, repos.PolicyRepository, repos.PaymentRepository, repos.TicketRepository, repos.EventRepository, service.NewMockPaymentGateway(testWebhookSecret), repos.Transactor)
+}
+
+// sendPaymentWebhook delivers a signed gateway notification for the payment
+func sendPaymentWebhook(paymentService service.PaymentService, payment *entity.Payment, status string) error {
+	payload, _ := json.Marshal(service.PaymentWebhookEvent{IntentID: payment.IntentID, Status: status, Amount: payment.Amount})
+	signature := service.NewMockPaymentGateway(testWebhookSecret).SignPayload(payload)
+	return paymentService.HandleWebhook(payload, signature)
+}
+
+func createTestEvent(t *testing.T, capacity int) *entity.Event {
+	event := &entity.Event{
+		Name:      fmt.Sprintf("Test Event %d", time.Now().UnixNano()),
+		Location:  "Jakarta",
+		StartDate: time.Now().Add(48 * time.Hour),
+		EndDate:   time.Now().Add(50 * time.Hour),
+		Capacity:  capacity,
+		Price:     100000,
+		Status:    entity.EventStatusActive,
+	}
+	if err := config.DB.Create(event).Error; err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestPurchaseTicket_ConcurrentBuyersDoNotOversell(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, _ := newTestTicketService(repos)
+
+	capacity := 5
+	buyers := 25
+	event := createTestEvent(t, capacity)
+
+	// Test
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded, soldOut := 0, 0
+
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			_, err := ticketService.PurchaseTicket(&entity.Ticket{UserID: userID, EventID: event.ID})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				succeeded++
+			} else if err.Error() == "event is sold out" {
+				soldOut++
+			} else {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(uint(i + 1))
+	}
+	wg.Wait()
+
+	// Assertions
+	assert.Equal(t, capacity, succeeded)
+	assert.Equal(t, buyers-capacity, soldOut)
+
+	var ticketCount int64
+	config.DB.Model(&entity.Ticket{}).Where("event_id = ?", event.ID).Count(&ticketCount)
+	assert.Equal(t, int64(capacity), ticketCount)
+
+	savedEvent, err := repos.EventRepository.FindByID(event.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, capacity, savedEvent.SoldCount)
+}
+
+func TestCancelTicket_ReleasesSeat(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	event := createTestEvent(t, 1)
+
+	ticket := &entity.Ticket{UserID: 1, EventID: event.ID}
+	payment, err := ticketService.PurchaseTicket(ticket)
+	assert.NoError(t, err)
+	assert.NoError(t, sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded))
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 2, EventID: event.ID})
+	assert.EqualError(t, err, "event is sold out")
+
+	// Test
+	refund, err := ticketService.CancelTicket(ticket.ID, 1)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, event.Price, refund.Amount)
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 2, EventID: event.ID})
+	assert.NoError(t, err)
+	_, err = ticketService.CancelTicket(ticket.ID, 1)
+	assert.EqualError(t, err, "ticket is already cancelled")
+}
+
+func TestPurchaseTicket_TierCapacity(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, _ := newTestTicketService(repos)
+	tierService := service.NewTicketTierService(repos.TierRepository, repos.EventRepository, repos.Transactor)
+	event := createTestEvent(t, 10)
+
+	vip := &entity.TicketTier{EventID: event.ID, Name: "VIP", Price: 500000, Capacity: 1}
+	assert.NoError(t, tierService.CreateTier(vip))
+	assert.EqualError(t, tierService.CreateTier(&entity.TicketTier{EventID: event.ID, Name: "Regular", Price: 100000, Capacity: 10}),
+		"total tier capacity cannot exceed event capacity")
+
+	// Test
+	_, errWithoutTier := ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: event.ID})
+	payment, errFirst := ticketService.PurchaseTicket(&entity.Ticket{UserID: 1, EventID: event.ID, TierID: &vip.ID})
+	_, errSecond := ticketService.PurchaseTicket(&entity.Ticket{UserID: 2, EventID: event.ID, TierID: &vip.ID})
+
+	// Assertions
+	assert.EqualError(t, errWithoutTier, "tier_id is required for events with ticket tiers")
+	assert.NoError(t, errFirst)
+	assert.Equal(t, vip.Price, payment.Amount)
+	assert.EqualError(t, errSecond, "ticket tier is sold out")
+
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Equal(t, 1, savedEvent.SoldCount)
+}
+
+func TestHoldTicket_ConfirmBeforeExpiry(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	config.AppConfig.TicketHoldDuration = time.Minute
+	event := createTestEvent(t, 1)
+
+	ticket := &entity.Ticket{UserID: 1, EventID: event.ID}
+	assert.NoError(t, ticketService.HoldTicket(ticket))
+	assert.EqualError(t, ticketService.HoldTicket(&entity.Ticket{UserID: 2, EventID: event.ID}), "event is sold out")
+
+	// Test
+	payment, err := ticketService.ConfirmHold(ticket.ID, 1)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, event.Price, payment.Amount)
+	assert.NoError(t, sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded))
+	savedTicket, _ := repos.TicketRepository.FindByID(ticket.ID)
+	assert.Equal(t, entity.TicketStatusPurchased, savedTicket.Status)
+	assert.Nil(t, savedTicket.ExpiresAt)
+}
+
+func TestReleaseExpiredHolds_FreesSeat(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, _ := newTestTicketService(repos)
+	config.AppConfig.TicketHoldDuration = time.Millisecond
+	event := createTestEvent(t, 1)
+
+	ticket := &entity.Ticket{UserID: 1, EventID: event.ID}
+	assert.NoError(t, ticketService.HoldTicket(ticket))
+	time.Sleep(5 * time.Millisecond)
+
+	// Test
+	err := ticketService.ReleaseExpiredHolds()
+
+	// Assertions
+	assert.NoError(t, err)
+	savedTicket, _ := repos.TicketRepository.FindByID(ticket.ID)
+	assert.Equal(t, entity.TicketStatusExpired, savedTicket.Status)
+	_, err = ticketService.ConfirmHold(ticket.ID, 1)
+	assert.EqualError(t, err, "ticket is not on hold")
+
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Zero(t, savedEvent.SoldCount)
+}
+
+func TestCheckIn_AdmitsTicketOnce(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	event := createTestEvent(t, 1)
+	ticket := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	token := service.SignTicketToken(ticket)
+
+	// Test
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	admitted, rejected := 0, 0
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(staffID uint) {
+			defer wg.Done()
+			_, err := ticketService.CheckIn(token, staffID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				admitted++
+			} else if err == service.ErrTicketAlreadyCheckedIn {
+				rejected++
+			} else {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(uint(100 + i))
+	}
+	wg.Wait()
+
+	// Assertions
+	assert.Equal(t, 1, admitted)
+	assert.Equal(t, 9, rejected)
+
+	savedTicket, _ := repos.TicketRepository.FindByID(ticket.ID)
+	assert.NotNil(t, savedTicket.CheckedInAt)
+}
+
+func TestCheckTicketSigningSecret_RequiresASecretOfItsOwn(t *testing.T) {
+	// Test and assertions
+	err := service.CheckTicketSigningSecret(config.Config{JWTSecret: "jwt-secret"})
+	assert.EqualError(t, err, "TICKET_SIGNING_SECRET is required to sign ticket QR codes")
+	err = service.CheckTicketSigningSecret(config.Config{JWTSecret: "jwt-secret", TicketSigningSecret: "jwt-secret"})
+	assert.EqualError(t, err, "TICKET_SIGNING_SECRET must differ from JWT_SECRET")
+	err = service.CheckTicketSigningSecret(config.Config{JWTSecret: "jwt-secret", TicketSigningSecret: "test-signing-secret"})
+	assert.NoError(t, err)
+}
+
+func TestCheckIn_RejectsForgedAndCancelledTickets(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	event := createTestEvent(t, 2)
+	ticket := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	cancelled := purchasePaidTicket(t, ticketService, paymentService, 2, event.ID)
+	_, err := ticketService.CancelTicket(cancelled.ID, 2)
+	assert.NoError(t, err)
+
+	// Test
+	// Reuse a valid signature for another ticket
+	signature := strings.SplitN(service.SignTicketToken(ticket), ".", 3)[2]
+	forged := fmt.Sprintf("%d.%d.%s", cancelled.ID+1, event.ID, signature)
+	_, errForged := ticketService.CheckIn(forged, 100)
+	_, errCancelled := ticketService.CheckIn(service.SignTicketToken(cancelled), 100)
+
+	// Assertions
+	assert.EqualError(t, errForged, "invalid ticket token")
+	assert.EqualError(t, errCancelled, "ticket has been cancelled")
+}
+
+func TestGetTicketPDF_OnlyForPurchasedTickets(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	event := createTestEvent(t, 2)
+	ticket := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+
+	held := &entity.Ticket{UserID: 2, EventID: event.ID}
+	assert.NoError(t, ticketService.HoldTicket(held))
+
+	// Test
+	pdf, err := ticketService.GetTicketPDF(ticket.ID)
+	_, errHeld := ticketService.GetTicketPDF(held.ID)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(pdf), "%PDF"))
+	assert.EqualError(t, errHeld, "only purchased tickets can be downloaded")
+}
+
+func createTestUser(t *testing.T, id uint, email string) {
+	user := &entity.User{ID: id, Name: fmt.Sprintf("User %d", id), Email: email, Password: "password123"}
+	if err := config.DB.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTransferTicket_RecipientBecomesHolder(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	createTestUser(t, 1, "sender@example.com")
+	createTestUser(t, 2, "friend@example.com")
+	event := createTestEvent(t, 1)
+	ticket := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	oldToken := service.SignTicketToken(ticket)
+
+	// Test
+	transfer, err := ticketService.StartTransfer(ticket.ID, 1, "friend@example.com")
+	assert.NoError(t, err)
+	_, errDuplicate := ticketService.StartTransfer(ticket.ID, 1, "friend@example.com")
+	_, errStranger := ticketService.AcceptTransfer(transfer.ID, 1)
+	accepted, err := ticketService.AcceptTransfer(transfer.ID, 2)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.EqualError(t, errDuplicate, "ticket already has a pending transfer")
+	assert.EqualError(t, errStranger, "unauthorized to accept this transfer")
+	assert.Equal(t, entity.TransferStatusAccepted, accepted.Status)
+	assert.NotNil(t, accepted.AcceptedAt)
+
+	savedTicket, _ := repos.TicketRepository.FindByID(ticket.ID)
+	assert.Equal(t, uint(2), savedTicket.UserID)
+
+	// The previous holder's QR code no longer admits anyone
+	_, err = ticketService.CheckIn(oldToken, 100)
+	assert.EqualError(t, err, "ticket has been transferred to another holder")
+	_, err = ticketService.CheckIn(service.SignTicketToken(savedTicket), 100)
+	assert.NoError(t, err)
+}
+
+func TestTransferTicket_BlockedOnceEventStarts(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	createTestUser(t, 1, "sender@example.com")
+	createTestUser(t, 2, "friend@example.com")
+	event := createTestEvent(t, 2)
+	ticket := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	other := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	transfer, err := ticketService.StartTransfer(ticket.ID, 1, "friend@example.com")
+	assert.NoError(t, err)
+
+	config.DB.Model(&entity.Event{}).Where("id = ?", event.ID).Update("start_date", time.Now().Add(-time.Minute))
+
+	// Test
+	_, errAccept := ticketService.AcceptTransfer(transfer.ID, 2)
+	_, errStart := ticketService.StartTransfer(other.ID, 1, "friend@example.com")
+
+	// Assertions
+	assert.EqualError(t, errAccept, "cannot transfer tickets for events that have already started")
+	assert.EqualError(t, errStart, "cannot transfer tickets for events that have already started")
+
+	savedTicket, _ := repos.TicketRepository.FindByID(ticket.ID)
+	assert.Equal(t, uint(1), savedTicket.UserID)
+}
+
+func TestAcceptTransfer_RespectsPerUserLimit(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	createTestUser(t, 1, "sender@example.com")
+	createTestUser(t, 2, "collector@example.com")
+	event := createTestEvent(t, 10)
+	config.DB.Model(event).Update("max_tickets_per_user", 2)
+
+	purchasePaidTicket(t, ticketService, paymentService, 2, event.ID)
+	first := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	second := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	firstTransfer, err := ticketService.StartTransfer(first.ID, 1, "collector@example.com")
+	assert.NoError(t, err)
+	secondTransfer, err := ticketService.StartTransfer(second.ID, 1, "collector@example.com")
+	assert.NoError(t, err)
+
+	// Test
+	_, errFirst := ticketService.AcceptTransfer(firstTransfer.ID, 2)
+	_, errSecond := ticketService.AcceptTransfer(secondTransfer.ID, 2)
+
+	// Assertions
+	assert.NoError(t, errFirst)
+	assert.ErrorIs(t, errSecond, service.ErrUserLimitReached)
+	savedTicket, _ := repos.TicketRepository.FindByID(second.ID)
+	assert.Equal(t, uint(1), savedTicket.UserID)
+	pending, _ := repos.TransferRepository.FindByID(secondTransfer.ID)
+	assert.Equal(t, entity.TransferStatusPending, pending.Status)
+}
+
+func TestHoldTicket_PerUserLimitAndCooldown(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, _ := newTestTicketService(repos)
+	limited := createTestEvent(t, 10)
+	config.DB.Model(limited).Update("max_tickets_per_user", 2)
+	throttled := createTestEvent(t, 10)
+	config.DB.Model(throttled).Update("purchase_cooldown_seconds", 60)
+
+	first := &entity.Ticket{UserID: 1, EventID: limited.ID}
+	assert.NoError(t, ticketService.HoldTicket(first))
+	assert.NoError(t, ticketService.HoldTicket(&entity.Ticket{UserID: 1, EventID: limited.ID}))
+	assert.NoError(t, ticketService.HoldTicket(&entity.Ticket{UserID: 1, EventID: throttled.ID}))
+
+	// Test
+	errLimit := ticketService.HoldTicket(&entity.Ticket{UserID: 1, EventID: limited.ID})
+	errOtherUser := ticketService.HoldTicket(&entity.Ticket{UserID: 2, EventID: limited.ID})
+	errCooldown := ticketService.HoldTicket(&entity.Ticket{UserID: 1, EventID: throttled.ID})
+
+	// Assertions
+	assert.ErrorIs(t, errLimit, service.ErrUserLimitReached)
+	assert.NoError(t, errOtherUser)
+	assert.ErrorIs(t, errCooldown, service.ErrPurchaseCooldown)
+
+	savedEvent, _ := repos.EventRepository.FindByID(limited.ID)
+	assert.Equal(t, 3, savedEvent.SoldCount)
+
+	// Releasing a hold gives the buyer room again, and the cooldown runs out
+	assert.NoError(t, ticketService.ReleaseHold(first.ID, 1))
+	assert.NoError(t, ticketService.HoldTicket(&entity.Ticket{UserID: 1, EventID: limited.ID}))
+	config.DB.Model(&entity.Ticket{}).Where("event_id = ?", throttled.ID).Update("created_at", time.Now().Add(-2*time.Minute))
+	assert.NoError(t, ticketService.HoldTicket(&entity.Ticket{UserID: 1, EventID: throttled.ID}))
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/dto"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+func createTestVenue(t *testing.T, repos *repository.Repositories, name, city string, maxCapacity int) *entity.Venue {
+	venue := &entity.Venue{
+		Name:        name,
+		Address:     "Jl. Test No. 1",
+		City:        city,
+		Timezone:    "Asia/Jakarta",
+		MaxCapacity: maxCapacity,
+	}
+	if err := service.NewVenueService(repos.VenueRepository, repos.EventRepository).CreateVenue(venue); err != nil {
+		t.Fatal(err)
+	}
+	return venue
+}
+
+func TestCreateVenue_Validation(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	venueService := service.NewVenueService(repos.VenueRepository, repos.EventRepository)
+	createTestVenue(t, repos, "Istora Senayan", "Jakarta", 7000)
+
+	latitude := -6.2
+	valid := func() *entity.Venue {
+		return &entity.Venue{Name: "Sabuga", Address: "Jl. Tamansari 73", City: "Bandung", Timezone: "Asia/Jakarta", MaxCapacity: 1500}
+	}
+	withChange := func(change func(venue *entity.Venue)) *entity.Venue {
+		venue := valid()
+		change(venue)
+		return venue
+	}
+
+	// Test and assertions
+	assert.EqualError(t, venueService.CreateVenue(withChange(func(v *entity.Venue) { v.Name = "istora senayan" })), "venue already exists")
+	assert.EqualError(t, venueService.CreateVenue(withChange(func(v *entity.Venue) { v.City = " " })), "venue city is required")
+	assert.EqualError(t, venueService.CreateVenue(withChange(func(v *entity.Venue) { v.MaxCapacity = 0 })), "venue max_capacity must be positive")
+	assert.EqualError(t, venueService.CreateVenue(withChange(func(v *entity.Venue) { v.Timezone = "Mars/Olympus" })), `unknown timezone "Mars/Olympus"`)
+	assert.EqualError(t, venueService.CreateVenue(withChange(func(v *entity.Venue) { v.Latitude = &latitude })), "latitude and longitude must be given together")
+
+	venue := valid()
+	assert.NoError(t, venueService.CreateVenue(venue))
+	assert.NotZero(t, venue.ID)
+}
+
+func TestCreateEvent_AtVenue(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	eventService := newTestEventService(repos)
+	venueService := service.NewVenueService(repos.VenueRepository, repos.EventRepository)
+	istora := createTestVenue(t, repos, "Istora Senayan", "Jakarta", 500)
+	sabuga := createTestVenue(t, repos, "Sabuga", "Bandung", 1500)
+
+	newEvent := func(name string, venueID uint, capacity int) *entity.Event {
+		return &entity.Event{
+			Name:      name,
+			VenueID:   &venueID,
+			StartDate: time.Now().Add(48 * time.Hour),
+			EndDate:   time.Now().Add(50 * time.Hour),
+			Capacity:  capacity,
+			Price:     100000,
+		}
+	}
+
+	// Test
+	tooBig := eventService.CreateEvent(newEvent("Too Big", istora.ID, 501))
+	jakarta := newEvent("Jakarta Show", istora.ID, 500)
+	errJakarta := eventService.CreateEvent(jakarta)
+	bandung := newEvent("Bandung Show", sabuga.ID, 1000)
+	errBandung := eventService.CreateEvent(bandung)
+
+	// Assertions
+	assert.EqualError(t, tooBig, "event capacity cannot be higher than the venue's max capacity of 500")
+	assert.NoError(t, errJakarta)
+	assert.NoError(t, errBandung)
+	assert.Equal(t, "Istora Senayan, Jakarta", jakarta.Location)
+
+	saved, _ := eventService.GetEventByID(jakarta.ID)
+	assert.Equal(t, "Istora Senayan", saved.Venue.Name)
+
+	// Events can be listed by venue and by city
+	events, total, err := venueService.GetVenueEvents(sabuga.ID, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, bandung.ID, events[0].ID)
+
+	events, _, err = eventService.GetAllEvents(1, 10, dto.EventFilterRequest{City: "jakarta"})
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, jakarta.ID, events[0].ID)
+
+	// The venue cannot shrink below its upcoming events or go while it has events
+	istora.MaxCapacity = 400
+	assert.EqualError(t, venueService.UpdateVenue(istora.ID, istora), "max_capacity cannot be lower than the capacity of an upcoming event (500)")
+	assert.EqualError(t, venueService.DeleteVenue(istora.ID), "cannot delete a venue that has events")
+}
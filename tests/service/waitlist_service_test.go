@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taufikmulyawan/ticketing-system/config"
+	"github.com/taufikmulyawan/ticketing-system/entity"
+	"github.com/taufikmulyawan/ticketing-system/repository"
+	"github.com/taufikmulyawan/ticketing-system/service"
+)
+
+func newTestWaitlistService(repos *repository.Repositories, paymentService service.PaymentService) service.WaitlistService {
+	return service.NewWaitlistService(repos.WaitlistRepository, repos.EventRepository, repos.TierRepository, paymentService, repos.Transactor)
+}
+
+func TestWaitlist_CancelledSeatGoesToNextInLine(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	waitlistService := newTestWaitlistService(repos, paymentService)
+	event := createTestEvent(t, 1)
+
+	ticket := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	first, err := waitlistService.JoinWaitlist(event.ID, 2)
+	assert.NoError(t, err)
+	second, err := waitlistService.JoinWaitlist(event.ID, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), first.Position)
+	assert.Equal(t, int64(2), second.Position)
+
+	// Test
+	_, err = ticketService.CancelTicket(ticket.ID, 1)
+	assert.NoError(t, err)
+
+	// Assertions
+	offered, _ := waitlistService.GetMyEntry(event.ID, 2)
+	assert.Equal(t, entity.WaitlistStatusOffered, offered.Status)
+	waiting, _ := waitlistService.GetMyEntry(event.ID, 3)
+	assert.Equal(t, int64(1), waiting.Position)
+
+	// The seat is kept for the offer instead of going back on sale
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 4, EventID: event.ID})
+	assert.EqualError(t, err, "event is sold out")
+	_, _, err = waitlistService.ClaimOffer(event.ID, 3)
+	assert.EqualError(t, err, "no seat has been offered to you yet")
+
+	claimed, payment, err := waitlistService.ClaimOffer(event.ID, 2)
+	assert.NoError(t, err)
+	assert.NoError(t, sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded))
+
+	savedTicket, _ := repos.TicketRepository.FindByID(claimed.ID)
+	assert.Equal(t, entity.TicketStatusPurchased, savedTicket.Status)
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Equal(t, 1, savedEvent.SoldCount)
+}
+
+func TestWaitlist_ExpiredOfferRollsOver(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	waitlistService := newTestWaitlistService(repos, paymentService)
+	config.AppConfig.WaitlistClaimDuration = time.Millisecond
+	event := createTestEvent(t, 1)
+
+	ticket := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	_, err := waitlistService.JoinWaitlist(event.ID, 2)
+	assert.NoError(t, err)
+	_, err = waitlistService.JoinWaitlist(event.ID, 3)
+	assert.NoError(t, err)
+	_, err = ticketService.CancelTicket(ticket.ID, 1)
+	assert.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	// Test
+	err = waitlistService.ExpireOffers()
+
+	// Assertions
+	assert.NoError(t, err)
+	_, err = waitlistService.GetMyEntry(event.ID, 2)
+	assert.Error(t, err)
+	next, _ := waitlistService.GetMyEntry(event.ID, 3)
+	assert.Equal(t, entity.WaitlistStatusOffered, next.Status)
+
+	// With nobody left in line the seat goes back on sale
+	time.Sleep(5 * time.Millisecond)
+	assert.NoError(t, waitlistService.ExpireOffers())
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Zero(t, savedEvent.SoldCount)
+}
+
+func TestWaitlist_ExpiredHoldGoesToNextInLine(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	waitlistService := newTestWaitlistService(repos, paymentService)
+	config.AppConfig.TicketHoldDuration = time.Millisecond
+	config.AppConfig.WaitlistClaimDuration = time.Minute
+	event := createTestEvent(t, 1)
+
+	hold := &entity.Ticket{UserID: 1, EventID: event.ID}
+	assert.NoError(t, ticketService.HoldTicket(hold))
+	_, err := waitlistService.JoinWaitlist(event.ID, 2)
+	assert.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	// Test
+	err = ticketService.ReleaseExpiredHolds()
+
+	// Assertions
+	assert.NoError(t, err)
+	savedHold, _ := repos.TicketRepository.FindByID(hold.ID)
+	assert.NotEqual(t, entity.TicketStatusReserved, savedHold.Status)
+	offered, _ := waitlistService.GetMyEntry(event.ID, 2)
+	assert.Equal(t, entity.WaitlistStatusOffered, offered.Status)
+
+	// The freed seat stays with the waitlist instead of going back on sale
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 3, EventID: event.ID})
+	assert.EqualError(t, err, "event is sold out")
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Equal(t, 1, savedEvent.SoldCount)
+}
+
+func TestWaitlist_ClosedWhenEventIsCancelled(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	waitlistService := newTestWaitlistService(repos, paymentService)
+	eventService := newTestEventService(repos)
+	event := createTestEvent(t, 2)
+
+	ticket := purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	purchasePaidTicket(t, ticketService, paymentService, 2, event.ID)
+	_, err := waitlistService.JoinWaitlist(event.ID, 3)
+	assert.NoError(t, err)
+	_, err = waitlistService.JoinWaitlist(event.ID, 4)
+	assert.NoError(t, err)
+	_, err = ticketService.CancelTicket(ticket.ID, 1)
+	assert.NoError(t, err)
+
+	// Test
+	_, _, err = eventService.CancelEvent(event.ID, "Venue flooded")
+
+	// Assertions
+	assert.NoError(t, err)
+	entries, err := waitlistService.GetEventWaitlist(event.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	_, _, err = waitlistService.ClaimOffer(event.ID, 3)
+	assert.EqualError(t, err, "waitlist entry not found")
+
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Zero(t, savedEvent.SoldCount)
+}
+
+func TestWaitlist_RaisedCapacityIsOffered(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	waitlistService := newTestWaitlistService(repos, paymentService)
+	eventService := newTestEventService(repos)
+	event := createTestEvent(t, 1)
+
+	purchasePaidTicket(t, ticketService, paymentService, 1, event.ID)
+	for _, userID := range []uint{2, 3, 4} {
+		_, err := waitlistService.JoinWaitlist(event.ID, userID)
+		assert.NoError(t, err)
+	}
+
+	// Test
+	raised := *event
+	raised.Capacity = 3
+	err := eventService.UpdateEvent(event.ID, &raised)
+
+	// Assertions
+	assert.NoError(t, err)
+	for _, userID := range []uint{2, 3} {
+		entry, _ := waitlistService.GetMyEntry(event.ID, userID)
+		assert.Equal(t, entity.WaitlistStatusOffered, entry.Status)
+	}
+	waiting, _ := waitlistService.GetMyEntry(event.ID, 4)
+	assert.Equal(t, int64(1), waiting.Position)
+
+	// The new seats are kept for the offers
+	savedEvent, _ := repos.EventRepository.FindByID(event.ID)
+	assert.Equal(t, 3, savedEvent.SoldCount)
+	_, err = ticketService.PurchaseTicket(&entity.Ticket{UserID: 5, EventID: event.ID})
+	assert.EqualError(t, err, "event is sold out")
+}
+
+func TestWaitlist_ClaimRespectsTierSalesWindow(t *testing.T) {
+	// Setup
+	repos := setupTicketTestDB(t)
+	ticketService, paymentService := newTestTicketService(repos)
+	waitlistService := newTestWaitlistService(repos, paymentService)
+	tierService := service.NewTicketTierService(repos.TierRepository, repos.EventRepository, repos.Transactor)
+	event := createTestEvent(t, 1)
+
+	tier := &entity.TicketTier{EventID: event.ID, Name: "Early Bird", Price: 50000, Capacity: 1}
+	assert.NoError(t, tierService.CreateTier(tier))
+	ticket := &entity.Ticket{UserID: 1, EventID: event.ID, TierID: &tier.ID}
+	payment, err := ticketService.PurchaseTicket(ticket)
+	assert.NoError(t, err)
+	assert.NoError(t, sendPaymentWebhook(paymentService, payment, service.PaymentEventSucceeded))
+
+	_, err = waitlistService.JoinWaitlist(event.ID, 2)
+	assert.NoError(t, err)
+	_, err = ticketService.CancelTicket(ticket.ID, 1)
+	assert.NoError(t, err)
+
+	// The tier stops selling before the offer is claimed
+	config.DB.Model(tier).Update("sales_end", time.Now().Add(-time.Minute))
+
+	// Test
+	_, _, err = waitlistService.ClaimOffer(event.ID, 2)
+
+	// Assertions
+	assert.EqualError(t, err, "ticket tier sales have ended")
+}
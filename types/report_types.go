@@ -1,17 +1,50 @@
 package types
 
+// TierSalesSummary represents sales data for one ticket tier of an event
+type TierSalesSummary struct {
+	TierID         uint    `json:"tier_id"`
+	TierName       string  `json:"tier_name"`
+	Price          float64 `json:"price"`
+	TotalTickets   int64   `json:"total_tickets"`
+	TotalDiscounts float64 `json:"total_discounts"`
+	TotalFees      float64 `json:"total_fees"`
+	TotalRefunds   float64 `json:"total_refunds"`
+	TotalRevenue   float64 `json:"total_revenue"` // Amount paid, net of discounts and refunds and including fees
+}
+
 // EventSalesSummary represents sales data for a specific event
 type EventSalesSummary struct {
-	EventID       uint    `json:"event_id"`
-	EventName     string  `json:"event_name"`
-	TotalTickets  int64   `json:"total_tickets"`
-	TotalRevenue  float64 `json:"total_revenue"`
+	EventID        uint               `json:"event_id"`
+	EventName      string             `json:"event_name"`
+	TotalTickets   int64              `json:"total_tickets"`
+	TotalDiscounts float64            `json:"total_discounts"`
+	TotalFees      float64            `json:"total_fees"`
+	GrossRevenue   float64            `json:"gross_revenue"` // Amount paid, including tickets refunded later
+	TotalRefunds   float64            `json:"total_refunds"`
+	TotalRevenue   float64            `json:"total_revenue"` // Net of discounts and refunds
+	TierSummary    []TierSalesSummary `json:"tier_summary,omitempty"`
 }
 
 // SalesSummary represents overall sales data across all events
 type SalesSummary struct {
-	TotalEvents   int64              `json:"total_events"`
-	TotalTickets  int64              `json:"total_tickets"`
-	TotalRevenue  float64            `json:"total_revenue"`
-	EventSummary  []EventSalesSummary `json:"event_summary"`
-} 
\ No newline at end of file
+	TotalEvents    int64               `json:"total_events"`
+	TotalTickets   int64               `json:"total_tickets"`
+	TotalDiscounts float64             `json:"total_discounts"`
+	TotalFees      float64             `json:"total_fees"`
+	TotalRefunds   float64             `json:"total_refunds"`
+	TotalRevenue   float64             `json:"total_revenue"` // Net of discounts and refunds
+	EventSummary   []EventSalesSummary `json:"event_summary"`
+}
+
+// CategorySalesSummary represents sales data for the events of one category.
+// Events in several categories count towards each of them.
+type CategorySalesSummary struct {
+	CategoryID     uint    `json:"category_id"` // 0 for events without a category
+	CategoryName   string  `json:"category_name"`
+	TotalEvents    int64   `json:"total_events"`
+	TotalTickets   int64   `json:"total_tickets"`
+	TotalDiscounts float64 `json:"total_discounts"`
+	TotalFees      float64 `json:"total_fees"`
+	TotalRefunds   float64 `json:"total_refunds"`
+	TotalRevenue   float64 `json:"total_revenue"` // Net of discounts and refunds
+}